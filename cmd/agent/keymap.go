@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aiknow/acc_jabra_agent/internal/actions"
+)
+
+const keymapUsage = `uso: agent keymap <comando> [arquivo]
+
+comandos:
+  validate [arquivo]  reporta os erros que impedem a carga do keymap
+  lint [arquivo]      reporta erros e avisos (entradas inalcançáveis, exec perigoso)
+  schema              imprime o JSON Schema do keymap.json
+
+sem arquivo, usa config/keymap.json
+`
+
+// runKeymapCommand executa o subcomando "keymap" da linha de comando e
+// retorna o código de saída: 0 sem erros, 1 com erros, 2 em uso incorreto
+func runKeymapCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, keymapUsage)
+		return 2
+	}
+
+	switch args[0] {
+	case "schema":
+		schema, err := actions.Schema()
+		if err != nil {
+			fmt.Fprintf(stderr, "keymap schema: %v\n", err)
+			return 1
+		}
+		stdout.Write(schema)
+		return 0
+
+	case "validate", "lint":
+		path := getConfigPath("keymap.json")
+		if len(args) > 1 {
+			path = args[1]
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "keymap %s: %v\n", args[0], err)
+			return 1
+		}
+
+		findings := actions.LintKeyMap(data)
+		for _, f := range findings {
+			if args[0] == "validate" && f.Severity != actions.SeverityError {
+				continue
+			}
+			fmt.Fprintf(stdout, "%s:%s\n", path, f)
+		}
+		if actions.HasErrors(findings) {
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprint(stderr, keymapUsage)
+	return 2
+}
@@ -1,15 +1,24 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/aiknow/acc_jabra_agent/internal/actions"
 	"github.com/aiknow/acc_jabra_agent/internal/api"
+	"github.com/aiknow/acc_jabra_agent/internal/audio"
 	"github.com/aiknow/acc_jabra_agent/internal/autostart"
 	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
 	"github.com/aiknow/acc_jabra_agent/internal/jabra"
 	"github.com/aiknow/acc_jabra_agent/internal/security"
 	"github.com/aiknow/acc_jabra_agent/internal/socket"
@@ -19,14 +28,22 @@ import (
 
 // App contém todas as dependências da aplicação
 type App struct {
-	Port     string
-	Store    *db.Store
-	Monitor  *jabra.Monitor
-	Server   *api.Server
-	Socket   *socket.Client
-	Executor *actions.Executor
+	Port      string
+	Store     *db.Store
+	Driver    jabra.Driver
+	Monitor   *jabra.Monitor
+	Server    *api.Server
+	Socket    *socket.Client
+	Executor  *actions.Executor
 	Whitelist *security.Whitelist
-	WinChan  chan string
+	Bus       *events.Bus
+	Audio     *audio.Bridge
+	WinChan   chan string
+
+	// Contexto raiz dos workers de segundo plano, cancelado no cleanup
+	ctx      context.Context
+	cancel   context.CancelFunc
+	stopOnce sync.Once
 }
 
 // SocketConfig representa a configuração do Socket.IO
@@ -40,6 +57,11 @@ type SocketConfig struct {
 var app *App
 
 func main() {
+	// Subcomando de linha de comando: não inicia o agente
+	if len(os.Args) > 1 && os.Args[1] == "keymap" {
+		os.Exit(runKeymapCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 	log.Println("[ACC-Jabra] Iniciando aplicação...")
 
@@ -47,6 +69,10 @@ func main() {
 		Port:    getEnvOrDefault("PORT", "18888"),
 		WinChan: make(chan string, 1),
 	}
+	app.ctx, app.cancel = context.WithCancel(context.Background())
+
+	// Barramento de eventos entre driver, executor, whitelist, store, API e socket
+	app.Bus = events.NewBus()
 
 	// 1. Inicializa Persistência (SQLite)
 	var err error
@@ -56,6 +82,7 @@ func main() {
 		log.Fatalf("[ACC-Jabra] Erro ao iniciar banco: %v", err)
 	}
 	log.Println("[ACC-Jabra] Banco de dados inicializado")
+	app.Store.SubscribeEvents(app.Bus)
 
 	// 2. Inicializa Whitelist de dispositivos
 	whitelistPath := getConfigPath("allowed_devices.json")
@@ -64,19 +91,38 @@ func main() {
 		log.Printf("[ACC-Jabra] Aviso: Whitelist não carregado: %v", err)
 	}
 
-	// 3. Inicializa o monitor de hardware
+	// 3. Inicializa o driver da plataforma e o monitor de hardware
+	// Modelos adicionais/corrigidos do catálogo de produtos (opcional)
+	if err := jabra.DefaultCatalog().LoadFile(getConfigPath("products.json")); err != nil && !os.IsNotExist(err) {
+		log.Printf("[ACC-Jabra] Aviso: Catálogo de produtos não carregado: %v", err)
+	}
+
+	driverConfig := jabra.DefaultConfig()
+	driverConfig.CaptureFile = os.Getenv("JABRA_CAPTURE_FILE")
+	app.Driver, err = newDriver(driverConfig)
+	if err != nil && usesDevDriver() {
+		// Simulação/replay mal configurados: volta para o hardware real
+		log.Printf("[ACC-Jabra] Aviso: Driver de simulação/replay indisponível: %v", err)
+		app.Driver, err = jabra.NewPlatformDriver(driverConfig)
+	}
+	if err != nil {
+		log.Printf("[ACC-Jabra] Aviso: Driver de hardware indisponível: %v", err)
+		app.Driver = nil
+	}
+
 	serialNumber := app.Store.GetSetting("device_serial", "")
-	app.Monitor = jabra.NewMonitor(serialNumber, app.Store)
+	app.Monitor = jabra.NewMonitor(app.Driver, serialNumber, app.Store)
+	app.Monitor.SetEventBus(app.Bus)
 	log.Println("[ACC-Jabra] Monitor de hardware inicializado")
 
-	// 4. Inicializa executor de ações (keymap)
-	keymapPath := getConfigPath("keymap.json")
-	app.Executor, err = actions.NewExecutor(keymapPath)
-	if err != nil {
-		log.Printf("[ACC-Jabra] Aviso: Executor usando keymap padrão: %v", err)
+	// Dispositivos conectados passam pela whitelist (soft-block via driver)
+	if app.Whitelist != nil && app.Driver != nil {
+		app.Whitelist.SubscribeEvents(app.Bus, app.Driver)
+		app.Whitelist.StartEnforcement(app.Driver)
 	}
 
-	// 5. Inicializa cliente Socket.IO
+	// 4. Inicializa cliente Socket.IO antes do executor e do monitor, que
+	// leem app.Socket (AgentState) a partir dos workers
 	socketConfig := loadSocketConfig()
 	if socketConfig.Host != "" {
 		app.Socket = socket.NewClient(socket.Config{
@@ -86,13 +132,42 @@ func main() {
 			Ramal: socketConfig.Ramal,
 		})
 
-		// Conecta executor ao socket
-		app.Executor.SetSocketEmitter(app.Socket)
+		// Eventos recebidos vão para o barramento
+		app.Socket.SetEventBus(app.Bus)
+	}
+
+	// 5. Inicializa executor de ações (keymap)
+	keymapPath := getConfigPath("keymap.json")
+	app.Executor, err = actions.NewExecutor(keymapPath)
+	if err != nil {
+		log.Printf("[ACC-Jabra] Aviso: Executor usando keymap padrão: %v", err)
+	}
+	if app.Executor != nil {
+		app.Executor.SetGestureConfig(loadGestureConfig())
+		app.Executor.SetVolumeController(app.Monitor)
+		app.Executor.SetBusylightController(app.Monitor)
+		app.Executor.SetStateProvider(app)
+		if app.Socket != nil {
+			app.Executor.SetSocketEmitter(app.Socket)
+		}
+		app.Executor.SubscribeEvents(app.Bus)
+
+		// Alterações no keymap.json valem sem reiniciar o agente
+		if err := app.Executor.WatchKeyMap(app.ctx, 2*time.Second); err != nil {
+			log.Printf("[ACC-Jabra] Erro ao observar keymap: %v", err)
+		}
+	}
 
-		// Registra callbacks do Socket
-		registerSocketCallbacks()
+	// Inicia driver e workers do monitor com os assinantes já registrados
+	if err := app.Monitor.Start(app.ctx); err != nil {
+		log.Printf("[ACC-Jabra] Erro ao iniciar monitor: %v", err)
+	}
 
-		// Conecta ao servidor
+	// Mute do microfone do SO sincronizado com o headset (PipeWire/PulseAudio)
+	startAudioBridge()
+
+	// 6. Conecta ao servidor Socket.IO
+	if app.Socket != nil {
 		go func() {
 			if err := app.Socket.Connect(); err != nil {
 				log.Printf("[ACC-Jabra] Erro ao conectar Socket.IO: %v", err)
@@ -100,22 +175,25 @@ func main() {
 		}()
 	}
 
-	// 6. Inicia o Servidor API/Web em background
+	// 7. Inicia o Servidor API/Web em background
 	app.Server = api.NewServer(app.Monitor, app.Store)
+	if app.Executor != nil {
+		app.Server.SetExecutor(app.Executor)
+	}
 	go func() {
 		log.Printf("[ACC-Jabra] Iniciando servidor na porta %s", app.Port)
-		if err := app.Server.Start(app.Port); err != nil {
+		if err := app.Server.Start(app.Port); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Fatalf("[ACC-Jabra] Erro no servidor: %v", err)
 		}
 	}()
 
-	// 7. Configura Autostart
+	// 8. Configura Autostart
 	setupAutostart()
 
-	// 8. Worker para abrir janelas
+	// 9. Worker para abrir janelas
 	go windowWorker()
 
-	// 9. Inicia Systray (bloqueia)
+	// 10. Inicia Systray (bloqueia)
 	log.Println("[ACC-Jabra] Iniciando System Tray...")
 	systray.Run(onReady, onExit)
 }
@@ -162,19 +240,55 @@ func onExit() {
 	cleanup()
 }
 
+// cleanup encerra os componentes na ordem inversa da inicialização. É
+// chamado pelo menu Sair e pelo onExit; apenas a primeira chamada atua.
 func cleanup() {
-	// Fecha Socket.IO
-	if app.Socket != nil {
-		app.Socket.Disconnect()
-	}
+	app.stopOnce.Do(func() {
+		// Cancela o contexto raiz dos workers
+		app.cancel()
+
+		// Para de aceitar requisições da API
+		if app.Server != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if err := app.Server.Shutdown(ctx); err != nil {
+				log.Printf("[ACC-Jabra] Erro ao encerrar servidor: %v", err)
+			}
+			cancel()
+		}
 
-	// Para whitelist enforcement
-	if app.Whitelist != nil {
-		app.Whitelist.StopEnforcement()
-	}
+		// Fecha Socket.IO
+		if app.Socket != nil {
+			app.Socket.Disconnect()
+		}
+
+		// Cancela gestos pendentes
+		if app.Executor != nil {
+			app.Executor.Stop()
+		}
+
+		// Para a sincronização de mute com o SO
+		if app.Audio != nil {
+			app.Audio.Stop()
+		}
+
+		// Para o monitor, seus workers e o driver de hardware
+		if app.Monitor != nil {
+			if err := app.Monitor.Stop(); err != nil {
+				log.Printf("[ACC-Jabra] Erro ao parar monitor: %v", err)
+			}
+		}
+
+		// Para whitelist enforcement
+		if app.Whitelist != nil {
+			app.Whitelist.StopEnforcement()
+		}
 
-	// Fecha canal de janelas
-	close(app.WinChan)
+		// Entrega os eventos pendentes aos assinantes e encerra o barramento
+		app.Bus.Close()
+
+		// Fecha canal de janelas
+		close(app.WinChan)
+	})
 }
 
 func windowWorker() {
@@ -248,33 +362,6 @@ func toggleAutostart(menuItem *systray.MenuItem) {
 	}
 }
 
-func registerSocketCallbacks() {
-	if app.Socket == nil {
-		return
-	}
-
-	app.Socket.OnConnectionChange(func(connected bool) {
-		if connected {
-			log.Println("[ACC-Jabra] Socket.IO conectado")
-		} else {
-			log.Println("[ACC-Jabra] Socket.IO desconectado")
-		}
-	})
-
-	app.Socket.OnNotificarCarro(func(temCarro bool) {
-		log.Printf("[ACC-Jabra] Evento: notificar_carro = %v", temCarro)
-		// TODO: Tocar som, mostrar notificação, etc.
-	})
-
-	app.Socket.OnLigacaoAtendida(func(ramalQueAtendeu string) {
-		log.Printf("[ACC-Jabra] Evento: ligacao_atendida por ramal %s", ramalQueAtendeu)
-	})
-
-	app.Socket.OnLigacaoInterna(func(ramalQueSolicitou string, temCarro bool) {
-		log.Printf("[ACC-Jabra] Evento: ligacao_interna de %s (carro: %v)", ramalQueSolicitou, temCarro)
-	})
-}
-
 func loadSocketConfig() SocketConfig {
 	config := SocketConfig{}
 
@@ -299,6 +386,87 @@ func loadSocketConfig() SocketConfig {
 	return config
 }
 
+// loadGestureConfig lê os tempos de gesto (em ms) das settings do banco:
+// gesture_double_ms, gesture_long_ms e gesture_repeat_ms
+func loadGestureConfig() actions.GestureConfig {
+	config := actions.DefaultGestureConfig()
+	settings := []struct {
+		key   string
+		value *time.Duration
+	}{
+		{"gesture_double_ms", &config.DoubleTapWindow},
+		{"gesture_long_ms", &config.LongPressDelay},
+		{"gesture_repeat_ms", &config.RepeatInterval},
+	}
+	for _, setting := range settings {
+		raw := app.Store.GetSetting(setting.key, "")
+		if raw == "" {
+			continue
+		}
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms <= 0 {
+			log.Printf("[ACC-Jabra] Aviso: %s inválido: %q", setting.key, raw)
+			continue
+		}
+		*setting.value = time.Duration(ms) * time.Millisecond
+	}
+	return config
+}
+
+// startAudioBridge inicia a sincronização de mute com o sistema de áudio.
+// A setting audio_backend escolhe o backend: auto (padrão), pactl, wpctl ou
+// off para desativar.
+func startAudioBridge() {
+	name := app.Store.GetSetting("audio_backend", "auto")
+	if name == "off" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(app.ctx, 5*time.Second)
+	backend, err := audio.Detect(ctx, audio.ExecRunner{}, name)
+	cancel()
+	if err != nil {
+		log.Printf("[ACC-Jabra] Aviso: Sincronização de mute com o SO desativada: %v", err)
+		return
+	}
+
+	app.Audio = audio.NewBridge(backend, app.Monitor)
+	app.Audio.SubscribeEvents(app.Bus)
+	if err := app.Audio.Start(app.ctx); err != nil {
+		log.Printf("[ACC-Jabra] Erro ao iniciar sincronização de mute: %v", err)
+		return
+	}
+	log.Printf("[ACC-Jabra] Mute sincronizado com o microfone do SO (%s)", backend.Name())
+}
+
+// AgentState reúne o estado avaliado nas regras condicionais e inserido
+// nos templates do keymap: chamada, mute, conexão e identificação do
+// headset principal, Socket.IO (ramal e token) e operador
+func (a *App) AgentState() actions.AgentState {
+	telemetry := a.Monitor.GetTelemetry()
+	state := actions.AgentState{
+		CallState:  telemetry.State.Call.State,
+		Muted:      telemetry.State.IsMuted,
+		Connection: telemetry.State.Connection,
+		Socket:     "disconnected",
+		Operator:   a.Store.GetSetting("operator_name", "Operador 01"),
+		Serial:     telemetry.Serial,
+		Device:     telemetry.Device,
+	}
+	if state.Connection == "" {
+		state.Connection = "offline"
+	}
+	if a.Socket != nil {
+		if a.Socket.IsConnected() {
+			state.Socket = "connected"
+		}
+		config := a.Socket.GetConfig()
+		state.Ramal = config.Ramal
+		state.Token = config.Token
+	}
+	return state
+}
+
 func getConfigPath(filename string) string {
 	// Primeiro tenta no diretório config/ relativo ao executável
 	execPath, _ := os.Executable()
@@ -319,6 +487,48 @@ func getConfigPath(filename string) string {
 	return filepath.Join("config", filename)
 }
 
+// newDriver cria o driver de hardware. Com JABRA_REPLAY_FILE definido,
+// reproduz uma captura HID (JABRA_REPLAY_SPEED, padrão 1 = tempo real);
+// com JABRA_SIMULATION=1 ou JABRA_SIM_SCENARIO definido, usa o driver
+// simulado em vez de acessar o hardware.
+func newDriver(config jabra.DriverConfig) (jabra.Driver, error) {
+	if scenarioFile := os.Getenv("JABRA_SIM_SCENARIO"); scenarioFile != "" {
+		scenario, err := jabra.LoadSimScenario(scenarioFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("[ACC-Jabra] Modo simulação: cenário %s", scenarioFile)
+		return jabra.NewSimulationDriver(scenario), nil
+	}
+	if os.Getenv("JABRA_SIMULATION") == "1" {
+		log.Println("[ACC-Jabra] Modo simulação: cenário padrão")
+		return jabra.NewSimulationDriver(jabra.DefaultSimScenario()), nil
+	}
+
+	replayFile := os.Getenv("JABRA_REPLAY_FILE")
+	if replayFile == "" {
+		return jabra.NewPlatformDriver(config)
+	}
+
+	speed, err := strconv.ParseFloat(getEnvOrDefault("JABRA_REPLAY_SPEED", "1"), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JABRA_REPLAY_SPEED: %w", err)
+	}
+	driver, err := jabra.OpenReplayDriver(replayFile, speed)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[ACC-Jabra] Reproduzindo captura HID %s (%gx)", replayFile, speed)
+	return driver, nil
+}
+
+// usesDevDriver retorna true se o driver foi trocado pelo simulador ou pelo
+// replay de uma captura HID
+func usesDevDriver() bool {
+	return os.Getenv("JABRA_SIM_SCENARIO") != "" || os.Getenv("JABRA_SIMULATION") == "1" ||
+		os.Getenv("JABRA_REPLAY_FILE") != ""
+}
+
 func getEnvOrDefault(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
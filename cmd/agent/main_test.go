@@ -0,0 +1,20 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+func TestNewDriverMissingReplayFile(t *testing.T) {
+	t.Setenv("JABRA_REPLAY_FILE", filepath.Join(t.TempDir(), "nao-existe.jsonl"))
+
+	driver, err := newDriver(jabra.DefaultConfig())
+	if err == nil {
+		t.Fatal("newDriver() erro esperado para captura inexistente")
+	}
+	if driver != nil {
+		t.Errorf("newDriver() = %#v, want interface nil", driver)
+	}
+}
@@ -1,17 +1,22 @@
 package actions
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+	"github.com/aiknow/acc_jabra_agent/internal/supervisor"
 	"github.com/gen2brain/beeep"
 )
 
@@ -24,40 +29,143 @@ const (
 	ActionSocketEmit ActionType = "socket_emit" // Emite evento via Socket.IO
 	ActionNotify     ActionType = "notify"      // Mostra notificação do sistema
 	ActionPlaySound  ActionType = "play_sound"  // Reproduz som
+	ActionVolume     ActionType = "volume"      // Ajusta o volume do headset
+	ActionBusylight  ActionType = "busylight"   // Liga/desliga o LED de ocupado
+	ActionSequence   ActionType = "sequence"    // Executa passos em ordem (macro)
 	ActionNone       ActionType = "none"        // Não faz nada
 )
 
 // Action define uma ação a ser executada quando um botão é pressionado
 type Action struct {
-	Type    ActionType `json:"action"`
-	URL     string     `json:"url,omitempty"`     // Para api_call
-	Method  string     `json:"method,omitempty"`  // Para api_call (GET, POST)
-	Body    string     `json:"body,omitempty"`    // Para api_call POST
-	Command string     `json:"cmd,omitempty"`     // Para exec
-	Event   string     `json:"event,omitempty"`   // Para socket_emit
-	Message string     `json:"message,omitempty"` // Para notify
-	Title   string     `json:"title,omitempty"`   // Para notify
-	Sound   string     `json:"sound,omitempty"`   // Para play_sound (path do arquivo)
-}
-
-// KeyMap mapeia IDs de botão para ações
+	Type    ActionType  `json:"action"`
+	URL     string      `json:"url,omitempty"`      // Para api_call
+	Method  string      `json:"method,omitempty"`   // Para api_call (GET, POST)
+	Body    string      `json:"body,omitempty"`     // Para api_call POST
+	Command string      `json:"cmd,omitempty"`      // Para exec
+	Event   string      `json:"event,omitempty"`    // Para socket_emit
+	Message string      `json:"message,omitempty"`  // Para notify
+	Title   string      `json:"title,omitempty"`    // Para notify
+	Sound   string      `json:"sound,omitempty"`    // Para play_sound (path do arquivo)
+	Step    int         `json:"step,omitempty"`     // Para volume (negativo diminui)
+	Light   string      `json:"light,omitempty"`    // Para busylight (on, off)
+	Steps   []Step      `json:"steps,omitempty"`    // Para sequence
+	OnError ErrorPolicy `json:"on_error,omitempty"` // Para sequence (stop, continue)
+	Rules   []Rule      `json:"rules,omitempty"`    // Regras condicionais; a ação acima é o padrão
+}
+
+// Valores de "light" da ação busylight
+const (
+	LightOn  = "on"
+	LightOff = "off"
+)
+
+// KeyMap mapeia IDs de botão para ações. Chaves simples ("Mute") executam
+// no press; chaves com gesto ("Mute:long", "Flash:double") executam quando
+// o gesto é reconhecido. Chaves "Wear:on"/"Wear:off" reagem ao sensor de
+// uso, opcionalmente restritas a um estado de chamada ("Wear:off:idle").
 type KeyMap map[string]Action
 
+// WearButton é o prefixo das chaves de keymap do sensor de uso
+const WearButton = "Wear"
+
+// WearKeys retorna as chaves do keymap para uma mudança do sensor de uso,
+// da mais específica (com estado de chamada) para a genérica
+func WearKeys(worn bool, callState string) []string {
+	key := WearButton + ":off"
+	if worn {
+		key = WearButton + ":on"
+	}
+	if callState == "" {
+		return []string{key}
+	}
+	return []string{key + ":" + callState, key}
+}
+
 // SocketEmitter interface para emitir eventos Socket.IO
 type SocketEmitter interface {
 	EmitClick(button string) error
 }
 
+// VolumeController aplica os passos de volume no dispositivo do evento ou,
+// sem ele, no dispositivo principal; applied é false quando o passo é
+// ignorado (sem suporte a volume ou volume ajustado pelo próprio dispositivo)
+type VolumeController interface {
+	StepVolume(deviceID uint16, delta int, source string) (level int, applied bool, err error)
+	StepPrimaryVolume(delta int, source string) (level int, applied bool, err error)
+}
+
+// BusylightController liga e desliga o LED de ocupado do dispositivo do
+// evento ou, sem ele, do dispositivo principal
+type BusylightController interface {
+	SetBusylight(deviceID uint16, on bool, source string) error
+	SetPrimaryBusylight(on bool, source string) error
+}
+
+// deviceKey guarda no contexto da execução o dispositivo que gerou o evento
+type deviceKey struct{}
+
+// withDevice associa o dispositivo do evento à execução: as ações volume e
+// busylight atuam nele em vez do dispositivo principal
+func withDevice(ctx context.Context, deviceID uint16) context.Context {
+	return context.WithValue(ctx, deviceKey{}, deviceID)
+}
+
+// eventDevice retorna o dispositivo do evento da execução, se houver
+func eventDevice(ctx context.Context) (uint16, bool) {
+	deviceID, ok := ctx.Value(deviceKey{}).(uint16)
+	return deviceID, ok
+}
+
 // Executor gerencia a execução de ações baseado em eventos de botão
 type Executor struct {
-	mu       sync.RWMutex
-	keyMap   KeyMap
-	filePath string
-	socket   SocketEmitter
+	mu        sync.RWMutex
+	keyMap    KeyMap
+	filePath  string
+	socket    SocketEmitter
+	volume    VolumeController
+	busylight BusylightController
+	state     StateProvider
+	now       func() time.Time
 
 	// Debounce para evitar execuções duplicadas
 	lastExecution map[string]time.Time
 	debounceTime  time.Duration
+
+	// Reconhecimento de gestos para chaves "Botão:gesto" e o dispositivo do
+	// último evento de cada botão, usado na execução do gesto
+	gestures       *GestureRecognizer
+	gestureDevices map[string]uint16
+
+	// Recarga do keymap: estado exposto na API e versão do arquivo já lida
+	status    KeyMapStatus
+	fileStamp fileStamp
+
+	watchMu     sync.Mutex
+	watchCancel context.CancelFunc
+	watchers    *supervisor.Supervisor
+
+	// Sequências em execução (por chave), canceladas em Stop
+	ctx       context.Context
+	cancel    context.CancelFunc
+	running   map[string]bool
+	sequences sync.WaitGroup
+}
+
+// KeyMapStatus descreve o keymap em uso e a última versão rejeitada
+type KeyMapStatus struct {
+	Path     string     `json:"path"`
+	Default  bool       `json:"default,omitempty"` // Usando DefaultKeyMap (arquivo ausente)
+	Mappings int        `json:"mappings"`
+	LoadedAt time.Time  `json:"loaded_at"`
+	Error    string     `json:"error,omitempty"`    // Por que a última versão do arquivo foi rejeitada
+	Problems []Problem  `json:"problems,omitempty"` // Problemas de validação dessa versão
+	ErrorAt  *time.Time `json:"error_at,omitempty"`
+}
+
+// fileStamp identifica uma versão do arquivo de keymap
+type fileStamp struct {
+	modTime time.Time
+	size    int64
 }
 
 // NewExecutor cria um novo executor de ações
@@ -67,13 +175,20 @@ func NewExecutor(keymapPath string) (*Executor, error) {
 		filePath:      keymapPath,
 		lastExecution: make(map[string]time.Time),
 		debounceTime:  200 * time.Millisecond,
+		running:       make(map[string]bool),
+		now:           time.Now,
+
+		gestureDevices: make(map[string]uint16),
 	}
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	e.gestures = NewGestureRecognizer(DefaultGestureConfig(), e.executeGesture, e.hasDoubleTap)
 
 	if keymapPath != "" {
 		if err := e.LoadKeyMap(keymapPath); err != nil {
 			// Se arquivo não existe, usa keymap padrão
 			if os.IsNotExist(err) {
 				e.keyMap = DefaultKeyMap()
+				e.status = KeyMapStatus{Path: keymapPath, Default: true, LoadedAt: time.Now()}
 				log.Printf("[Actions] Usando keymap padrão, arquivo não encontrado: %s", keymapPath)
 			} else {
 				return nil, err
@@ -81,6 +196,7 @@ func NewExecutor(keymapPath string) (*Executor, error) {
 		}
 	} else {
 		e.keyMap = DefaultKeyMap()
+		e.status = KeyMapStatus{Default: true, LoadedAt: time.Now()}
 	}
 
 	return e, nil
@@ -99,12 +215,12 @@ func DefaultKeyMap() KeyMap {
 			Message: "Mute ativado",
 		},
 		"VolumeUp": {
-			Type:    ActionNone,
-			Message: "Volume aumentado",
+			Type: ActionVolume,
+			Step: 5,
 		},
 		"VolumeDown": {
-			Type:    ActionNone,
-			Message: "Volume diminuído",
+			Type: ActionVolume,
+			Step: -5,
 		},
 		"HookSwitch": {
 			Type:  ActionSocketEmit,
@@ -113,27 +229,133 @@ func DefaultKeyMap() KeyMap {
 	}
 }
 
-// LoadKeyMap carrega o mapeamento de um arquivo JSON
+// LoadKeyMap carrega e valida o mapeamento de um arquivo JSON. Um arquivo
+// inválido é rejeitado e o keymap em uso é mantido; o erro fica em
+// KeyMapStatus até a próxima carga bem-sucedida.
 func (e *Executor) LoadKeyMap(path string) error {
+	var stamp fileStamp
+	if info, err := os.Stat(path); err == nil {
+		stamp = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	var km KeyMap
-	if err := json.Unmarshal(data, &km); err != nil {
-		return fmt.Errorf("invalid keymap JSON: %w", err)
+	km, err := ParseKeyMap(data)
+	if err != nil {
+		e.mu.Lock()
+		e.fileStamp = stamp
+		now := time.Now()
+		e.status.Error = err.Error()
+		e.status.Problems = nil
+		var invalid *ValidationError
+		if errors.As(err, &invalid) {
+			e.status.Problems = invalid.Problems
+		}
+		e.status.ErrorAt = &now
+		e.mu.Unlock()
+		return err
 	}
 
 	e.mu.Lock()
 	e.keyMap = km
 	e.filePath = path
+	e.fileStamp = stamp
+	e.status = KeyMapStatus{Path: path, LoadedAt: time.Now()}
 	e.mu.Unlock()
 
 	log.Printf("[Actions] KeyMap carregado: %d mapeamentos", len(km))
 	return nil
 }
 
+// ParseKeyMap decodifica e valida um keymap JSON
+func ParseKeyMap(data []byte) (KeyMap, error) {
+	var km KeyMap
+	if err := json.Unmarshal(data, &km); err != nil {
+		return nil, fmt.Errorf("invalid keymap JSON: %w", err)
+	}
+	if err := km.Validate(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+// ReloadKeyMap recarrega o arquivo de keymap em uso
+func (e *Executor) ReloadKeyMap() error {
+	e.mu.RLock()
+	path := e.filePath
+	e.mu.RUnlock()
+
+	if path == "" {
+		return errors.New("no keymap file configured")
+	}
+	if err := e.LoadKeyMap(path); err != nil {
+		log.Printf("[Actions] KeyMap rejeitado, mantendo o anterior: %v", err)
+		return err
+	}
+	return nil
+}
+
+// KeyMapStatus retorna o estado do keymap em uso e da última recarga
+func (e *Executor) KeyMapStatus() KeyMapStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	status := e.status
+	status.Mappings = len(e.keyMap)
+	status.Problems = slices.Clone(status.Problems)
+	return status
+}
+
+// WatchKeyMap recarrega o arquivo de keymap sempre que ele muda, verificando
+// a cada interval até ctx ser cancelado ou Stop ser chamado
+func (e *Executor) WatchKeyMap(ctx context.Context, interval time.Duration) error {
+	e.watchMu.Lock()
+	defer e.watchMu.Unlock()
+
+	if e.watchCancel != nil {
+		return errors.New("keymap watcher already started")
+	}
+	ctx, e.watchCancel = context.WithCancel(ctx)
+	e.watchers = supervisor.New("Actions")
+	e.watchers.Go(ctx, "keymap_watcher", func(ctx context.Context) error {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+				e.checkKeyMapFile()
+			}
+		}
+	})
+	return nil
+}
+
+// checkKeyMapFile recarrega o keymap se o arquivo mudou desde a última leitura
+func (e *Executor) checkKeyMapFile() {
+	e.mu.RLock()
+	path := e.filePath
+	last := e.fileStamp
+	e.mu.RUnlock()
+
+	if path == "" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		// Arquivo removido ou em substituição: mantém o keymap em uso
+		return
+	}
+	if info.ModTime().Equal(last.modTime) && info.Size() == last.size {
+		return
+	}
+	log.Printf("[Actions] KeyMap alterado, recarregando: %s", path)
+	e.ReloadKeyMap()
+}
+
 // SaveKeyMap salva o mapeamento atual para arquivo
 func (e *Executor) SaveKeyMap(path string) error {
 	e.mu.RLock()
@@ -154,9 +376,153 @@ func (e *Executor) SetSocketEmitter(socket SocketEmitter) {
 	e.socket = socket
 }
 
-// Execute executa a ação mapeada para um botão
+// SetVolumeController define quem aplica as ações de volume
+func (e *Executor) SetVolumeController(volume VolumeController) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.volume = volume
+}
+
+// SetBusylightController define quem aplica as ações de busylight
+func (e *Executor) SetBusylightController(busylight BusylightController) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.busylight = busylight
+}
+
+// SetStateProvider define de onde vem o estado do agente avaliado nas
+// regras condicionais. Sem ele apenas as condições de horário e dia casam.
+func (e *Executor) SetStateProvider(state StateProvider) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.state = state
+}
+
+// SetGestureConfig altera os tempos do reconhecimento de gestos
+func (e *Executor) SetGestureConfig(config GestureConfig) {
+	e.gestures.SetConfig(config)
+}
+
+// GestureConfig retorna os tempos do reconhecimento de gestos
+func (e *Executor) GestureConfig() GestureConfig {
+	return e.gestures.Config()
+}
+
+// Stop cancela gestos pendentes (timers de duplo toque/pressão longa) e
+// sequências em execução e encerra a observação do arquivo de keymap
+func (e *Executor) Stop() {
+	e.gestures.Stop()
+	e.cancel()
+	e.sequences.Wait()
+
+	e.watchMu.Lock()
+	defer e.watchMu.Unlock()
+	if e.watchCancel != nil {
+		e.watchCancel()
+		e.watchers.Wait()
+		e.watchCancel = nil
+	}
+}
+
+// SubscribeEvents executa o keymap para os eventos de botão e do sensor de
+// uso publicados no barramento. A fila é grande o bastante para rajadas de gestos; com ela
+// cheia os eventos novos são descartados.
+func (e *Executor) SubscribeEvents(bus *events.Bus) *events.Subscription {
+	return bus.Handle(events.Options{
+		Name:   "executor",
+		Buffer: 128,
+		Policy: events.DropNewest,
+		Kinds:  []events.Kind{events.KindButton, events.KindWear},
+	}, func(event events.Event) {
+		switch ev := event.(type) {
+		case events.Button:
+			if err := e.execute(withDevice(context.Background(), ev.DeviceID), ev.Button, ev.Pressed); err != nil {
+				log.Printf("[Actions] Erro ao executar ação de %s: %v", ev.Button, err)
+			}
+		case events.Wear:
+			if err := e.executeWear(withDevice(context.Background(), ev.DeviceID), ev.Worn, ev.CallState); err != nil {
+				log.Printf("[Actions] Erro ao executar ação do sensor de uso: %v", err)
+			}
+		}
+	})
+}
+
+// ExecuteWear executa a ação do keymap para o headset colocado/retirado.
+// "Wear:off:idle" tem precedência sobre "Wear:off"; sem mapeamento nada é
+// executado.
+func (e *Executor) ExecuteWear(worn bool, callState string) error {
+	return e.executeWear(context.Background(), worn, callState)
+}
+
+// executeWear executa a ação do sensor de uso; ctx traz o dispositivo do evento
+func (e *Executor) executeWear(ctx context.Context, worn bool, callState string) error {
+	e.mu.RLock()
+	var key string
+	var action Action
+	found := false
+	for _, k := range WearKeys(worn, callState) {
+		if action, found = e.keyMap[k]; found {
+			key = k
+			break
+		}
+	}
+	socket := e.socket
+	e.mu.RUnlock()
+
+	if !found {
+		return nil
+	}
+
+	log.Printf("[Actions] Executando ação para %s: %s", key, action.Type)
+	return e.run(ctx, key, action, socket, false)
+}
+
+// hasGestures retorna true se o keymap tem alguma chave de gesto para o botão
+func (e *Executor) hasGestures(buttonID string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	prefix := buttonID + ":"
+	for key := range e.keyMap {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDoubleTap retorna true se o botão tem entrada de duplo toque; sem ela
+// o tap é executado sem aguardar a janela de duplo toque
+func (e *Executor) hasDoubleTap(buttonID string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	_, ok := e.keyMap[GestureKey(buttonID, GestureDouble)]
+	return ok
+}
+
+// Execute processa um evento de botão. Botões com chaves de gesto passam
+// pelo reconhecimento de gestos e a ação simples vira o tap (executa uma
+// única vez, sem o double ou o long); os demais executam a ação simples
+// no press.
 func (e *Executor) Execute(buttonID string, pressed bool) error {
-	// Só executa no press, não no release (exceto se configurado diferente)
+	return e.execute(context.Background(), buttonID, pressed)
+}
+
+// execute processa um evento de botão; ctx traz o dispositivo do evento
+func (e *Executor) execute(ctx context.Context, buttonID string, pressed bool) error {
+	if e.hasGestures(buttonID) {
+		e.mu.Lock()
+		if deviceID, ok := eventDevice(ctx); ok {
+			e.gestureDevices[buttonID] = deviceID
+		} else {
+			delete(e.gestureDevices, buttonID)
+		}
+		e.mu.Unlock()
+		e.gestures.Feed(buttonID, pressed)
+		return nil
+	}
+
+	// Ações simples só executam no press, não no release
 	if !pressed {
 		return nil
 	}
@@ -183,18 +549,77 @@ func (e *Executor) Execute(buttonID string, pressed bool) error {
 	e.mu.Unlock()
 
 	log.Printf("[Actions] Executando ação para botão %s: %s", buttonID, action.Type)
+	return e.run(ctx, buttonID, action, socket, false)
+}
+
+// executeGesture executa a ação mapeada para um gesto reconhecido. Sem
+// chave "Botão:tap", o tap executa a ação simples do botão.
+func (e *Executor) executeGesture(buttonID string, gesture Gesture) {
+	key := GestureKey(buttonID, gesture)
+
+	e.mu.RLock()
+	action, ok := e.keyMap[key]
+	if !ok && gesture == GestureTap {
+		key = buttonID
+		action, ok = e.keyMap[key]
+	}
+	socket := e.socket
+	deviceID, hasDevice := e.gestureDevices[buttonID]
+	e.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	if hasDevice {
+		ctx = withDevice(ctx, deviceID)
+	}
+	log.Printf("[Actions] Executando ação para gesto %s: %s", key, action.Type)
+	if err := e.run(ctx, key, action, socket, false); err != nil {
+		log.Printf("[Actions] Erro ao executar %s: %v", key, err)
+	}
+}
+
+// run resolve as regras, expande os templates e executa a ação; key é
+// usada como evento padrão do socket_emit. Com wait (passos de sequência) o
+// exec aguarda o fim do comando.
+func (e *Executor) run(ctx context.Context, key string, action Action, socket SocketEmitter, wait bool) error {
+	if len(action.Rules) > 0 {
+		action = e.resolve(key, action)
+	}
 
+	// Templates dos campos ({{.ramal}}...); o token não aparece em logs e erros
+	action, redactor, err := e.expandTemplates(key, action)
+	if err != nil {
+		return err
+	}
+	err = e.dispatch(ctx, key, action, socket, wait, redactor)
+	if err != nil && redactor != nil {
+		return errors.New(redactor.Replace(err.Error()))
+	}
+	return err
+}
+
+// dispatch executa a ação já resolvida e expandida conforme o tipo
+func (e *Executor) dispatch(ctx context.Context, key string, action Action, socket SocketEmitter, wait bool, redactor *strings.Replacer) error {
 	switch action.Type {
 	case ActionAPICall:
-		return e.executeAPICall(action)
+		return e.executeAPICall(ctx, action, redactor)
 	case ActionExec:
-		return e.executeCommand(action)
+		return e.executeCommand(ctx, action, wait, redactor)
 	case ActionSocketEmit:
-		return e.executeSocketEmit(action, buttonID, socket)
+		return e.executeSocketEmit(action, key, socket)
 	case ActionNotify:
 		return e.executeNotify(action)
 	case ActionPlaySound:
 		return e.executePlaySound(action)
+	case ActionVolume:
+		return e.executeVolume(ctx, action)
+	case ActionBusylight:
+		return e.executeBusylight(ctx, action)
+	case ActionSequence:
+		return e.startSequence(ctx, key, action)
 	case ActionNone:
 		return nil
 	default:
@@ -203,24 +628,31 @@ func (e *Executor) Execute(buttonID string, pressed bool) error {
 }
 
 // executeAPICall faz uma chamada HTTP
-func (e *Executor) executeAPICall(action Action) error {
+func (e *Executor) executeAPICall(ctx context.Context, action Action, redactor *strings.Replacer) error {
 	method := action.Method
 	if method == "" {
 		method = "GET"
 	}
 
-	var resp *http.Response
+	var req *http.Request
 	var err error
 
 	switch strings.ToUpper(method) {
 	case "GET":
-		resp, err = http.Get(action.URL)
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, action.URL, nil)
 	case "POST":
-		resp, err = http.Post(action.URL, "application/json", strings.NewReader(action.Body))
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, action.URL, strings.NewReader(action.Body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
 	default:
 		return fmt.Errorf("unsupported HTTP method: %s", method)
 	}
+	if err != nil {
+		return fmt.Errorf("API call failed: %w", err)
+	}
 
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("API call failed: %w", err)
 	}
@@ -230,12 +662,13 @@ func (e *Executor) executeAPICall(action Action) error {
 		return fmt.Errorf("API call returned status %d", resp.StatusCode)
 	}
 
-	log.Printf("[Actions] API call para %s retornou %d", action.URL, resp.StatusCode)
+	log.Printf("[Actions] API call para %s retornou %d", redact(redactor, action.URL), resp.StatusCode)
 	return nil
 }
 
-// executeCommand executa um comando do sistema
-func (e *Executor) executeCommand(action Action) error {
+// executeCommand executa um comando do sistema. Sem wait o comando roda em
+// background; com wait aguarda o término (encerrado se ctx expirar).
+func (e *Executor) executeCommand(ctx context.Context, action Action, wait bool, redactor *strings.Replacer) error {
 	if action.Command == "" {
 		return fmt.Errorf("no command specified")
 	}
@@ -243,9 +676,20 @@ func (e *Executor) executeCommand(action Action) error {
 	var cmd *exec.Cmd
 
 	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/C", action.Command)
+		cmd = exec.CommandContext(ctx, "cmd", "/C", action.Command)
 	} else {
-		cmd = exec.Command("sh", "-c", action.Command)
+		cmd = exec.CommandContext(ctx, "sh", "-c", action.Command)
+	}
+
+	if wait {
+		if out, err := cmd.CombinedOutput(); err != nil {
+			if msg := strings.TrimSpace(string(out)); msg != "" {
+				return fmt.Errorf("command failed: %w: %s", err, msg)
+			}
+			return fmt.Errorf("command failed: %w", err)
+		}
+		log.Printf("[Actions] Comando concluído: %s", redact(redactor, action.Command))
+		return nil
 	}
 
 	// Executa em background
@@ -253,7 +697,7 @@ func (e *Executor) executeCommand(action Action) error {
 		return fmt.Errorf("command start failed: %w", err)
 	}
 
-	log.Printf("[Actions] Comando iniciado: %s", action.Command)
+	log.Printf("[Actions] Comando iniciado: %s", redact(redactor, action.Command))
 	return nil
 }
 
@@ -316,6 +760,68 @@ func (e *Executor) executePlaySound(action Action) error {
 	return cmd.Start()
 }
 
+// executeVolume ajusta o volume do headset do evento (ou do principal) pelo
+// passo da ação; passos ignorados pelo dispositivo não são erro
+func (e *Executor) executeVolume(ctx context.Context, action Action) error {
+	if action.Step == 0 {
+		return fmt.Errorf("no volume step specified")
+	}
+
+	e.mu.RLock()
+	volume := e.volume
+	e.mu.RUnlock()
+	if volume == nil {
+		return fmt.Errorf("volume controller not configured")
+	}
+
+	var level int
+	var applied bool
+	var err error
+	if deviceID, ok := eventDevice(ctx); ok {
+		level, applied, err = volume.StepVolume(deviceID, action.Step, "keymap")
+	} else {
+		level, applied, err = volume.StepPrimaryVolume(action.Step, "keymap")
+	}
+	if err != nil {
+		return fmt.Errorf("volume adjust failed: %w", err)
+	}
+	if !applied {
+		return nil
+	}
+
+	log.Printf("[Actions] Volume ajustado para %d", level)
+	return nil
+}
+
+// executeBusylight liga ou desliga o LED de ocupado do headset do evento
+// (ou do principal)
+func (e *Executor) executeBusylight(ctx context.Context, action Action) error {
+	if action.Light != LightOn && action.Light != LightOff {
+		return fmt.Errorf("invalid busylight light %q", action.Light)
+	}
+
+	e.mu.RLock()
+	busylight := e.busylight
+	e.mu.RUnlock()
+	if busylight == nil {
+		return fmt.Errorf("busylight controller not configured")
+	}
+
+	on := action.Light == LightOn
+	var err error
+	if deviceID, ok := eventDevice(ctx); ok {
+		err = busylight.SetBusylight(deviceID, on, "keymap")
+	} else {
+		err = busylight.SetPrimaryBusylight(on, "keymap")
+	}
+	if err != nil {
+		return fmt.Errorf("busylight failed: %w", err)
+	}
+
+	log.Printf("[Actions] Busylight: %s", action.Light)
+	return nil
+}
+
 // GetKeyMap retorna o mapeamento atual
 func (e *Executor) GetKeyMap() KeyMap {
 	e.mu.RLock()
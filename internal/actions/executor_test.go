@@ -0,0 +1,283 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+func TestExecutorSubscribeEvents(t *testing.T) {
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+
+	bus := events.NewBus()
+	e.SubscribeEvents(bus)
+	bus.Publish(events.Battery{DeviceID: 1, Level: 50})
+	bus.Publish(events.Button{DeviceID: 1, Button: "OffHook", Pressed: true})
+	bus.Publish(events.Button{DeviceID: 1, Button: "OffHook", Pressed: false})
+	bus.Close() // Aguarda o executor processar a fila
+
+	socket.mu.Lock()
+	defer socket.mu.Unlock()
+	if len(socket.events) != 1 || socket.events[0] != "click" {
+		t.Errorf("OffHook deveria emitir um click pelo keymap padrão: %v", socket.events)
+	}
+}
+
+func TestExecuteWear(t *testing.T) {
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+	e.SetAction("Wear:off:idle", Action{Type: ActionSocketEmit, Event: "ausente"})
+	e.SetAction("Wear:off", Action{Type: ActionSocketEmit, Event: "retirado"})
+
+	tests := []struct {
+		worn      bool
+		callState string
+		want      string // Evento emitido; vazio se nenhum
+	}{
+		{false, "idle", "ausente"},
+		{false, "in_call", "retirado"},
+		{true, "idle", ""},
+	}
+	for _, tt := range tests {
+		socket.mu.Lock()
+		socket.events = nil
+		socket.mu.Unlock()
+
+		if err := e.ExecuteWear(tt.worn, tt.callState); err != nil {
+			t.Fatalf("ExecuteWear(%v, %s) erro inesperado: %v", tt.worn, tt.callState, err)
+		}
+
+		socket.mu.Lock()
+		got := slices.Clone(socket.events)
+		socket.mu.Unlock()
+		if tt.want == "" && len(got) != 0 || tt.want != "" && (len(got) != 1 || got[0] != tt.want) {
+			t.Errorf("ExecuteWear(%v, %s) emitiu %v, want %q", tt.worn, tt.callState, got, tt.want)
+		}
+	}
+}
+
+// Depois de uma chamada encerrada o estado volta a idle e Wear:off:idle
+// continua valendo
+func TestExecuteWearAfterCallEnded(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "wear_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+	e.SetAction("Wear:off:idle", Action{Type: ActionSocketEmit, Event: "ausente"})
+
+	bus := events.NewBus()
+	sim := jabra.NewSimulationDriver(nil)
+	m := jabra.NewMonitor(sim, "", store)
+	m.SetEventBus(bus)
+	m.SetCallEndedDelay(20 * time.Millisecond)
+	e.SubscribeEvents(bus)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+
+	if err := sim.Execute(jabra.SimCommand{Action: jabra.SimAttach, Name: "Jabra Engage 55"}); err != nil {
+		t.Fatalf("attach erro inesperado: %v", err)
+	}
+	m.HandleCallEvent(jabra.CallEventAnswer, "teste")
+	m.HandleCallEvent(jabra.CallEventHangup, "teste")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for m.GetTelemetry().State.Call.State != "idle" {
+		if time.Now().After(deadline) {
+			t.Fatalf("chamada não voltou a idle: %s", m.GetTelemetry().State.Call.State)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := sim.Execute(jabra.SimCommand{Action: jabra.SimWear, Worn: false}); err != nil {
+		t.Fatalf("wear erro inesperado: %v", err)
+	}
+	m.Stop()
+	bus.Close() // Aguarda o executor processar a fila
+
+	socket.mu.Lock()
+	defer socket.mu.Unlock()
+	if !slices.Equal(socket.events, []string{"ausente"}) {
+		t.Errorf("Wear:off após a chamada emitiu %v, want [ausente]", socket.events)
+	}
+}
+
+// fakeVolume registra os passos de volume recebidos e o dispositivo de
+// cada um (-1 no dispositivo principal)
+type fakeVolume struct {
+	mu      sync.Mutex
+	level   int
+	deltas  []int
+	devices []int
+}
+
+func (f *fakeVolume) StepVolume(deviceID uint16, delta int, source string) (int, bool, error) {
+	return f.step(int(deviceID), delta)
+}
+
+func (f *fakeVolume) StepPrimaryVolume(delta int, source string) (int, bool, error) {
+	return f.step(-1, delta)
+}
+
+func (f *fakeVolume) step(device, delta int) (int, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deltas = append(f.deltas, delta)
+	f.devices = append(f.devices, device)
+	f.level = min(max(f.level+delta, 0), 100)
+	return f.level, true, nil
+}
+
+func TestExecuteVolume(t *testing.T) {
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+
+	if err := e.run(context.Background(), "VolumeUp", Action{Type: ActionVolume, Step: 5}, nil, false); err == nil {
+		t.Error("ação de volume sem controlador deveria falhar")
+	}
+
+	volume := &fakeVolume{level: 50}
+	e.SetVolumeController(volume)
+	if err := e.Execute("VolumeUp", true); err != nil {
+		t.Fatalf("Execute(VolumeUp) erro inesperado: %v", err)
+	}
+	if err := e.Execute("VolumeDown", true); err != nil {
+		t.Fatalf("Execute(VolumeDown) erro inesperado: %v", err)
+	}
+	if !slices.Equal(volume.deltas, []int{5, -5}) {
+		t.Errorf("ajustes de volume = %v, want [5 -5] pelo keymap padrão", volume.deltas)
+	}
+	if err := e.run(context.Background(), "VolumeUp", Action{Type: ActionVolume}, nil, false); err == nil {
+		t.Error("ação de volume sem step deveria falhar")
+	}
+}
+
+func TestExecuteVolumeOnEventDevice(t *testing.T) {
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	volume := &fakeVolume{level: 50}
+	e.SetVolumeController(volume)
+	e.SetAction("Flash", Action{Type: ActionSequence, Steps: []Step{
+		{Action: Action{Type: ActionVolume, Step: -10}},
+	}})
+
+	bus := events.NewBus()
+	e.SubscribeEvents(bus)
+	bus.Publish(events.Button{DeviceID: 2, Button: "VolumeUp", Pressed: true})
+	bus.Publish(events.Button{DeviceID: 3, Button: "Flash", Pressed: true})
+	bus.Close() // Aguarda o executor processar a fila
+
+	// A sequência roda em segundo plano
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		volume.mu.Lock()
+		n := len(volume.deltas)
+		volume.mu.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	volume.mu.Lock()
+	defer volume.mu.Unlock()
+	if !slices.Equal(volume.deltas, []int{5, -10}) || !slices.Equal(volume.devices, []int{2, 3}) {
+		t.Errorf("passos = %v nos dispositivos %v, want [5 -10] em [2 3]", volume.deltas, volume.devices)
+	}
+}
+
+func TestKeyMapHotReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keymap.json")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Erro ao escrever keymap: %v", err)
+		}
+	}
+	write(`{"Mute": {"action": "socket_emit", "event": "v1"}}`)
+
+	e, err := NewExecutor(path)
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	if err := e.WatchKeyMap(context.Background(), 10*time.Millisecond); err != nil {
+		t.Fatalf("WatchKeyMap() erro inesperado: %v", err)
+	}
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout aguardando %s: %+v", what, e.KeyMapStatus())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	event := func() string { return e.GetKeyMap()["Mute"].Event }
+
+	// Versão válida: trocada sem reiniciar
+	time.Sleep(20 * time.Millisecond) // Garante mtime diferente
+	write(`{"Mute": {"action": "socket_emit", "event": "v2"}, "Flash": {"action": "none"}}`)
+	waitFor("keymap v2", func() bool { return event() == "v2" })
+
+	// Versão inválida: rejeitada, v2 mantido e erro exposto
+	time.Sleep(20 * time.Millisecond)
+	write(`{"Mute": {"action": "socket_emit", "event": "v3"}, "Turbo": {"action": "exec"}}`)
+	waitFor("erro de validação", func() bool { return e.KeyMapStatus().Error != "" })
+	status := e.KeyMapStatus()
+	if event() != "v2" || status.Mappings != 2 || len(status.Problems) != 2 {
+		t.Errorf("keymap inválido deveria manter o anterior: evento %s, status %+v", event(), status)
+	}
+
+	// JSON quebrado também é rejeitado
+	time.Sleep(20 * time.Millisecond)
+	write(`{"Mute": `)
+	waitFor("erro de JSON", func() bool { return len(e.KeyMapStatus().Problems) == 0 })
+	if event() != "v2" {
+		t.Errorf("JSON inválido substituiu o keymap: %s", event())
+	}
+
+	// Correção limpa o erro
+	time.Sleep(20 * time.Millisecond)
+	write(`{"Mute": {"action": "socket_emit", "event": "v4"}}`)
+	waitFor("keymap v4", func() bool { return event() == "v4" })
+	if status := e.KeyMapStatus(); status.Error != "" || status.ErrorAt != nil {
+		t.Errorf("carga válida deveria limpar o erro: %+v", status)
+	}
+}
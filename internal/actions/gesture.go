@@ -0,0 +1,210 @@
+package actions
+
+import (
+	"sync"
+	"time"
+)
+
+// Gesture identifica um gesto reconhecido a partir de press/release
+type Gesture string
+
+const (
+	GestureTap    Gesture = "tap"    // Pressionar e soltar rapidamente
+	GestureDouble Gesture = "double" // Dois toques dentro da janela de duplo toque
+	GestureLong   Gesture = "long"   // Manter pressionado além do atraso de long-press
+	GestureRepeat Gesture = "repeat" // Repetido periodicamente enquanto mantido após o long-press
+)
+
+// Gestures lista os gestos suportados, na ordem usada em documentação e validação
+var Gestures = []Gesture{GestureTap, GestureDouble, GestureLong, GestureRepeat}
+
+// GestureKey monta a chave de keymap de um gesto (ex.: "Mute:long")
+func GestureKey(button string, gesture Gesture) string {
+	return button + ":" + string(gesture)
+}
+
+// GestureConfig define os tempos do reconhecimento de gestos
+type GestureConfig struct {
+	DoubleTapWindow time.Duration // Intervalo máximo entre soltar e pressionar de novo
+	LongPressDelay  time.Duration // Tempo pressionado até disparar long
+	RepeatInterval  time.Duration // Intervalo entre repeats após o long
+}
+
+// DefaultGestureConfig retorna os tempos padrão
+func DefaultGestureConfig() GestureConfig {
+	return GestureConfig{
+		DoubleTapWindow: 300 * time.Millisecond,
+		LongPressDelay:  800 * time.Millisecond,
+		RepeatInterval:  250 * time.Millisecond,
+	}
+}
+
+// buttonGesture é o estado de reconhecimento de um botão
+type buttonGesture struct {
+	pressed    bool
+	secondTap  bool // press atual é o segundo de um possível duplo toque
+	longFired  bool
+	pendingTap bool // tap aguardando a janela de duplo toque
+	generation int  // invalida timers de interações anteriores
+	timer      *time.Timer
+}
+
+// GestureRecognizer converte sequências de press/release em gestos.
+// Sem entrada de duplo toque no keymap (wantsDouble), o tap é emitido
+// imediatamente ao soltar, sem esperar a janela.
+type GestureRecognizer struct {
+	mu          sync.Mutex
+	config      GestureConfig
+	buttons     map[string]*buttonGesture
+	emit        func(button string, gesture Gesture)
+	wantsDouble func(button string) bool
+}
+
+// NewGestureRecognizer cria o reconhecedor; emit é chamado fora do lock
+func NewGestureRecognizer(config GestureConfig, emit func(button string, gesture Gesture), wantsDouble func(button string) bool) *GestureRecognizer {
+	if wantsDouble == nil {
+		wantsDouble = func(string) bool { return true }
+	}
+	return &GestureRecognizer{
+		config:      config,
+		buttons:     make(map[string]*buttonGesture),
+		emit:        emit,
+		wantsDouble: wantsDouble,
+	}
+}
+
+// SetConfig altera os tempos; vale para as próximas interações
+func (r *GestureRecognizer) SetConfig(config GestureConfig) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.config = config
+}
+
+// Config retorna os tempos atuais
+func (r *GestureRecognizer) Config() GestureConfig {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.config
+}
+
+// Feed processa um evento de botão
+func (r *GestureRecognizer) Feed(button string, pressed bool) {
+	r.mu.Lock()
+	b, ok := r.buttons[button]
+	if !ok {
+		b = &buttonGesture{}
+		r.buttons[button] = b
+	}
+
+	if pressed == b.pressed {
+		// Eventos repetidos (ex.: pacotes duplicados) não mudam o estado
+		r.mu.Unlock()
+		return
+	}
+	b.pressed = pressed
+
+	var emitNow []Gesture
+	if pressed {
+		r.stopTimer(b)
+		b.secondTap = b.pendingTap
+		b.pendingTap = false
+		b.longFired = false
+		r.schedule(button, b, r.config.LongPressDelay, r.onLong)
+	} else {
+		r.stopTimer(b)
+		switch {
+		case b.longFired:
+			// Long/repeat já emitidos; soltar encerra o gesto
+		case b.secondTap:
+			emitNow = append(emitNow, GestureDouble)
+		case r.wantsDouble(button):
+			b.pendingTap = true
+			r.schedule(button, b, r.config.DoubleTapWindow, r.onTapWindow)
+		default:
+			emitNow = append(emitNow, GestureTap)
+		}
+		b.secondTap = false
+	}
+	r.mu.Unlock()
+
+	for _, g := range emitNow {
+		r.emit(button, g)
+	}
+}
+
+// schedule agenda fn para o botão, invalidando timers anteriores (com lock)
+func (r *GestureRecognizer) schedule(button string, b *buttonGesture, delay time.Duration, fn func(button string, generation int)) {
+	b.generation++
+	generation := b.generation
+	b.timer = time.AfterFunc(delay, func() { fn(button, generation) })
+}
+
+// stopTimer cancela o timer pendente do botão (com lock)
+func (r *GestureRecognizer) stopTimer(b *buttonGesture) {
+	b.generation++
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
+
+// current retorna o estado do botão se o timer ainda é válido (com lock)
+func (r *GestureRecognizer) current(button string, generation int) (*buttonGesture, bool) {
+	b, ok := r.buttons[button]
+	return b, ok && b.generation == generation
+}
+
+// onLong dispara long e inicia a repetição enquanto o botão estiver pressionado
+func (r *GestureRecognizer) onLong(button string, generation int) {
+	r.mu.Lock()
+	b, ok := r.current(button, generation)
+	if !ok || !b.pressed {
+		r.mu.Unlock()
+		return
+	}
+	b.longFired = true
+	b.secondTap = false
+	r.schedule(button, b, r.config.RepeatInterval, r.onRepeat)
+	r.mu.Unlock()
+
+	r.emit(button, GestureLong)
+}
+
+// onRepeat emite repeat e reagenda enquanto o botão estiver pressionado
+func (r *GestureRecognizer) onRepeat(button string, generation int) {
+	r.mu.Lock()
+	b, ok := r.current(button, generation)
+	if !ok || !b.pressed {
+		r.mu.Unlock()
+		return
+	}
+	r.schedule(button, b, r.config.RepeatInterval, r.onRepeat)
+	r.mu.Unlock()
+
+	r.emit(button, GestureRepeat)
+}
+
+// onTapWindow emite o tap quando a janela de duplo toque expira sem segundo press
+func (r *GestureRecognizer) onTapWindow(button string, generation int) {
+	r.mu.Lock()
+	b, ok := r.current(button, generation)
+	if !ok || !b.pendingTap {
+		r.mu.Unlock()
+		return
+	}
+	b.pendingTap = false
+	b.timer = nil
+	r.mu.Unlock()
+
+	r.emit(button, GestureTap)
+}
+
+// Stop cancela todos os timers pendentes
+func (r *GestureRecognizer) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.buttons {
+		r.stopTimer(b)
+		b.pendingTap = false
+	}
+}
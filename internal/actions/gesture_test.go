@@ -0,0 +1,155 @@
+package actions
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// gestureLog coleta os gestos emitidos pelo reconhecedor
+type gestureLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *gestureLog) emit(button string, gesture Gesture) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, GestureKey(button, gesture))
+}
+
+func (l *gestureLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.events...)
+}
+
+var testGestureConfig = GestureConfig{
+	DoubleTapWindow: 40 * time.Millisecond,
+	LongPressDelay:  60 * time.Millisecond,
+	RepeatInterval:  30 * time.Millisecond,
+}
+
+func TestGestureTapAndDouble(t *testing.T) {
+	var log gestureLog
+	r := NewGestureRecognizer(testGestureConfig, log.emit, nil)
+	defer r.Stop()
+
+	r.Feed("Mute", true)
+	r.Feed("Mute", false)
+	if got := log.get(); len(got) != 0 {
+		t.Fatalf("tap não deveria ser emitido antes da janela de duplo toque: %v", got)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if got := log.get(); len(got) != 1 || got[0] != "Mute:tap" {
+		t.Fatalf("esperado Mute:tap, obtido %v", got)
+	}
+
+	r.Feed("Mute", true)
+	r.Feed("Mute", false)
+	r.Feed("Mute", true)
+	r.Feed("Mute", false)
+	time.Sleep(100 * time.Millisecond)
+	if got := log.get(); len(got) != 2 || got[1] != "Mute:double" {
+		t.Fatalf("esperado Mute:double sem tap extra, obtido %v", got)
+	}
+}
+
+func TestGestureTapWithoutDouble(t *testing.T) {
+	var log gestureLog
+	r := NewGestureRecognizer(testGestureConfig, log.emit, func(string) bool { return false })
+	defer r.Stop()
+
+	r.Feed("Flash", true)
+	r.Feed("Flash", false)
+	if got := log.get(); len(got) != 1 || got[0] != "Flash:tap" {
+		t.Fatalf("tap deveria ser imediato sem entrada de duplo toque, obtido %v", got)
+	}
+}
+
+func TestGestureLongAndRepeat(t *testing.T) {
+	var log gestureLog
+	r := NewGestureRecognizer(testGestureConfig, log.emit, nil)
+	defer r.Stop()
+
+	r.Feed("VolumeUp", true)
+	time.Sleep(150 * time.Millisecond)
+	r.Feed("VolumeUp", false)
+	got := log.get()
+
+	if len(got) < 2 || got[0] != "VolumeUp:long" {
+		t.Fatalf("esperado long seguido de repeat, obtido %v", got)
+	}
+	for _, key := range got[1:] {
+		if key != "VolumeUp:repeat" {
+			t.Fatalf("esperado apenas repeat após long, obtido %v", got)
+		}
+	}
+
+	// Soltar encerra o gesto: sem tap e sem novos repeats
+	time.Sleep(100 * time.Millisecond)
+	if after := log.get(); len(after) != len(got) {
+		t.Errorf("eventos após soltar o botão: %v", after[len(got):])
+	}
+}
+
+// fakeEmitter registra os eventos emitidos pelo executor
+type fakeEmitter struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (f *fakeEmitter) EmitClick(button string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, button)
+	return nil
+}
+
+func TestExecutorGestureKeys(t *testing.T) {
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.keyMap = KeyMap{
+		"Mute":         {Type: ActionSocketEmit, Event: "mute"},
+		"Mute:long":    {Type: ActionSocketEmit, Event: "mute_long"},
+		"Flash":        {Type: ActionSocketEmit, Event: "flash"},
+		"Flash:double": {Type: ActionSocketEmit},
+	}
+	e.SetGestureConfig(testGestureConfig)
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+
+	// Long-press executa só o long, sem a ação simples
+	e.Execute("Mute", true)
+	time.Sleep(80 * time.Millisecond)
+	e.Execute("Mute", false)
+
+	// Duplo toque executa só o double
+	e.Execute("Flash", true)
+	e.Execute("Flash", false)
+	e.Execute("Flash", true)
+	e.Execute("Flash", false)
+	time.Sleep(80 * time.Millisecond)
+
+	// Toque curto executa a ação simples uma vez
+	e.Execute("Mute", true)
+	e.Execute("Mute", false)
+	e.Execute("Flash", true)
+	e.Execute("Flash", false)
+	time.Sleep(80 * time.Millisecond)
+
+	want := []string{"mute_long", "Flash:double", "mute", "flash"}
+	socket.mu.Lock()
+	got := socket.events
+	socket.mu.Unlock()
+	if len(got) != len(want) {
+		t.Fatalf("esperado %v, obtido %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("evento %d: esperado %s, obtido %s", i, want[i], got[i])
+		}
+	}
+}
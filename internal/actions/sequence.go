@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// ErrorPolicy define o que uma sequência faz quando um passo falha
+type ErrorPolicy string
+
+const (
+	OnErrorStop     ErrorPolicy = "stop"     // Interrompe a sequência (padrão)
+	OnErrorContinue ErrorPolicy = "continue" // Registra o erro e segue para o próximo passo
+)
+
+// DefaultStepTimeout limita os passos sem timeout_ms
+const DefaultStepTimeout = 10 * time.Second
+
+// Step é um passo de uma ação "sequence": uma ação com atraso antes de
+// executar e timeout próprios. O on_error do passo sobrepõe o da sequência.
+type Step struct {
+	Action
+	DelayMs   int `json:"delay_ms,omitempty"`   // Espera antes do passo
+	TimeoutMs int `json:"timeout_ms,omitempty"` // Limite do passo (padrão DefaultStepTimeout)
+}
+
+// timeout retorna o limite de execução do passo
+func (s Step) timeout() time.Duration {
+	if s.TimeoutMs > 0 {
+		return time.Duration(s.TimeoutMs) * time.Millisecond
+	}
+	return DefaultStepTimeout
+}
+
+// startSequence executa a sequência em segundo plano, para não segurar os
+// eventos de botão seguintes durante atrasos e chamadas lentas. Uma
+// sequência ainda em execução não é iniciada de novo pela mesma chave; o
+// dispositivo do evento em ctx segue para os passos.
+func (e *Executor) startSequence(ctx context.Context, key string, action Action) error {
+	e.mu.Lock()
+	if e.running[key] {
+		e.mu.Unlock()
+		log.Printf("[Actions] Sequência %s já em execução, ignorando", key)
+		return nil
+	}
+	e.running[key] = true
+	e.mu.Unlock()
+
+	seqCtx := e.ctx
+	if deviceID, ok := eventDevice(ctx); ok {
+		seqCtx = withDevice(seqCtx, deviceID)
+	}
+	e.sequences.Add(1)
+	go func() {
+		defer e.sequences.Done()
+		defer func() {
+			e.mu.Lock()
+			delete(e.running, key)
+			e.mu.Unlock()
+		}()
+		if err := e.RunSequence(seqCtx, key, action); err != nil {
+			log.Printf("[Actions] Sequência %s falhou: %v", key, err)
+		}
+	}()
+	return nil
+}
+
+// RunSequence executa os passos da sequência em ordem, registrando o
+// resultado de cada um. Com on_error "stop" (padrão) o primeiro erro
+// interrompe a sequência; com "continue" os erros são reunidos no retorno.
+func (e *Executor) RunSequence(ctx context.Context, key string, action Action) error {
+	e.mu.RLock()
+	socket := e.socket
+	e.mu.RUnlock()
+
+	total := len(action.Steps)
+	var errs []error
+	for i, step := range action.Steps {
+		n := i + 1
+		if step.DelayMs > 0 {
+			timer := time.NewTimer(time.Duration(step.DelayMs) * time.Millisecond)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				log.Printf("[Actions] Sequência %s cancelada antes do passo %d/%d", key, n, total)
+				return errors.Join(append(errs, ctx.Err())...)
+			case <-timer.C:
+			}
+		}
+
+		start := time.Now()
+		err := e.runStep(ctx, key, step, socket)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err == nil {
+			log.Printf("[Actions] Sequência %s passo %d/%d (%s): ok em %s", key, n, total, step.Type, elapsed)
+			continue
+		}
+
+		log.Printf("[Actions] Sequência %s passo %d/%d (%s) falhou em %s: %v", key, n, total, step.Type, elapsed, err)
+		errs = append(errs, fmt.Errorf("step %d (%s): %w", n, step.Type, err))
+
+		policy := action.OnError
+		if step.OnError != "" {
+			policy = step.OnError
+		}
+		if policy != OnErrorContinue || ctx.Err() != nil {
+			log.Printf("[Actions] Sequência %s interrompida no passo %d/%d", key, n, total)
+			return errors.Join(errs...)
+		}
+	}
+
+	log.Printf("[Actions] Sequência %s concluída: %d/%d passos ok", key, total-len(errs), total)
+	return errors.Join(errs...)
+}
+
+// runStep executa um passo limitado pelo seu timeout. api_call e exec são
+// cancelados pelo contexto; nas demais ações o passo é dado como falho no
+// timeout e a ação termina em segundo plano.
+func (e *Executor) runStep(ctx context.Context, key string, step Step, socket SocketEmitter) error {
+	ctx, cancel := context.WithTimeout(ctx, step.timeout())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- e.run(ctx, key, step.Action, socket, true)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("step timed out: %w", ctx.Err())
+	}
+}
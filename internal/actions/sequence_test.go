@@ -0,0 +1,202 @@
+package actions
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+// emitted retorna e limpa os eventos emitidos
+func (f *fakeEmitter) emitted() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	got := f.events
+	f.events = nil
+	return got
+}
+
+func TestRunSequenceErrorPolicy(t *testing.T) {
+	crm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer crm.Close()
+
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+
+	steps := []Step{
+		{Action: Action{Type: ActionSocketEmit, Event: "click"}},
+		{Action: Action{Type: ActionAPICall, URL: crm.URL, Method: "POST"}},
+		{Action: Action{Type: ActionSocketEmit, Event: "busylight_on"}},
+	}
+
+	tests := []struct {
+		policy ErrorPolicy
+		want   []string
+	}{
+		{"", []string{"click"}},
+		{OnErrorStop, []string{"click"}},
+		{OnErrorContinue, []string{"click", "busylight_on"}},
+	}
+	for _, tt := range tests {
+		err := e.RunSequence(context.Background(), "OffHook", Action{Type: ActionSequence, Steps: steps, OnError: tt.policy})
+		if err == nil || !strings.Contains(err.Error(), "step 2 (api_call)") {
+			t.Errorf("RunSequence(on_error %q) = %v, want erro do passo 2", tt.policy, err)
+		}
+		if got := socket.emitted(); !slices.Equal(got, tt.want) {
+			t.Errorf("RunSequence(on_error %q) emitiu %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+
+	// on_error do passo sobrepõe o da sequência
+	lenient := slices.Clone(steps)
+	lenient[1].OnError = OnErrorContinue
+	if err := e.RunSequence(context.Background(), "OffHook", Action{Type: ActionSequence, Steps: lenient}); err == nil {
+		t.Error("RunSequence deveria retornar o erro do passo 2")
+	}
+	if got := socket.emitted(); !slices.Equal(got, []string{"click", "busylight_on"}) {
+		t.Errorf("on_error continue no passo emitiu %v, want [click busylight_on]", got)
+	}
+}
+
+func TestRunSequenceTimeoutAndDelay(t *testing.T) {
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+
+	start := time.Now()
+	err = e.RunSequence(context.Background(), "Flash", Action{Type: ActionSequence, OnError: OnErrorContinue, Steps: []Step{
+		{Action: Action{Type: ActionAPICall, URL: slow.URL}, TimeoutMs: 50},
+		{Action: Action{Type: ActionSocketEmit, Event: "flash"}, DelayMs: 100},
+	}})
+	elapsed := time.Since(start)
+
+	if err == nil || !strings.Contains(err.Error(), "step 1") {
+		t.Errorf("RunSequence = %v, want timeout no passo 1", err)
+	}
+	if elapsed < 150*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("RunSequence levou %s, want timeout de 50ms + atraso de 100ms", elapsed)
+	}
+	if got := socket.emitted(); !slices.Equal(got, []string{"flash"}) {
+		t.Errorf("RunSequence emitiu %v, want [flash]", got)
+	}
+}
+
+func TestExecuteSequence(t *testing.T) {
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+	e.SetAction("OffHook", Action{Type: ActionSequence, Steps: []Step{
+		{Action: Action{Type: ActionSocketEmit, Event: "click"}},
+		{Action: Action{Type: ActionSocketEmit, Event: "busylight_on"}, DelayMs: 10},
+		{Action: Action{Type: ActionSocketEmit, Event: "tarde_demais"}, DelayMs: 10000},
+	}})
+
+	// A sequência roda em segundo plano: Execute não aguarda os atrasos
+	start := time.Now()
+	if err := e.Execute("OffHook", true); err != nil {
+		t.Fatalf("Execute(OffHook) erro inesperado: %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Execute deveria retornar sem aguardar a sequência")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		socket.mu.Lock()
+		n := len(socket.events)
+		socket.mu.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// Stop cancela o atraso do último passo e aguarda a sequência
+	start = time.Now()
+	e.Stop()
+	if time.Since(start) > time.Second {
+		t.Error("Stop deveria cancelar a sequência em execução")
+	}
+	if got := socket.emitted(); !slices.Equal(got, []string{"click", "busylight_on"}) {
+		t.Errorf("sequência emitiu %v, want [click busylight_on]", got)
+	}
+}
+
+func TestExecuteSequenceBusylight(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "busylight_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	sim := jabra.NewSimulationDriver(nil)
+	m := jabra.NewMonitor(sim, "", store)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer m.Stop()
+	if err := sim.Execute(jabra.SimCommand{Action: jabra.SimAttach, Name: "Jabra Engage 55"}); err != nil {
+		t.Fatalf("attach erro inesperado: %v", err)
+	}
+
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	if err := e.run(context.Background(), "OffHook", Action{Type: ActionBusylight, Light: LightOn}, nil, false); err == nil {
+		t.Error("ação de busylight sem controlador deveria falhar")
+	}
+
+	socket := &fakeEmitter{}
+	e.SetSocketEmitter(socket)
+	e.SetBusylightController(m)
+	e.SetAction("OffHook", Action{Type: ActionSequence, Steps: []Step{
+		{Action: Action{Type: ActionBusylight, Light: LightOn}},
+		{Action: Action{Type: ActionSocketEmit, Event: "click"}},
+	}})
+	if err := e.Execute("OffHook", true); err != nil {
+		t.Fatalf("Execute(OffHook) erro inesperado: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		socket.mu.Lock()
+		n := len(socket.events)
+		socket.mu.Unlock()
+		if n == 1 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := socket.emitted(); !slices.Equal(got, []string{"click"}) {
+		t.Errorf("sequência emitiu %v, want [click]", got)
+	}
+	if state := sim.State(); len(state) != 1 || !state[0].Busylight {
+		t.Errorf("busylight simulado = %+v, want ligado", state)
+	}
+}
@@ -0,0 +1,207 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateVars são as variáveis disponíveis nos templates das ações
+// ({{.ramal}}, {{.operator_name}}...)
+var TemplateVars = []string{
+	"button", "gesture", "ramal", "token", "serial", "device",
+	"operator_name", "call_state", "timestamp",
+}
+
+// redactedToken substitui o token nos logs e erros
+const redactedToken = "***"
+
+// templateField é um campo de Action expandido como template e o escape
+// aplicado aos valores inseridos nele
+type templateField struct {
+	name   string
+	value  func(a *Action) *string
+	escape func(string) string
+}
+
+// templateFields são os campos expandidos: na URL os valores são
+// percent-encoded, no body escapados como conteúdo de string JSON e no
+// comando citados para o shell
+var templateFields = []templateField{
+	{"url", func(a *Action) *string { return &a.URL }, escapeURL},
+	{"body", func(a *Action) *string { return &a.Body }, escapeJSON},
+	{"cmd", func(a *Action) *string { return &a.Command }, escapeShell},
+	{"message", func(a *Action) *string { return &a.Message }, noEscape},
+	{"title", func(a *Action) *string { return &a.Title }, noEscape},
+}
+
+// templateData monta as variáveis dos templates para a chave do keymap
+// ("Flash", "Mute:long", "Wear:off:idle") e o estado do agente
+func templateData(key string, state AgentState, now time.Time) map[string]string {
+	button, rest, _ := strings.Cut(key, ":")
+	gesture, _, _ := strings.Cut(rest, ":")
+	if gesture == "" {
+		gesture = "press"
+	}
+	return map[string]string{
+		"button":        button,
+		"gesture":       gesture,
+		"ramal":         state.Ramal,
+		"token":         state.Token,
+		"serial":        state.Serial,
+		"device":        state.Device,
+		"operator_name": state.Operator,
+		"call_state":    state.CallState,
+		"timestamp":     now.Format(time.RFC3339),
+	}
+}
+
+// sampleTemplateData são valores de exemplo para validar templates
+func sampleTemplateData() map[string]string {
+	return templateData("Flash", AgentState{
+		CallState: "idle", Ramal: "1000", Token: "token", Serial: "0000",
+		Device: "Jabra", Operator: "Operador 01",
+	}, time.Now())
+}
+
+// expandTemplate expande um texto com as variáveis já escapadas para o
+// campo. Textos sem "{{" são retornados sem alteração.
+func expandTemplate(name, text string, data map[string]string, escape func(string) string) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	escaped := make(map[string]string, len(data))
+	for k, v := range data {
+		escaped[k] = escape(v)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, escaped); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// hasTemplates retorna true se algum campo da ação é um template
+func (a Action) hasTemplates() bool {
+	for _, field := range templateFields {
+		if strings.Contains(*field.value(&a), "{{") {
+			return true
+		}
+	}
+	return false
+}
+
+// expandTemplates expande os campos da ação para a chave e o estado atual
+// do agente. O replacer retornado (nil se não há token) oculta o token nos
+// logs e erros.
+func (e *Executor) expandTemplates(key string, action Action) (Action, *strings.Replacer, error) {
+	if !action.hasTemplates() {
+		return action, nil, nil
+	}
+
+	e.mu.RLock()
+	provider := e.state
+	now := e.now
+	e.mu.RUnlock()
+
+	var state AgentState
+	if provider != nil {
+		state = provider.AgentState()
+	}
+	data := templateData(key, state, now())
+
+	for _, field := range templateFields {
+		value := field.value(&action)
+		expanded, err := expandTemplate(field.name, *value, data, field.escape)
+		if err != nil {
+			return action, nil, fmt.Errorf("template %s: %w", field.name, err)
+		}
+		*value = expanded
+	}
+	return action, tokenRedactor(state.Token), nil
+}
+
+// tokenRedactor oculta o token, em todas as formas escapadas, nos logs
+func tokenRedactor(token string) *strings.Replacer {
+	if token == "" {
+		return nil
+	}
+	var pairs []string
+	for _, field := range templateFields {
+		if escaped := field.escape(token); escaped != token {
+			pairs = append(pairs, escaped, redactedToken)
+		}
+	}
+	return strings.NewReplacer(append(pairs, token, redactedToken)...)
+}
+
+// redact aplica o replacer do token, se houver
+func redact(r *strings.Replacer, s string) string {
+	if r == nil {
+		return s
+	}
+	return r.Replace(s)
+}
+
+// noEscape insere o valor sem escape (textos da notificação)
+func noEscape(s string) string {
+	return s
+}
+
+// escapeURL codifica o valor para qualquer parte da URL (caminho ou query)
+func escapeURL(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// escapeJSON escapa o valor como conteúdo de uma string JSON (sem aspas)
+func escapeJSON(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return string(data[1 : len(data)-1])
+}
+
+// escapeShell cita o valor como um único argumento do shell da plataforma
+func escapeShell(s string) string {
+	return shellQuote(runtime.GOOS, s)
+}
+
+// shellQuote cita o valor para sh (aspas simples) ou, no Windows, para o
+// cmd.exe (aspas duplas, removendo os caracteres que o cmd interpreta
+// mesmo entre aspas: ", %, ! e quebras de linha)
+func shellQuote(goos, s string) string {
+	if goos == "windows" {
+		s = strings.Map(func(r rune) rune {
+			switch r {
+			case '"', '%', '!', '\r', '\n':
+				return -1
+			}
+			return r
+		}, s)
+		return `"` + s + `"`
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// validateTemplates retorna a descrição do problema nos templates da ação,
+// expandindo-os com valores de exemplo (variáveis desconhecidas falham)
+func validateTemplates(action Action) string {
+	data := sampleTemplateData()
+	for _, field := range templateFields {
+		if _, err := expandTemplate(field.name, *field.value(&action), data, field.escape); err != nil {
+			return fmt.Sprintf("invalid template in %s: %v (variables: %s)", field.name, err, strings.Join(TemplateVars, ", "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTemplateEscaping(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"url", escapeURL("a b&c=d/é+"), "a%20b%26c%3Dd%2F%C3%A9%2B"},
+		{"json", escapeJSON("a\"b\\\n<"), `a\"b\\\n<`},
+		{"sh", shellQuote("linux", "it's; rm -rf ~"), `'it'\''s; rm -rf ~'`},
+		{"cmd", shellQuote("windows", "a\"b %PATH%! & c\r\n"), `"ab PATH & c"`},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: escape = %q, want %q", tt.name, tt.got, tt.want)
+		}
+	}
+
+	data := templateData("Mute:long", AgentState{Ramal: "1 2"}, time.Now())
+	got, err := expandTemplate("url", "http://crm/{{.ramal}}/{{.button}}/{{.gesture}}", data, escapeURL)
+	if err != nil || got != "http://crm/1%202/Mute/long" {
+		t.Errorf("expandTemplate = %q, %v", got, err)
+	}
+}
+
+func TestExecuteTemplates(t *testing.T) {
+	type request struct {
+		path, op, token string
+		body            map[string]string
+	}
+	received := make(chan request, 1)
+	crm := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		req := request{path: r.URL.Path, op: r.URL.Query().Get("op"), token: r.URL.Query().Get("t")}
+		json.Unmarshal(data, &req.body)
+		received <- req
+	}))
+	defer crm.Close()
+
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	e.SetStateProvider(&fakeState{state: AgentState{
+		CallState: "in_call", Ramal: "1234", Token: "s3cr&t", Serial: "ABC-1",
+		Device: `Jabra "Evolve2"`, Operator: "Maria José",
+	}})
+	e.SetAction("Redial", Action{
+		Type:   ActionAPICall,
+		Method: "POST",
+		URL:    crm.URL + "/crm/{{.ramal}}?op={{.operator_name}}&t={{.token}}",
+		Body:   `{"serial": "{{.serial}}", "device": "{{.device}}", "button": "{{.button}}", "call": "{{.call_state}}"}`,
+	})
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	if err := e.Execute("Redial", true); err != nil {
+		t.Fatalf("Execute(Redial) erro inesperado: %v", err)
+	}
+	req := <-received
+	if req.path != "/crm/1234" || req.op != "Maria José" || req.token != "s3cr&t" {
+		t.Errorf("requisição = %+v, want ramal, operador e token na URL", req)
+	}
+	want := map[string]string{"serial": "ABC-1", "device": `Jabra "Evolve2"`, "button": "Redial", "call": "in_call"}
+	for k, v := range want {
+		if req.body[k] != v {
+			t.Errorf("body[%s] = %q, want %q", k, req.body[k], v)
+		}
+	}
+
+	// Erros com a URL também não expõem o token
+	e.SetAction("Flash", Action{Type: ActionAPICall, URL: "http://127.0.0.1:1/?t={{.token}}"})
+	err = e.Execute("Flash", true)
+	if err == nil || strings.Contains(err.Error(), "s3cr") {
+		t.Errorf("Execute(Flash) = %v, want erro sem o token", err)
+	}
+	if strings.Contains(logs.String(), "s3cr") || !strings.Contains(logs.String(), redactedToken) {
+		t.Errorf("token exposto nos logs:\n%s", logs.String())
+	}
+}
+
+func TestExecuteCommandTemplateQuoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("citação do sh")
+	}
+	out := filepath.Join(t.TempDir(), "operador")
+
+	e, err := NewExecutor("")
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer e.Stop()
+	operator := "Maria'; touch " + out + ".pwned; echo '"
+	e.SetStateProvider(&fakeState{state: AgentState{Operator: operator}})
+
+	err = e.RunSequence(context.Background(), "Flash", Action{Type: ActionSequence, Steps: []Step{
+		{Action: Action{Type: ActionExec, Command: "printf %s {{.operator_name}} > " + out}},
+	}})
+	if err != nil {
+		t.Fatalf("RunSequence erro inesperado: %v", err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != operator {
+		t.Errorf("comando recebeu %q, want %q", data, operator)
+	}
+	if _, err := os.Stat(out + ".pwned"); err == nil {
+		t.Error("valor do template executado pelo shell")
+	}
+}
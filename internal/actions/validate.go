@@ -0,0 +1,217 @@
+package actions
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+// ActionTypes lista os tipos de ação aceitos no keymap
+var ActionTypes = []ActionType{
+	ActionAPICall, ActionExec, ActionSocketEmit, ActionNotify,
+	ActionPlaySound, ActionVolume, ActionBusylight, ActionSequence, ActionNone,
+}
+
+// requiredFields é o campo JSON obrigatório de cada tipo de ação
+var requiredFields = map[ActionType]string{
+	ActionAPICall:   "url",
+	ActionExec:      "cmd",
+	ActionNotify:    "message",
+	ActionPlaySound: "sound",
+	ActionVolume:    "step",
+	ActionBusylight: "light",
+	ActionSequence:  "steps",
+}
+
+// Problem é um problema encontrado em uma entrada do keymap
+type Problem struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Key, p.Message)
+}
+
+// ValidationError reúne os problemas de um keymap inválido
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		msgs[i] = p.String()
+	}
+	return fmt.Sprintf("invalid keymap (%d problems): %s", len(e.Problems), strings.Join(msgs, "; "))
+}
+
+// Validate confere chaves (botões, gestos e regras Wear conhecidos), tipos
+// de ação e os campos obrigatórios de cada tipo. Retorna *ValidationError
+// com todos os problemas, em ordem de chave.
+func (km KeyMap) Validate() error {
+	keys := make([]string, 0, len(km))
+	for key := range km {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var problems []Problem
+	for _, key := range keys {
+		if msg := validateKey(key); msg != "" {
+			problems = append(problems, Problem{Key: key, Message: msg})
+		}
+		if msg := validateAction(km[key]); msg != "" {
+			problems = append(problems, Problem{Key: key, Message: msg})
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateKey retorna a descrição do problema da chave, ou vazio se válida
+func validateKey(key string) string {
+	parts := strings.Split(key, ":")
+	if parts[0] == WearButton {
+		if len(parts) < 2 || len(parts) > 3 || (parts[1] != "on" && parts[1] != "off") {
+			return `wear keys must be "Wear:on", "Wear:off" or "Wear:<on|off>:<call state>"`
+		}
+		if len(parts) == 3 && !slices.Contains(jabra.CallStates, jabra.CallState(parts[2])) {
+			return fmt.Sprintf("unknown call state %q", parts[2])
+		}
+		return ""
+	}
+
+	if _, ok := jabra.ParseButtonID(parts[0]); !ok {
+		for _, name := range jabra.ButtonNames() {
+			if strings.EqualFold(name, parts[0]) {
+				return fmt.Sprintf("unknown button %q (did you mean %q?)", parts[0], name)
+			}
+		}
+		return fmt.Sprintf("unknown button %q", parts[0])
+	}
+	switch len(parts) {
+	case 1:
+		return ""
+	case 2:
+		if !slices.Contains(Gestures, Gesture(parts[1])) {
+			return fmt.Sprintf("unknown gesture %q", parts[1])
+		}
+		return ""
+	}
+	return `keys must be "<button>" or "<button>:<gesture>"`
+}
+
+// validateAction retorna a descrição do problema da ação, ou vazio se válida
+func validateAction(action Action) string {
+	if action.Type == "" {
+		return "missing action"
+	}
+	if !slices.Contains(ActionTypes, action.Type) {
+		return fmt.Sprintf("unknown action %q", action.Type)
+	}
+
+	missing := false
+	switch action.Type {
+	case ActionAPICall:
+		missing = action.URL == ""
+	case ActionExec:
+		missing = action.Command == ""
+	case ActionNotify:
+		missing = action.Message == ""
+	case ActionPlaySound:
+		missing = action.Sound == ""
+	case ActionVolume:
+		missing = action.Step == 0
+	case ActionBusylight:
+		missing = action.Light == ""
+	case ActionSequence:
+		missing = len(action.Steps) == 0
+	}
+	if missing {
+		return fmt.Sprintf("%s requires %s", action.Type, requiredFields[action.Type])
+	}
+
+	if action.Type == ActionBusylight && action.Light != LightOn && action.Light != LightOff {
+		return fmt.Sprintf("unknown light %q (use %q or %q)", action.Light, LightOn, LightOff)
+	}
+	if method := strings.ToUpper(action.Method); action.Type == ActionAPICall && method != "" && method != "GET" && method != "POST" {
+		return fmt.Sprintf("unsupported HTTP method %q", action.Method)
+	}
+	if msg := validateTemplates(action); msg != "" {
+		return msg
+	}
+	if action.OnError != "" && action.OnError != OnErrorStop && action.OnError != OnErrorContinue {
+		return fmt.Sprintf("unknown on_error %q (use %q or %q)", action.OnError, OnErrorStop, OnErrorContinue)
+	}
+	for i, step := range action.Steps {
+		if msg := validateStep(step); msg != "" {
+			return fmt.Sprintf("step %d: %s", i+1, msg)
+		}
+	}
+	for i, rule := range action.Rules {
+		if msg := validateRule(rule); msg != "" {
+			return fmt.Sprintf("rule %d: %s", i+1, msg)
+		}
+	}
+	return ""
+}
+
+// validateStep retorna a descrição do problema do passo de uma sequência
+func validateStep(step Step) string {
+	if step.Type == ActionSequence {
+		return "nested sequences are not supported"
+	}
+	if len(step.Rules) > 0 {
+		return "rules are not supported in sequence steps"
+	}
+	if step.DelayMs < 0 || step.TimeoutMs < 0 {
+		return "delay_ms and timeout_ms must not be negative"
+	}
+	return validateAction(step.Action)
+}
+
+// validateRule retorna a descrição do problema da regra condicional
+func validateRule(rule Rule) string {
+	if len(rule.Rules) > 0 {
+		return "nested rules are not supported"
+	}
+	if msg := validateCondition(rule.When); msg != "" {
+		return msg
+	}
+	return validateAction(rule.Action)
+}
+
+// validateCondition retorna a descrição do problema da condição "when"
+func validateCondition(c Condition) string {
+	if c.empty() {
+		return "missing when (the entry action is already the default)"
+	}
+	for _, state := range c.CallState {
+		if !slices.Contains(jabra.CallStates, jabra.CallState(state)) {
+			return fmt.Sprintf("unknown call state %q", state)
+		}
+	}
+	if c.Connection != "" && c.Connection != "online" && c.Connection != "offline" {
+		return fmt.Sprintf(`unknown connection %q (use "online" or "offline")`, c.Connection)
+	}
+	if c.Socket != "" && c.Socket != "connected" && c.Socket != "disconnected" {
+		return fmt.Sprintf(`unknown socket %q (use "connected" or "disconnected")`, c.Socket)
+	}
+	if c.Time != "" {
+		if _, _, err := parseTimeRange(c.Time); err != nil {
+			return err.Error()
+		}
+	}
+	for _, day := range c.Weekdays {
+		if !slices.Contains(Weekdays, day) {
+			return fmt.Sprintf("unknown weekday %q (use %s)", day, strings.Join(Weekdays, ", "))
+		}
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package actions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKeyMapValidate(t *testing.T) {
+	valid := KeyMap{
+		"Mute":          {Type: ActionNotify, Message: "Mute"},
+		"Flash:double":  {Type: ActionSocketEmit},
+		"VolumeUp":      {Type: ActionVolume, Step: 5},
+		"Flash:long":    {Type: ActionBusylight, Light: LightOn},
+		"Redial":        {Type: ActionAPICall, URL: "http://localhost/redial/{{.ramal}}", Method: "post", Body: `{"op": "{{.operator_name}}"}`},
+		"Wear:off:idle": {Type: ActionSocketEmit, Event: "ausente"},
+		"Wear:on":       {Type: ActionNone},
+		"OffHook": {Type: ActionSequence, OnError: OnErrorContinue, Steps: []Step{
+			{Action: Action{Type: ActionSocketEmit, Event: "click"}},
+			{Action: Action{Type: ActionNone}, DelayMs: 500},
+			{Action: Action{Type: ActionAPICall, URL: "http://localhost/crm", OnError: OnErrorStop}, TimeoutMs: 2000},
+		}},
+		"Flash": {Type: ActionSocketEmit, Event: "flash", Rules: []Rule{
+			{When: Condition{CallState: []string{"in_call"}, Time: "8:00-18:00", Weekdays: []string{"mon", "fri"}}, Action: Action{Type: ActionSocketEmit, Event: "transferir"}},
+			{When: Condition{Socket: "disconnected", Connection: "online", Operator: []string{"Maria"}}, Action: Action{Type: ActionNone}},
+		}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("keymap válido rejeitado: %v", err)
+	}
+	if err := DefaultKeyMap().Validate(); err != nil {
+		t.Errorf("keymap padrão rejeitado: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		action Action
+	}{
+		{"Turbo", Action{Type: ActionNone}},
+		{"Mute:triple", Action{Type: ActionNone}},
+		{"Mute:long:extra", Action{Type: ActionNone}},
+		{"Wear:maybe", Action{Type: ActionNone}},
+		{"Wear:off:busy", Action{Type: ActionNone}},
+		{"Mute", Action{Type: "beep"}},
+		{"Mute", Action{}},
+		{"Redial", Action{Type: ActionAPICall}},
+		{"Redial", Action{Type: ActionAPICall, URL: "http://x", Method: "DELETE"}},
+		{"Flash", Action{Type: ActionExec}},
+		{"Mute", Action{Type: ActionNotify}},
+		{"Mute", Action{Type: ActionPlaySound}},
+		{"VolumeUp", Action{Type: ActionVolume}},
+		{"Flash", Action{Type: ActionBusylight}},
+		{"Flash", Action{Type: ActionBusylight, Light: "blink"}},
+		{"OffHook", Action{Type: ActionSequence}},
+		{"OffHook", Action{Type: ActionSequence, OnError: "retry", Steps: []Step{{Action: Action{Type: ActionNone}}}}},
+		{"OffHook", Action{Type: ActionSequence, Steps: []Step{{Action: Action{Type: ActionExec}}}}},
+		{"OffHook", Action{Type: ActionSequence, Steps: []Step{{Action: Action{Type: ActionNone}, DelayMs: -1}}}},
+		{"OffHook", Action{Type: ActionSequence, Steps: []Step{{Action: Action{Type: ActionSequence, Steps: []Step{{Action: Action{Type: ActionNone}}}}}}}},
+		{"Flash", Action{Type: ActionNone, Rules: []Rule{{Action: Action{Type: ActionNone}}}}},
+		{"Flash", Action{Type: ActionNone, Rules: []Rule{{When: Condition{CallState: []string{"busy"}}, Action: Action{Type: ActionNone}}}}},
+		{"Flash", Action{Type: ActionNone, Rules: []Rule{{When: Condition{Socket: "up"}, Action: Action{Type: ActionNone}}}}},
+		{"Flash", Action{Type: ActionNone, Rules: []Rule{{When: Condition{Time: "8h-18h"}, Action: Action{Type: ActionNone}}}}},
+		{"Flash", Action{Type: ActionNone, Rules: []Rule{{When: Condition{Weekdays: []string{"seg"}}, Action: Action{Type: ActionNone}}}}},
+		{"Flash", Action{Type: ActionNone, Rules: []Rule{{When: Condition{Connection: "online"}, Action: Action{Type: ActionAPICall}}}}},
+		{"Redial", Action{Type: ActionAPICall, URL: "http://crm/{{.ramall}}"}},
+		{"Redial", Action{Type: ActionExec, Command: "echo {{.ramal"}},
+	}
+	for _, tt := range tests {
+		err := KeyMap{tt.key: tt.action}.Validate()
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) || len(invalid.Problems) != 1 || invalid.Problems[0].Key != tt.key {
+			t.Errorf("Validate(%s: %+v) = %v, want um problema em %s", tt.key, tt.action, err, tt.key)
+		}
+	}
+}
+
+func TestRepoKeyMapIsValid(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "..", "config", "keymap.json"))
+	if err != nil {
+		t.Fatalf("Erro ao ler config/keymap.json: %v", err)
+	}
+	if _, err := ParseKeyMap(data); err != nil {
+		t.Errorf("config/keymap.json inválido: %v", err)
+	}
+}
@@ -1,34 +1,83 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
+	"strings"
+	"sync"
 
+	"github.com/aiknow/acc_jabra_agent/internal/actions"
 	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
 	"github.com/aiknow/acc_jabra_agent/internal/jabra"
 )
 
 type Server struct {
-	monitor *jabra.Monitor
-	store   *db.Store
+	monitor  *jabra.Monitor
+	store    *db.Store
+	executor *actions.Executor
+	mu       sync.Mutex
+	http     *http.Server
+
+	// Fechado no Shutdown para encerrar os streams de eventos abertos
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
 func NewServer(m *jabra.Monitor, s *db.Store) *Server {
-	return &Server{monitor: m, store: s}
+	return &Server{monitor: m, store: s, done: make(chan struct{})}
+}
+
+// SetExecutor habilita os endpoints de keymap. Deve ser chamado antes de Start.
+func (s *Server) SetExecutor(executor *actions.Executor) {
+	s.executor = executor
 }
 
 func (s *Server) Start(port string) error {
 	http.HandleFunc("/api/telemetry", s.handleTelemetry)
+	http.HandleFunc("/api/devices", s.handleDevices)
+	http.HandleFunc("/api/devices/{id}/telemetry", s.handleDeviceTelemetry)
+	http.HandleFunc("/api/devices/{id}/capabilities", s.handleDeviceCapabilities)
+	http.HandleFunc("/api/devices/{id}/settings", s.handleDeviceSettings)
+	http.HandleFunc("/api/devices/{id}/settings/{key}", s.handleDeviceSetting)
+	http.HandleFunc("/api/devices/{id}/volume", s.handleDeviceVolume)
+	http.HandleFunc("/api/settings/profile", s.handleSettingsProfile)
 	http.HandleFunc("/api/history/battery", s.handleBatteryHistory)
 	http.HandleFunc("/api/logs", s.handleLogs)
 	http.HandleFunc("/api/config", s.handleConfig)
 	http.HandleFunc("/api/health", s.handleHealth)
+	http.HandleFunc("/api/sim", s.handleSim)
+	http.HandleFunc("/api/sim/scenario", s.handleSimScenario)
+	http.HandleFunc("/api/events", s.handleEvents)
+	http.HandleFunc("/api/keymap", s.handleKeyMap)
+	http.HandleFunc("/api/keymap/reload", s.handleKeyMapReload)
 
 	fs := http.FileServer(http.Dir("./public"))
 	http.Handle("/", fs)
 
-	return http.ListenAndServe(":"+port, nil)
+	srv := &http.Server{Addr: ":" + port}
+	s.mu.Lock()
+	s.http = srv
+	s.mu.Unlock()
+	return srv.ListenAndServe()
+}
+
+// Shutdown encerra o servidor aguardando as requisições em curso até o
+// fim de ctx. Start retorna http.ErrServerClosed.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.done) })
+
+	s.mu.Lock()
+	srv := s.http
+	s.mu.Unlock()
+	if srv == nil {
+		return nil
+	}
+	return srv.Shutdown(ctx)
 }
 
 func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
@@ -57,10 +106,11 @@ func (s *Server) handleConfig(w http.ResponseWriter, r *http.Request) {
 
 	// GET: Retorna configs atuais
 	config := map[string]string{
-		"operator_name": s.store.GetSetting("operator_name", "Operador 01"),
-		"custom_color":  s.store.GetSetting("custom_color", "#2196F3"),
-		"autostart":     s.store.GetSetting("autostart", "true"),
+		"operator_name":  s.store.GetSetting("operator_name", "Operador 01"),
+		"custom_color":   s.store.GetSetting("custom_color", "#2196F3"),
+		"autostart":      s.store.GetSetting("autostart", "true"),
 		"show_tray":      s.store.GetSetting("show_tray", "true"),
+		"default_volume": s.store.GetSetting("default_volume", ""),
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(config)
@@ -83,7 +133,375 @@ func (s *Server) handleTelemetry(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]interface{}{"hostname": hostname, "data": data})
 }
 
+func (s *Server) handleDevices(w http.ResponseWriter, r *http.Request) {
+	devices := s.monitor.GetDevicesTelemetry()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(devices)
+}
+
+func (s *Server) handleDeviceTelemetry(w http.ResponseWriter, r *http.Request) {
+	data, ok := s.monitor.GetDeviceTelemetry(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "device not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	hostname, _ := os.Hostname()
+	json.NewEncoder(w).Encode(map[string]interface{}{"hostname": hostname, "data": data})
+}
+
+// deviceDriver resolve o dispositivo do path (ID ou serial) e o driver em
+// uso; em caso de falha já respondeu a requisição e retorna false
+func (s *Server) deviceDriver(w http.ResponseWriter, r *http.Request) (jabra.Driver, uint16, bool) {
+	data, ok := s.monitor.GetDeviceTelemetry(r.PathValue("id"))
+	if !ok {
+		http.Error(w, "device not found", http.StatusNotFound)
+		return nil, 0, false
+	}
+	driver := s.monitor.Driver()
+	if driver == nil {
+		http.Error(w, "driver not available", http.StatusServiceUnavailable)
+		return nil, 0, false
+	}
+	return driver, data.DeviceID, true
+}
+
+// handleDeviceCapabilities retorna o que o dispositivo suporta no driver atual
+func (s *Server) handleDeviceCapabilities(w http.ResponseWriter, r *http.Request) {
+	driver, deviceID, ok := s.deviceDriver(w, r)
+	if !ok {
+		return
+	}
+	caps, err := driver.Capabilities(deviceID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(caps)
+}
+
+// settingErrorStatus mapeia erros de configuração para o status HTTP
+func settingErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, jabra.ErrNotSupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, jabra.ErrUnknownSetting):
+		return http.StatusNotFound
+	case errors.Is(err, jabra.ErrInvalidSettingValue):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// publish publica um evento no barramento do monitor, se houver
+func (s *Server) publish(event events.Event) {
+	if bus := s.monitor.Events(); bus != nil {
+		bus.Publish(event)
+	}
+}
+
+// applyProfile aplica um perfil de configurações e registra as alterações
+func (s *Server) applyProfile(driver jabra.Driver, deviceID uint16, profile map[string]any) ([]jabra.SettingResult, bool) {
+	results := jabra.ApplySettings(driver, deviceID, profile)
+	applied := true
+	for _, result := range results {
+		if !result.Applied {
+			applied = false
+			continue
+		}
+		s.publish(events.SettingChanged{DeviceID: deviceID, Key: result.Key, Value: profile[result.Key]})
+	}
+	return results, applied
+}
+
+// handleDeviceSettings lista as configurações (GET) ou aplica um perfil
+// chave → valor ao dispositivo (POST)
+func (s *Server) handleDeviceSettings(w http.ResponseWriter, r *http.Request) {
+	driver, deviceID, ok := s.deviceDriver(w, r)
+	if !ok {
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		var profile map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		results, applied := s.applyProfile(driver, deviceID, profile)
+		w.Header().Set("Content-Type", "application/json")
+		if !applied {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		}
+		json.NewEncoder(w).Encode(results)
+		return
+	}
+
+	settings, err := driver.ListSettings(deviceID)
+	if err != nil {
+		http.Error(w, err.Error(), settingErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// handleDeviceSetting retorna (GET) ou altera (PUT/POST {"value": ...}) uma
+// configuração do dispositivo
+func (s *Server) handleDeviceSetting(w http.ResponseWriter, r *http.Request) {
+	driver, deviceID, ok := s.deviceDriver(w, r)
+	if !ok {
+		return
+	}
+	key := r.PathValue("key")
+
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		var body struct {
+			Value any `json:"value"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := driver.SetSetting(deviceID, key, body.Value); err != nil {
+			http.Error(w, err.Error(), settingErrorStatus(err))
+			return
+		}
+		s.publish(events.SettingChanged{DeviceID: deviceID, Key: key, Value: body.Value})
+	}
+
+	setting, err := driver.GetSetting(deviceID, key)
+	if err != nil {
+		http.Error(w, err.Error(), settingErrorStatus(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(setting)
+}
+
+// handleDeviceVolume retorna (GET) ou altera (PUT/POST {"volume": 0-100}
+// ou {"delta": n}) o volume do dispositivo
+func (s *Server) handleDeviceVolume(w http.ResponseWriter, r *http.Request) {
+	_, deviceID, ok := s.deviceDriver(w, r)
+	if !ok {
+		return
+	}
+
+	var level int
+	var err error
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		var body struct {
+			Volume *int `json:"volume"`
+			Delta  *int `json:"delta"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case body.Volume != nil:
+			if *body.Volume < 0 || *body.Volume > 100 {
+				http.Error(w, "volume must be between 0 and 100", http.StatusBadRequest)
+				return
+			}
+			level = *body.Volume
+			err = s.monitor.SetVolume(deviceID, level, "api")
+		case body.Delta != nil:
+			level, err = s.monitor.AdjustVolume(deviceID, *body.Delta, "api")
+		default:
+			http.Error(w, "volume or delta required", http.StatusBadRequest)
+			return
+		}
+	} else {
+		level, err = s.monitor.GetVolume(deviceID)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), settingErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int{"device_id": int(deviceID), "volume": level})
+}
+
+// handleKeyMap retorna o keymap em uso e o estado da última recarga,
+// incluindo o erro da última versão rejeitada do arquivo
+func (s *Server) handleKeyMap(w http.ResponseWriter, r *http.Request) {
+	if s.executor == nil {
+		http.Error(w, "executor not available", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": s.executor.KeyMapStatus(),
+		"keymap": s.executor.GetKeyMap(),
+	})
+}
+
+// handleKeyMapReload recarrega o arquivo de keymap (POST); arquivo inválido
+// responde 422 com o estado, mantendo o keymap anterior
+func (s *Server) handleKeyMapReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.executor == nil {
+		http.Error(w, "executor not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	err := s.executor.ReloadKeyMap()
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(s.executor.KeyMapStatus())
+}
+
+// handleSettingsProfile aplica um perfil de configurações a todos os
+// dispositivos online (POST); a resposta traz os resultados por serial
+func (s *Server) handleSettingsProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	driver := s.monitor.Driver()
+	if driver == nil {
+		http.Error(w, "driver not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	var profile map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results := make(map[string][]jabra.SettingResult)
+	allApplied := true
+	for _, device := range s.monitor.GetDevicesTelemetry() {
+		if device.State.Connection != "online" {
+			continue
+		}
+		deviceResults, applied := s.applyProfile(driver, device.DeviceID, profile)
+		results[device.Serial] = deviceResults
+		allApplied = allApplied && applied
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !allApplied {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(results)
+}
+
+// handleEvents transmite os eventos do barramento por Server-Sent Events
+// (event: tipo, data: JSON). ?kinds=button,call_state filtra os tipos.
+// Clientes lentos demais são desconectados em vez de atrasar o barramento.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	bus := s.monitor.Events()
+	if bus == nil {
+		http.Error(w, "event bus not available", http.StatusServiceUnavailable)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var kinds []events.Kind
+	if raw := r.URL.Query().Get("kinds"); raw != "" {
+		for _, kind := range strings.Split(raw, ",") {
+			kinds = append(kinds, events.Kind(strings.TrimSpace(kind)))
+		}
+	}
+	sub := bus.Subscribe(events.Options{Name: "sse " + r.RemoteAddr, Policy: events.Disconnect, Kinds: kinds})
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Kind(), data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
+
+// simulation retorna o driver simulado em uso, se houver
+func (s *Server) simulation() (*jabra.SimulationDriver, bool) {
+	sim, ok := s.monitor.Driver().(*jabra.SimulationDriver)
+	return sim, ok
+}
+
+// handleSim retorna o estado simulado (GET) ou aplica um comando ao vivo (POST)
+func (s *Server) handleSim(w http.ResponseWriter, r *http.Request) {
+	sim, ok := s.simulation()
+	if !ok {
+		http.Error(w, "simulation driver not active", http.StatusConflict)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		var cmd jabra.SimCommand
+		if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := sim.Execute(cmd); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sim.State())
+}
+
+// handleSimScenario executa um cenário completo enviado no corpo (POST)
+func (s *Server) handleSimScenario(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	sim, ok := s.simulation()
+	if !ok {
+		http.Error(w, "simulation driver not active", http.StatusConflict)
+		return
+	}
+
+	var scenario jabra.SimScenario
+	if err := json.NewDecoder(r.Body).Decode(&scenario); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := sim.RunScenario(&scenario); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -1,13 +1,20 @@
 package api
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/aiknow/acc_jabra_agent/internal/actions"
 	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
 	"github.com/aiknow/acc_jabra_agent/internal/jabra"
 )
 
@@ -15,7 +22,7 @@ func TestEndpoints(t *testing.T) {
 	// Setup
 	store, _ := db.NewStore("./jabra_test_api.db")
 	defer os.Remove("./jabra_test_api.db")
-	monitor := jabra.NewMonitor("TEST-SERIAL", store)
+	monitor := jabra.NewMonitor(nil, "TEST-SERIAL", store)
 	server := NewServer(monitor, store)
 
 	t.Run("GET /api/health", func(t *testing.T) {
@@ -45,4 +52,329 @@ func TestEndpoints(t *testing.T) {
 			t.Error("hostname não deve estar vazio")
 		}
 	})
+
+	t.Run("GET /api/devices", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/api/devices", nil)
+		rr := httptest.NewRecorder()
+		handler := http.HandlerFunc(server.handleDevices)
+		handler.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusOK {
+			t.Errorf("status incorreto: got %v want %v", status, http.StatusOK)
+		}
+
+		var resp []map[string]interface{}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Errorf("resposta deveria ser uma lista JSON: %v", err)
+		}
+	})
+
+	t.Run("GET /api/devices/{id}/telemetry inexistente", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/devices/{id}/telemetry", server.handleDeviceTelemetry)
+		req, _ := http.NewRequest("GET", "/api/devices/999/telemetry", nil)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("status incorreto: got %v want %v", status, http.StatusNotFound)
+		}
+	})
+}
+
+func TestSimEndpoints(t *testing.T) {
+	store, _ := db.NewStore(filepath.Join(t.TempDir(), "jabra_test_sim.db"))
+
+	t.Run("sem driver simulado", func(t *testing.T) {
+		server := NewServer(jabra.NewMonitor(nil, "TEST-SERIAL", store), store)
+		req, _ := http.NewRequest("GET", "/api/sim", nil)
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(server.handleSim).ServeHTTP(rr, req)
+
+		if status := rr.Code; status != http.StatusConflict {
+			t.Errorf("status incorreto: got %v want %v", status, http.StatusConflict)
+		}
+	})
+
+	sim := jabra.NewSimulationDriver(nil)
+	monitor := jabra.NewMonitor(sim, "TEST-SERIAL", store)
+	if err := monitor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer monitor.Stop()
+	server := NewServer(monitor, store)
+
+	post := func(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/api/sim", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("POST /api/sim attach", func(t *testing.T) {
+		rr := post(server.handleSim, `{"action":"attach","device":7,"serial":"SIM-API"}`)
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("status incorreto: got %v want %v (%s)", status, http.StatusOK, rr.Body.String())
+		}
+
+		var state []jabra.SimDeviceState
+		json.NewDecoder(rr.Body).Decode(&state)
+		if len(state) != 1 || state[0].Serial != "SIM-API" {
+			t.Errorf("estado inesperado: %+v", state)
+		}
+		if telemetry, ok := monitor.GetDeviceTelemetry("SIM-API"); !ok || telemetry.State.Connection != "online" {
+			t.Errorf("monitor deveria ver o dispositivo simulado: %+v", telemetry)
+		}
+	})
+
+	t.Run("GET /api/devices/{id}/capabilities", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/devices/{id}/capabilities", server.handleDeviceCapabilities)
+
+		req, _ := http.NewRequest("GET", "/api/devices/SIM-API/capabilities", nil)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		if status := rr.Code; status != http.StatusOK {
+			t.Fatalf("status incorreto: got %v want %v (%s)", status, http.StatusOK, rr.Body.String())
+		}
+		var caps jabra.Capabilities
+		json.NewDecoder(rr.Body).Decode(&caps)
+		if caps != jabra.AllCapabilities() {
+			t.Errorf("simulador deveria suportar tudo: %+v", caps)
+		}
+
+		req, _ = http.NewRequest("GET", "/api/devices/999/capabilities", nil)
+		rr = httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		if status := rr.Code; status != http.StatusNotFound {
+			t.Errorf("status incorreto: got %v want %v", status, http.StatusNotFound)
+		}
+	})
+
+	t.Run("configurações do dispositivo", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/devices/{id}/settings", server.handleDeviceSettings)
+		mux.HandleFunc("/api/devices/{id}/settings/{key}", server.handleDeviceSetting)
+		mux.HandleFunc("/api/settings/profile", server.handleSettingsProfile)
+		do := func(method, path, body string) *httptest.ResponseRecorder {
+			req, _ := http.NewRequest(method, path, strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+			return rr
+		}
+
+		rr := do("GET", "/api/devices/SIM-API/settings", "")
+		var settings []jabra.Setting
+		if err := json.NewDecoder(rr.Body).Decode(&settings); err != nil || rr.Code != http.StatusOK || len(settings) == 0 {
+			t.Fatalf("GET settings = %d, %d configurações, %v", rr.Code, len(settings), err)
+		}
+
+		rr = do("PUT", "/api/devices/SIM-API/settings/sidetone", `{"value":"high"}`)
+		var setting jabra.Setting
+		json.NewDecoder(rr.Body).Decode(&setting)
+		if rr.Code != http.StatusOK || setting.Value != "high" {
+			t.Errorf("PUT sidetone = %d, %+v", rr.Code, setting)
+		}
+		if rr := do("PUT", "/api/devices/SIM-API/settings/sidetone", `{"value":"max"}`); rr.Code != http.StatusBadRequest {
+			t.Errorf("valor inválido: status %d, want %d", rr.Code, http.StatusBadRequest)
+		}
+		if rr := do("GET", "/api/devices/SIM-API/settings/equalizer", ""); rr.Code != http.StatusNotFound {
+			t.Errorf("configuração desconhecida: status %d, want %d", rr.Code, http.StatusNotFound)
+		}
+
+		rr = do("POST", "/api/settings/profile", `{"auto_answer":true,"ringtone":3}`)
+		var results map[string][]jabra.SettingResult
+		json.NewDecoder(rr.Body).Decode(&results)
+		if rr.Code != http.StatusOK || len(results["SIM-API"]) != 2 {
+			t.Errorf("POST profile = %d, %+v", rr.Code, results)
+		}
+		if rr := do("POST", "/api/devices/SIM-API/settings", `{"ringtone":0}`); rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("perfil inválido: status %d, want %d", rr.Code, http.StatusUnprocessableEntity)
+		}
+	})
+
+	t.Run("volume do dispositivo", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/api/devices/{id}/volume", server.handleDeviceVolume)
+		do := func(method, body string) (int, int) {
+			req, _ := http.NewRequest(method, "/api/devices/SIM-API/volume", strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+			var resp struct {
+				Volume int `json:"volume"`
+			}
+			json.NewDecoder(rr.Body).Decode(&resp)
+			return rr.Code, resp.Volume
+		}
+
+		if code, volume := do("PUT", `{"volume":60}`); code != http.StatusOK || volume != 60 {
+			t.Errorf("PUT volume = %d, %d; want 200, 60", code, volume)
+		}
+		if code, volume := do("POST", `{"delta":-10}`); code != http.StatusOK || volume != 50 {
+			t.Errorf("POST delta = %d, %d; want 200, 50", code, volume)
+		}
+		if code, volume := do("GET", ""); code != http.StatusOK || volume != 50 {
+			t.Errorf("GET volume = %d, %d; want 200, 50", code, volume)
+		}
+		if telemetry, _ := monitor.GetDeviceTelemetry("SIM-API"); telemetry.State.Volume != 50 {
+			t.Errorf("telemetria com volume %d, want 50", telemetry.State.Volume)
+		}
+		if code, _ := do("PUT", `{"volume":150}`); code != http.StatusBadRequest {
+			t.Errorf("volume inválido: status %d, want %d", code, http.StatusBadRequest)
+		}
+		if code, _ := do("PUT", `{}`); code != http.StatusBadRequest {
+			t.Errorf("corpo vazio: status %d, want %d", code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("POST /api/sim comando inválido", func(t *testing.T) {
+		rr := post(server.handleSim, `{"action":"press","device":7,"button":"Turbo"}`)
+		if status := rr.Code; status != http.StatusBadRequest {
+			t.Errorf("status incorreto: got %v want %v", status, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("POST /api/sim/scenario", func(t *testing.T) {
+		rr := post(server.handleSimScenario, `{"name":"api","steps":[{"action":"battery","device":7,"level":42}]}`)
+		if status := rr.Code; status != http.StatusAccepted {
+			t.Fatalf("status incorreto: got %v want %v", status, http.StatusAccepted)
+		}
+
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			state := sim.State()
+			if len(state) == 1 && state[0].Battery == 42 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("cenário não aplicado: %+v", state)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
+
+func TestEventStream(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "events_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	bus := events.NewBus()
+	defer bus.Close()
+
+	sim := jabra.NewSimulationDriver(nil)
+	monitor := jabra.NewMonitor(sim, "TEST-SERIAL", store)
+	monitor.SetEventBus(bus)
+	if err := monitor.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer monitor.Stop()
+	if err := sim.Execute(jabra.SimCommand{Action: jabra.SimAttach, Device: 1, Serial: "SSE-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(monitor, store)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/events", server.handleEvents)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/api/events?kinds=button")
+	if err != nil {
+		t.Fatalf("GET /api/events: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	if err := sim.Execute(jabra.SimCommand{Action: jabra.SimPress, Device: 1, Button: "Mute"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	var got []string
+	for len(got) < 2 {
+		select {
+		case line := <-lines:
+			if line != "" {
+				got = append(got, line)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("evento não recebido: %v", got)
+		}
+	}
+	if got[0] != "event: button" || !strings.Contains(got[1], `"button":"Mute"`) || !strings.Contains(got[1], `"serial":"SSE-1"`) {
+		t.Errorf("evento inesperado: %v", got)
+	}
+
+	// Shutdown encerra os streams abertos
+	server.Shutdown(context.Background())
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-lines:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("stream não encerrado no Shutdown")
+		}
+	}
+}
+
+func TestKeyMapEndpoints(t *testing.T) {
+	dir := t.TempDir()
+	store, err := db.NewStore(filepath.Join(dir, "keymap_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	path := filepath.Join(dir, "keymap.json")
+	os.WriteFile(path, []byte(`{"Mute": {"action": "none"}}`), 0644)
+	executor, err := actions.NewExecutor(path)
+	if err != nil {
+		t.Fatalf("NewExecutor() erro inesperado: %v", err)
+	}
+	defer executor.Stop()
+
+	server := NewServer(jabra.NewMonitor(nil, "TEST-SERIAL", store), store)
+	server.SetExecutor(executor)
+
+	reload := func() (int, actions.KeyMapStatus) {
+		req, _ := http.NewRequest("POST", "/api/keymap/reload", nil)
+		rr := httptest.NewRecorder()
+		server.handleKeyMapReload(rr, req)
+		var status actions.KeyMapStatus
+		json.NewDecoder(rr.Body).Decode(&status)
+		return rr.Code, status
+	}
+
+	os.WriteFile(path, []byte(`{"Mute": {"action": "notify"}}`), 0644)
+	if code, status := reload(); code != http.StatusUnprocessableEntity || len(status.Problems) != 1 || status.Mappings != 1 {
+		t.Errorf("reload inválido = %d, %+v", code, status)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/keymap", nil)
+	rr := httptest.NewRecorder()
+	server.handleKeyMap(rr, req)
+	var resp struct {
+		Status actions.KeyMapStatus `json:"status"`
+		KeyMap actions.KeyMap       `json:"keymap"`
+	}
+	json.NewDecoder(rr.Body).Decode(&resp)
+	if rr.Code != http.StatusOK || resp.Status.Error == "" || resp.KeyMap["Mute"].Type != actions.ActionNone {
+		t.Errorf("GET /api/keymap deveria trazer o erro e o keymap anterior: %d, %+v", rr.Code, resp)
+	}
+
+	os.WriteFile(path, []byte(`{"Mute": {"action": "notify", "message": "ok"}}`), 0644)
+	if code, status := reload(); code != http.StatusOK || status.Error != "" {
+		t.Errorf("reload válido = %d, %+v", code, status)
+	}
 }
@@ -0,0 +1,175 @@
+// Package audio integra o agente ao sistema de áudio do SO: o mute do
+// microfone padrão (source) acompanha o mute do headset e vice-versa. O
+// acesso ao sistema de áudio passa por um Backend (pactl, wpctl), que por
+// sua vez executa comandos por um Runner substituível nos testes.
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnsupported indica que nenhum sistema de áudio suportado foi encontrado
+var ErrUnsupported = errors.New("audio: no supported audio system")
+
+// Backend lê e altera o mute do microfone padrão do SO
+type Backend interface {
+	// Name identifica o backend nos logs (ex.: "pactl")
+	Name() string
+
+	// SourceMute retorna o mute atual do microfone padrão
+	SourceMute(ctx context.Context) (bool, error)
+
+	// SetSourceMute altera o mute do microfone padrão
+	SetSourceMute(ctx context.Context, mute bool) error
+}
+
+// Runner executa um comando e retorna sua saída padrão
+type Runner interface {
+	Run(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// ExecRunner executa os comandos com os/exec
+type ExecRunner struct{}
+
+// Run executa o comando; falhas incluem a saída de erro do comando
+func (r ExecRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, err := r.command(ctx, name, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return out, fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return out, fmt.Errorf("%s: %w", name, err)
+	}
+	return out, nil
+}
+
+// command monta o comando com LC_ALL=C: a saída do pactl é traduzida pelo
+// locale ("Mudo: sim" em pt_BR) e os backends esperam a saída em inglês
+func (ExecRunner) command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	return cmd
+}
+
+// Pactl controla o microfone pelo pactl (PulseAudio ou pipewire-pulse)
+type Pactl struct {
+	runner Runner
+	source string
+}
+
+// NewPactl cria o backend pactl; source vazio usa o microfone padrão
+func NewPactl(runner Runner, source string) *Pactl {
+	if source == "" {
+		source = "@DEFAULT_SOURCE@"
+	}
+	return &Pactl{runner: runner, source: source}
+}
+
+// Name retorna "pactl"
+func (p *Pactl) Name() string {
+	return "pactl"
+}
+
+// SourceMute lê o mute com "pactl get-source-mute" (saída "Mute: yes")
+func (p *Pactl) SourceMute(ctx context.Context) (bool, error) {
+	out, err := p.runner.Run(ctx, "pactl", "get-source-mute", p.source)
+	if err != nil {
+		return false, err
+	}
+	value, ok := strings.CutPrefix(strings.TrimSpace(string(out)), "Mute:")
+	if !ok {
+		return false, fmt.Errorf("pactl: unexpected output %q", out)
+	}
+	switch strings.TrimSpace(value) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("pactl: unexpected mute value %q", value)
+}
+
+// SetSourceMute altera o mute com "pactl set-source-mute"
+func (p *Pactl) SetSourceMute(ctx context.Context, mute bool) error {
+	_, err := p.runner.Run(ctx, "pactl", "set-source-mute", p.source, muteArg(mute))
+	return err
+}
+
+// Wpctl controla o microfone pelo wpctl (WirePlumber/PipeWire)
+type Wpctl struct {
+	runner Runner
+	node   string
+}
+
+// NewWpctl cria o backend wpctl; node vazio usa o microfone padrão
+func NewWpctl(runner Runner, node string) *Wpctl {
+	if node == "" {
+		node = "@DEFAULT_AUDIO_SOURCE@"
+	}
+	return &Wpctl{runner: runner, node: node}
+}
+
+// Name retorna "wpctl"
+func (w *Wpctl) Name() string {
+	return "wpctl"
+}
+
+// SourceMute lê o mute com "wpctl get-volume" (saída "Volume: 0.40 [MUTED]")
+func (w *Wpctl) SourceMute(ctx context.Context) (bool, error) {
+	out, err := w.runner.Run(ctx, "wpctl", "get-volume", w.node)
+	if err != nil {
+		return false, err
+	}
+	line := strings.TrimSpace(string(out))
+	if !strings.HasPrefix(line, "Volume:") {
+		return false, fmt.Errorf("wpctl: unexpected output %q", out)
+	}
+	return strings.Contains(line, "[MUTED]"), nil
+}
+
+// SetSourceMute altera o mute com "wpctl set-mute"
+func (w *Wpctl) SetSourceMute(ctx context.Context, mute bool) error {
+	_, err := w.runner.Run(ctx, "wpctl", "set-mute", w.node, muteArg(mute))
+	return err
+}
+
+// muteArg converte o mute para o argumento aceito por pactl e wpctl
+func muteArg(mute bool) string {
+	if mute {
+		return "1"
+	}
+	return "0"
+}
+
+// detect escolhe o backend pelo nome: "pactl", "wpctl" ou "auto"/vazio,
+// que usa o primeiro cujo microfone padrão responde (pactl atende
+// PulseAudio e pipewire-pulse; wpctl apenas PipeWire)
+func detect(ctx context.Context, runner Runner, name string) (Backend, error) {
+	var candidates []Backend
+	switch name {
+	case "", "auto":
+		candidates = []Backend{NewPactl(runner, ""), NewWpctl(runner, "")}
+	case "pactl":
+		candidates = []Backend{NewPactl(runner, "")}
+	case "wpctl":
+		candidates = []Backend{NewWpctl(runner, "")}
+	default:
+		return nil, fmt.Errorf("audio: unknown backend %q", name)
+	}
+
+	var errs []error
+	for _, backend := range candidates {
+		if _, err := backend.SourceMute(ctx); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return backend, nil
+	}
+	return nil, fmt.Errorf("%w: %w", ErrUnsupported, errors.Join(errs...))
+}
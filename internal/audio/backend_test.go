@@ -0,0 +1,112 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// scriptRunner responde aos comandos pela linha de comando completa e
+// registra as chamadas
+type scriptRunner struct {
+	outputs map[string]string
+	calls   []string
+}
+
+func (r *scriptRunner) Run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := strings.Join(append([]string{name}, args...), " ")
+	r.calls = append(r.calls, cmd)
+	out, ok := r.outputs[cmd]
+	if !ok {
+		return nil, errors.New(name + ": executable file not found")
+	}
+	return []byte(out), nil
+}
+
+func TestBackendsParseMute(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		backend func(Runner) Backend
+		cmd     string
+		out     string
+		want    bool
+		wantErr bool
+	}{
+		{"pactl yes", func(r Runner) Backend { return NewPactl(r, "") }, "pactl get-source-mute @DEFAULT_SOURCE@", "Mute: yes\n", true, false},
+		{"pactl no", func(r Runner) Backend { return NewPactl(r, "") }, "pactl get-source-mute @DEFAULT_SOURCE@", "Mute: no\n", false, false},
+		{"pactl inválido", func(r Runner) Backend { return NewPactl(r, "") }, "pactl get-source-mute @DEFAULT_SOURCE@", "Volume: 100%\n", false, true},
+		{"wpctl muted", func(r Runner) Backend { return NewWpctl(r, "") }, "wpctl get-volume @DEFAULT_AUDIO_SOURCE@", "Volume: 0.40 [MUTED]\n", true, false},
+		{"wpctl ativo", func(r Runner) Backend { return NewWpctl(r, "") }, "wpctl get-volume @DEFAULT_AUDIO_SOURCE@", "Volume: 0.40\n", false, false},
+		{"wpctl inválido", func(r Runner) Backend { return NewWpctl(r, "") }, "wpctl get-volume @DEFAULT_AUDIO_SOURCE@", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &scriptRunner{outputs: map[string]string{tt.cmd: tt.out}}
+			got, err := tt.backend(runner).SourceMute(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SourceMute() erro = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SourceMute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	runner := &scriptRunner{outputs: map[string]string{
+		"pactl set-source-mute alsa_input.usb-Jabra 1": "",
+		"wpctl set-mute 42 0":                          "",
+	}}
+	if err := NewPactl(runner, "alsa_input.usb-Jabra").SetSourceMute(ctx, true); err != nil {
+		t.Errorf("pactl SetSourceMute() erro inesperado: %v", err)
+	}
+	if err := NewWpctl(runner, "42").SetSourceMute(ctx, false); err != nil {
+		t.Errorf("wpctl SetSourceMute() erro inesperado: %v", err)
+	}
+}
+
+func TestExecRunnerLocale(t *testing.T) {
+	t.Setenv("LC_ALL", "pt_BR.UTF-8")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+
+	cmd := ExecRunner{}.command(context.Background(), "pactl", "get-source-mute", "@DEFAULT_SOURCE@")
+	want := append(os.Environ(), "LC_ALL=C")
+	if !slices.Equal(cmd.Env, want) {
+		t.Errorf("ambiente do comando = %v, want %v", cmd.Env, want)
+	}
+
+	// O último LC_ALL prevalece sobre o do agente
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh não encontrado")
+	}
+	out, err := ExecRunner{}.Run(context.Background(), "sh", "-c", "echo $LC_ALL")
+	if err != nil {
+		t.Fatalf("Run() erro inesperado: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "C" {
+		t.Errorf("LC_ALL no comando = %q, want C", got)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	ctx := context.Background()
+	wpctlOnly := &scriptRunner{outputs: map[string]string{
+		"wpctl get-volume @DEFAULT_AUDIO_SOURCE@": "Volume: 1.00\n",
+	}}
+
+	// auto: sem pactl, usa wpctl
+	backend, err := detect(ctx, wpctlOnly, "auto")
+	if err != nil || backend.Name() != "wpctl" {
+		t.Errorf("detect(auto) = %v, %v; want wpctl", backend, err)
+	}
+	if _, err := detect(ctx, wpctlOnly, "pactl"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("detect(pactl) sem pactl deveria retornar ErrUnsupported: %v", err)
+	}
+	if _, err := detect(ctx, wpctlOnly, "alsa"); err == nil || errors.Is(err, ErrUnsupported) {
+		t.Errorf("backend desconhecido deveria ser rejeitado: %v", err)
+	}
+}
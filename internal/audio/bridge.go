@@ -0,0 +1,203 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+	"github.com/aiknow/acc_jabra_agent/internal/models"
+	"github.com/aiknow/acc_jabra_agent/internal/supervisor"
+)
+
+// MuteSource é a origem dos eventos de mute aplicados a partir do SO
+const MuteSource = "os"
+
+const (
+	// DefaultPollInterval é o intervalo de leitura do mute do SO
+	DefaultPollInterval = time.Second
+
+	// commandTimeout limita cada comando do backend
+	commandTimeout = 2 * time.Second
+)
+
+// Headset é o lado do dispositivo da sincronização (implementado pelo
+// jabra.Monitor)
+type Headset interface {
+	// GetTelemetry retorna a telemetria do dispositivo principal
+	GetTelemetry() models.TelemetryPayload
+
+	// SetMute altera o mute no dispositivo e na telemetria
+	SetMute(deviceID uint16, mute bool, source string) error
+}
+
+// Bridge mantém o mute do headset (e da telemetria) e o do microfone do SO
+// sincronizados nos dois sentidos:
+//   - headset → SO: eventos de mute do barramento com origem diferente de
+//     MuteSource alteram o microfone do SO
+//   - SO → headset: o mute do SO é lido a cada intervalo; mudanças feitas
+//     fora do agente são aplicadas ao headset com origem MuteSource
+//
+// Ao conectar um dispositivo o estado do SO prevalece, pois é ele que
+// define se o softphone envia áudio.
+type Bridge struct {
+	backend  Backend
+	headset  Headset
+	interval time.Duration
+
+	// Serializa os comandos do backend e protege o último estado do SO
+	mu      sync.Mutex
+	osMuted bool
+	known   bool // osMuted já foi lido desde a última conexão
+	failing bool // Última leitura falhou (evita log a cada intervalo)
+
+	lifecycleMu sync.Mutex
+	cancel      context.CancelFunc
+	workers     *supervisor.Supervisor
+}
+
+// NewBridge cria a ponte entre o backend de áudio e o headset
+func NewBridge(backend Backend, headset Headset) *Bridge {
+	return &Bridge{
+		backend:  backend,
+		headset:  headset,
+		interval: DefaultPollInterval,
+	}
+}
+
+// SetPollInterval altera o intervalo de leitura do mute do SO. Deve ser
+// chamado antes de Start.
+func (b *Bridge) SetPollInterval(interval time.Duration) {
+	b.interval = interval
+}
+
+// SubscribeEvents assina as mudanças de mute do headset e as conexões de
+// dispositivo no barramento
+func (b *Bridge) SubscribeEvents(bus *events.Bus) *events.Subscription {
+	return bus.Handle(events.Options{
+		Name:  "audio",
+		Kinds: []events.Kind{events.KindMute, events.KindDeviceAttached},
+	}, b.handleEvent)
+}
+
+// handleEvent aplica ao SO o mute alterado no headset
+func (b *Bridge) handleEvent(event events.Event) {
+	switch e := event.(type) {
+	case events.Mute:
+		// Mudança que veio do próprio SO: nada a propagar
+		if e.Source == MuteSource {
+			return
+		}
+		// Apenas o dispositivo principal controla o microfone do SO
+		if b.headset.GetTelemetry().DeviceID != e.DeviceID {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+		defer cancel()
+		if err := b.setOSMute(ctx, e.Muted); err != nil {
+			log.Printf("[Audio] Erro ao aplicar mute no SO (%s): %v", b.backend.Name(), err)
+		}
+
+	case events.DeviceAttached:
+		// Força a próxima leitura a alinhar o headset ao SO
+		b.mu.Lock()
+		b.known = false
+		b.mu.Unlock()
+	}
+}
+
+// setOSMute altera o mute do SO, se diferente do último estado conhecido
+func (b *Bridge) setOSMute(ctx context.Context, mute bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.known && b.osMuted == mute {
+		return nil
+	}
+	if err := b.backend.SetSourceMute(ctx, mute); err != nil {
+		return err
+	}
+	b.osMuted = mute
+	b.known = true
+	log.Printf("[Audio] Mute do microfone do SO: %v (%s)", mute, b.backend.Name())
+	return nil
+}
+
+// Start inicia a leitura periódica do mute do SO, supervisionada até ctx
+// ser cancelado ou Stop ser chamado
+func (b *Bridge) Start(ctx context.Context) error {
+	b.lifecycleMu.Lock()
+	defer b.lifecycleMu.Unlock()
+
+	if b.cancel != nil {
+		return errors.New("audio bridge already started")
+	}
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.workers = supervisor.New("Audio")
+	b.workers.Go(ctx, "mute_sync", b.poll)
+	return nil
+}
+
+// Stop encerra a leitura periódica e aguarda seu término
+func (b *Bridge) Stop() {
+	b.lifecycleMu.Lock()
+	defer b.lifecycleMu.Unlock()
+
+	if b.cancel == nil {
+		return
+	}
+	b.cancel()
+	b.workers.Wait()
+	b.cancel = nil
+}
+
+// poll lê o mute do SO a cada intervalo
+func (b *Bridge) poll(ctx context.Context) error {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		b.sync(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sync lê o mute do SO e, se mudou (ou ainda não era conhecido), aplica ao
+// headset online
+func (b *Bridge) sync(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	b.mu.Lock()
+	muted, err := b.backend.SourceMute(ctx)
+	if err != nil {
+		if !b.failing && ctx.Err() == nil {
+			log.Printf("[Audio] Erro ao ler mute do SO (%s): %v", b.backend.Name(), err)
+		}
+		b.failing = true
+		b.mu.Unlock()
+		return
+	}
+	b.failing = false
+	changed := !b.known || b.osMuted != muted
+	b.osMuted = muted
+	b.known = true
+	b.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	telemetry := b.headset.GetTelemetry()
+	if telemetry.State.Connection != "online" || telemetry.State.IsMuted == muted {
+		return
+	}
+	log.Printf("[Audio] Mute alterado no SO: %v, aplicando ao headset (ID %d)", muted, telemetry.DeviceID)
+	if err := b.headset.SetMute(telemetry.DeviceID, muted, MuteSource); err != nil {
+		log.Printf("[Audio] Erro ao aplicar mute no headset: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package audio
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+// waitFor aguarda a condição ou falha após o timeout
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout aguardando %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestBridgeTwoWayMuteSync(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "audio_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	bus := events.NewBus()
+	defer bus.Close()
+
+	sim := jabra.NewSimulationDriver(nil)
+	monitor := jabra.NewMonitor(sim, "", store)
+	monitor.SetEventBus(bus)
+	if err := monitor.Start(context.Background()); err != nil {
+		t.Fatalf("Monitor.Start() erro inesperado: %v", err)
+	}
+	defer monitor.Stop()
+
+	// Microfone já mutado no SO antes do headset conectar
+	fake := NewFake()
+	fake.SetExternalMute(true)
+	bridge := NewBridge(fake, monitor)
+	bridge.SetPollInterval(10 * time.Millisecond)
+	bridge.SubscribeEvents(bus)
+	if err := bridge.Start(context.Background()); err != nil {
+		t.Fatalf("Bridge.Start() erro inesperado: %v", err)
+	}
+	defer bridge.Stop()
+
+	run := func(cmd jabra.SimCommand) {
+		t.Helper()
+		if err := sim.Execute(cmd); err != nil {
+			t.Fatalf("Execute(%+v) erro inesperado: %v", cmd, err)
+		}
+	}
+	headsetMuted := func() bool {
+		muted, _ := sim.GetMute(1)
+		return muted && monitor.GetTelemetry().State.IsMuted
+	}
+	osMuted := func() bool {
+		muted, _ := fake.SourceMute(context.Background())
+		return muted
+	}
+
+	// Conexão: o estado do SO prevalece
+	run(jabra.SimCommand{Action: jabra.SimAttach, Device: 1})
+	waitFor(t, "headset alinhado ao mute do SO", headsetMuted)
+
+	// Headset → SO
+	run(jabra.SimCommand{Action: jabra.SimClick, Device: 1, Button: "Mute"})
+	waitFor(t, "SO sem mute após o botão", func() bool { return !osMuted() })
+
+	// SO → headset
+	fake.SetExternalMute(true)
+	waitFor(t, "headset mutado pelo SO", headsetMuted)
+
+	// Mudanças vindas do SO não voltam ao SO
+	time.Sleep(50 * time.Millisecond)
+	if sets := fake.Sets(); !slices.Equal(sets, []bool{false}) {
+		t.Errorf("SetSourceMute chamado com %v, want [false]", sets)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build linux
+
+package audio
+
+import "context"
+
+// Detect retorna o backend do sistema de áudio (ver detect)
+func Detect(ctx context.Context, runner Runner, name string) (Backend, error) {
+	return detect(ctx, runner, name)
+}
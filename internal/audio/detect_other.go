@@ -0,0 +1,10 @@
+//go:build !linux
+
+package audio
+
+import "context"
+
+// Detect não tem implementação fora do Linux (PipeWire/PulseAudio)
+func Detect(ctx context.Context, runner Runner, name string) (Backend, error) {
+	return nil, ErrUnsupported
+}
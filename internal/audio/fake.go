@@ -0,0 +1,63 @@
+package audio
+
+import (
+	"context"
+	"sync"
+)
+
+// Fake é um backend em memória para testes. SetExternalMute simula o
+// operador alterando o mute pelo sistema (painel de som, atalho de teclado).
+type Fake struct {
+	mu    sync.Mutex
+	muted bool
+	sets  []bool
+
+	// Err, se definido, é retornado por SourceMute e SetSourceMute
+	Err error
+}
+
+// NewFake cria um backend em memória com o microfone sem mute
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// Name retorna "fake"
+func (f *Fake) Name() string {
+	return "fake"
+}
+
+// SourceMute retorna o mute simulado
+func (f *Fake) SourceMute(ctx context.Context) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return false, f.Err
+	}
+	return f.muted, nil
+}
+
+// SetSourceMute altera o mute simulado e registra a chamada
+func (f *Fake) SetSourceMute(ctx context.Context, mute bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.muted = mute
+	f.sets = append(f.sets, mute)
+	return nil
+}
+
+// SetExternalMute altera o mute sem passar pelo agente
+func (f *Fake) SetExternalMute(mute bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.muted = mute
+}
+
+// Sets retorna os valores recebidos por SetSourceMute, em ordem
+func (f *Fake) Sets() []bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]bool(nil), f.sets...)
+}
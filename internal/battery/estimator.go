@@ -0,0 +1,173 @@
+// Package battery estima a autonomia dos headsets a partir do histórico de
+// leituras de bateria de cada dispositivo.
+package battery
+
+import (
+	"math"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+)
+
+// Taxas usadas enquanto não há histórico suficiente (%/min)
+const (
+	DefaultDischargeRate = 0.1
+	DefaultChargeRate    = 1.0
+)
+
+// Config define como o histórico é interpretado
+type Config struct {
+	// MaxGap descarta intervalos entre leituras maiores que este valor
+	// (dispositivo desconectado, agente parado)
+	MaxGap time.Duration
+
+	// ConfidenceMinutes é o tempo observado com o qual a confiança chega a 50%
+	ConfidenceMinutes float64
+}
+
+// DefaultConfig retorna a configuração padrão
+func DefaultConfig() Config {
+	return Config{
+		MaxGap:            20 * time.Minute,
+		ConfidenceMinutes: 120,
+	}
+}
+
+// RateSource indica de onde veio a taxa
+type RateSource string
+
+const (
+	RateObserved RateSource = "observed" // Aprendida do histórico
+	RateDefault  RateSource = "default"  // Taxa padrão, sem histórico que a sustente
+)
+
+// Rate é uma taxa aprendida para um modo de uso. Com a taxa padrão
+// ObservedMinutes e Confidence são zero.
+type Rate struct {
+	PercentPerMinute float64    `json:"percent_per_minute"`
+	ObservedMinutes  float64    `json:"observed_minutes"`
+	Confidence       float64    `json:"confidence"` // 0-1
+	Source           RateSource `json:"source"`
+}
+
+// Model contém as taxas aprendidas para um serial
+type Model struct {
+	Serial string `json:"serial"`
+	Talk   Rate   `json:"talk"`   // Descarga em chamada
+	Idle   Rate   `json:"idle"`   // Descarga fora de chamada
+	Charge Rate   `json:"charge"` // Carregamento
+}
+
+// Estimate é a previsão para a leitura atual
+type Estimate struct {
+	TimeToEmptyMinutes int     `json:"time_to_empty_minutes"`
+	TimeToFullMinutes  int     `json:"time_to_full_minutes"`
+	TalkTimeMinutes    int     `json:"talk_time_minutes"` // Autonomia se todo o restante for em chamada
+	IdleTimeMinutes    int     `json:"idle_time_minutes"` // Autonomia se todo o restante for fora de chamada
+	Confidence         float64 `json:"confidence"`        // 0-1, da taxa usada na previsão
+}
+
+// accumulator soma variação de nível e tempo observados em um modo de uso
+type accumulator struct {
+	delta   float64
+	minutes float64
+}
+
+// rate retorna a taxa observada ou, sem tempo ou variação de nível que a
+// sustente, a taxa padrão
+func (a accumulator) rate(fallback, confidenceMinutes float64) Rate {
+	if a.minutes <= 0 || a.delta <= 0 {
+		return Rate{PercentPerMinute: fallback, Source: RateDefault}
+	}
+	return Rate{
+		PercentPerMinute: a.delta / a.minutes,
+		ObservedMinutes:  a.minutes,
+		Confidence:       a.minutes / (a.minutes + confidenceMinutes),
+		Source:           RateObserved,
+	}
+}
+
+// Learn calcula as taxas a partir das leituras de um serial em ordem
+// cronológica. Cada par de leituras consecutivas com o mesmo status e
+// intervalo menor que MaxGap contribui para talk (em chamada no início do
+// intervalo), idle ou charge.
+func Learn(serial string, samples []db.BatterySample, config Config) Model {
+	var talk, idle, charge accumulator
+
+	for i := 1; i < len(samples); i++ {
+		prev, cur := samples[i-1], samples[i]
+		dt := cur.Time.Sub(prev.Time)
+		if dt <= 0 || dt > config.MaxGap || prev.Status != cur.Status {
+			continue
+		}
+		minutes := dt.Minutes()
+		delta := float64(prev.Level - cur.Level)
+
+		switch prev.Status {
+		case "discharging":
+			if prev.InCall {
+				talk.delta += delta
+				talk.minutes += minutes
+			} else {
+				idle.delta += delta
+				idle.minutes += minutes
+			}
+		case "charging":
+			charge.delta -= delta
+			charge.minutes += minutes
+		}
+	}
+
+	model := Model{
+		Serial: serial,
+		Idle:   idle.rate(DefaultDischargeRate, config.ConfidenceMinutes),
+		Charge: charge.rate(DefaultChargeRate, config.ConfidenceMinutes),
+	}
+	// Sem amostras em chamada, assume a taxa de idle sem confiança própria
+	model.Talk = talk.rate(model.Idle.PercentPerMinute, config.ConfidenceMinutes)
+	return model
+}
+
+// Estimate prevê a autonomia para o nível atual. Fora de chamada o tempo até
+// vazio usa a média ponderada pelo perfil de uso observado (talk/idle); em
+// chamada, a taxa de talk.
+func (m Model) Estimate(level int, charging, inCall bool) Estimate {
+	est := Estimate{
+		TalkTimeMinutes: minutesFor(float64(level), m.Talk.PercentPerMinute),
+		IdleTimeMinutes: minutesFor(float64(level), m.Idle.PercentPerMinute),
+	}
+
+	if charging {
+		est.TimeToFullMinutes = minutesFor(float64(100-level), m.Charge.PercentPerMinute)
+		est.Confidence = m.Charge.Confidence
+	}
+
+	rate, confidence := m.usageRate()
+	if inCall {
+		rate, confidence = m.Talk.PercentPerMinute, m.Talk.Confidence
+	}
+	est.TimeToEmptyMinutes = minutesFor(float64(level), rate)
+	if !charging {
+		est.Confidence = confidence
+	}
+	return est
+}
+
+// usageRate combina talk e idle pela proporção de tempo observada em cada um
+func (m Model) usageRate() (float64, float64) {
+	total := m.Talk.ObservedMinutes + m.Idle.ObservedMinutes
+	if total <= 0 {
+		return m.Idle.PercentPerMinute, 0
+	}
+	talkShare := m.Talk.ObservedMinutes / total
+	rate := talkShare*m.Talk.PercentPerMinute + (1-talkShare)*m.Idle.PercentPerMinute
+	confidence := talkShare*m.Talk.Confidence + (1-talkShare)*m.Idle.Confidence
+	return rate, confidence
+}
+
+func minutesFor(percent, rate float64) int {
+	if percent <= 0 || rate <= 0 {
+		return 0
+	}
+	return int(math.Round(percent / rate))
+}
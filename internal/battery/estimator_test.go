@@ -0,0 +1,120 @@
+package battery
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+)
+
+// series gera leituras a cada 5 minutos com a variação informada por leitura
+func series(start time.Time, level, step, count int, status string, inCall bool) []db.BatterySample {
+	samples := make([]db.BatterySample, count)
+	for i := range samples {
+		samples[i] = db.BatterySample{
+			Level:  level + i*step,
+			Status: status,
+			InCall: inCall,
+			Time:   start.Add(time.Duration(i) * 5 * time.Minute),
+		}
+	}
+	return samples
+}
+
+func TestLearnSeparatesTalkIdleAndCharge(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	var samples []db.BatterySample
+	samples = append(samples, series(start, 100, -1, 13, "discharging", false)...)               // 0.2 %/min por 1h
+	samples = append(samples, series(start.Add(2*time.Hour), 80, -2, 7, "discharging", true)...) // 0.4 %/min por 30min
+	samples = append(samples, series(start.Add(4*time.Hour), 40, 10, 5, "charging", false)...)   // 2 %/min por 20min
+	model := Learn("SERIAL", samples, DefaultConfig())
+
+	assertRate := func(name string, got Rate, want, minutes float64) {
+		t.Helper()
+		if math.Abs(got.PercentPerMinute-want) > 1e-9 || got.ObservedMinutes != minutes {
+			t.Errorf("%s: %+v, esperado %.2f %%/min em %.0f min", name, got, want, minutes)
+		}
+		if got.Confidence <= 0 || got.Confidence >= 1 {
+			t.Errorf("%s: confiança fora de (0,1): %v", name, got.Confidence)
+		}
+		if got.Source != RateObserved {
+			t.Errorf("%s: origem %q, esperado %q", name, got.Source, RateObserved)
+		}
+	}
+	assertRate("idle", model.Idle, 0.2, 60)
+	assertRate("talk", model.Talk, 0.4, 30)
+	assertRate("charge", model.Charge, 2, 20)
+	if model.Idle.Confidence <= model.Talk.Confidence {
+		t.Error("mais tempo observado deveria dar mais confiança")
+	}
+}
+
+func TestLearnSkipsGapsAndStatusChanges(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	samples := []db.BatterySample{
+		{Level: 90, Status: "discharging", Time: start},
+		{Level: 89, Status: "discharging", Time: start.Add(5 * time.Minute)},
+		// Desconectado durante a noite: não conta
+		{Level: 20, Status: "discharging", Time: start.Add(12 * time.Hour)},
+		// Mudança de status: não conta
+		{Level: 25, Status: "charging", Time: start.Add(12*time.Hour + 5*time.Minute)},
+	}
+	model := Learn("SERIAL", samples, DefaultConfig())
+
+	if model.Idle.ObservedMinutes != 5 || math.Abs(model.Idle.PercentPerMinute-0.2) > 1e-9 {
+		t.Errorf("idle incorreto: %+v", model.Idle)
+	}
+	if model.Charge.ObservedMinutes != 0 || model.Charge.PercentPerMinute != DefaultChargeRate || model.Charge.Source != RateDefault {
+		t.Errorf("charge deveria usar o padrão: %+v", model.Charge)
+	}
+}
+
+func TestLearnFallbackHasNoObservedMinutes(t *testing.T) {
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	// 1h fora de chamada sem variação de nível: a taxa observada seria zero
+	model := Learn("SERIAL", series(start, 90, 0, 13, "discharging", false), DefaultConfig())
+
+	want := Rate{PercentPerMinute: DefaultDischargeRate, Source: RateDefault}
+	if model.Idle != want {
+		t.Errorf("idle = %+v, esperado %+v", model.Idle, want)
+	}
+	if model.Talk.Source != RateDefault || model.Talk.ObservedMinutes != 0 {
+		t.Errorf("talk deveria usar o padrão: %+v", model.Talk)
+	}
+	if est := model.Estimate(50, false, false); est.Confidence != 0 || est.TimeToEmptyMinutes != 500 {
+		t.Errorf("estimativa com a taxa padrão = %+v", est)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	empty := Learn("NOVO", nil, DefaultConfig())
+	if est := empty.Estimate(50, false, false); est.TimeToEmptyMinutes != 500 || est.Confidence != 0 {
+		t.Errorf("sem histórico: %+v", est)
+	}
+
+	model := Model{
+		Talk:   Rate{PercentPerMinute: 0.5, ObservedMinutes: 100, Confidence: 0.4},
+		Idle:   Rate{PercentPerMinute: 0.1, ObservedMinutes: 300, Confidence: 0.8},
+		Charge: Rate{PercentPerMinute: 2, ObservedMinutes: 60, Confidence: 0.3},
+	}
+
+	// Perfil observado: 25% em chamada → 0.2 %/min
+	est := model.Estimate(60, false, false)
+	if est.TimeToEmptyMinutes != 300 || est.TalkTimeMinutes != 120 || est.IdleTimeMinutes != 600 {
+		t.Errorf("fora de chamada: %+v", est)
+	}
+	if math.Abs(est.Confidence-0.7) > 1e-9 {
+		t.Errorf("confiança ponderada incorreta: %v", est.Confidence)
+	}
+
+	if est := model.Estimate(60, false, true); est.TimeToEmptyMinutes != 120 || est.Confidence != 0.4 {
+		t.Errorf("em chamada: %+v", est)
+	}
+
+	if est := model.Estimate(60, true, false); est.TimeToFullMinutes != 20 || est.Confidence != 0.3 {
+		t.Errorf("carregando: %+v", est)
+	}
+}
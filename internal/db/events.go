@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+)
+
+// SubscribeEvents grava em hardware_events as conexões, botões, transições
+// de chamada, mute, sensor de uso, eventos do socket e alterações de configuração
+// publicados no barramento. Leituras de bateria ficam no battery_history (LogBatterySample).
+func (s *Store) SubscribeEvents(bus *events.Bus) *events.Subscription {
+	return bus.Handle(events.Options{
+		Name:   "store",
+		Buffer: 256,
+		Kinds: []events.Kind{
+			events.KindDeviceAttached,
+			events.KindDeviceDetached,
+			events.KindButton,
+			events.KindCallState,
+			events.KindSocket,
+			events.KindSettingChanged,
+			events.KindWear,
+			events.KindMute,
+		},
+	}, s.logBusEvent)
+}
+
+// logBusEvent grava um evento do barramento
+func (s *Store) logBusEvent(event events.Event) {
+	if eventType, desc, ok := describeEvent(event); ok {
+		s.LogEvent(eventType, desc)
+	}
+}
+
+// describeEvent retorna o tipo e a descrição de hardware_events do evento;
+// ok false para eventos que não são gravados
+func describeEvent(event events.Event) (eventType, desc string, ok bool) {
+	switch e := event.(type) {
+	case events.DeviceAttached:
+		return "connection_change", fmt.Sprintf("%s (ID %d) Status: online", e.Name, e.DeviceID), true
+	case events.DeviceDetached:
+		return "connection_change", fmt.Sprintf("%s (ID %d) Status: offline", e.Name, e.DeviceID), true
+	case events.Button:
+		// Apenas o press: o release não é uma ação do operador
+		if !e.Pressed {
+			return "", "", false
+		}
+		return "button", fmt.Sprintf("%s (ID %d)", e.Button, e.DeviceID), true
+	case events.CallState:
+		return "call_state", fmt.Sprintf("%s → %s (%s, %s)", e.From, e.To, e.Event, e.Source), true
+	case events.SocketEvent:
+		if e.Ramal != "" {
+			return "socket_event", fmt.Sprintf("%s (ramal %s)", e.Name, e.Ramal), true
+		}
+		return "socket_event", e.Name, true
+	case events.Mute:
+		if e.Muted {
+			return "mute_change", fmt.Sprintf("Mute ativado (ID %d, %s)", e.DeviceID, e.Source), true
+		}
+		return "mute_change", fmt.Sprintf("Mute desativado (ID %d, %s)", e.DeviceID, e.Source), true
+	case events.Wear:
+		if e.Worn {
+			return "wear_change", fmt.Sprintf("Headset colocado (ID %d, %s)", e.DeviceID, e.CallState), true
+		}
+		return "wear_change", fmt.Sprintf("Headset retirado (ID %d, %s)", e.DeviceID, e.CallState), true
+	case events.SettingChanged:
+		return "setting_change", fmt.Sprintf("%s=%v (ID %d)", e.Key, e.Value, e.DeviceID), true
+	}
+	return "", "", false
+}
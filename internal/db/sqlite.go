@@ -2,9 +2,11 @@ package db
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 
 	_ "modernc.org/sqlite"
 )
@@ -50,10 +52,62 @@ func (s *Store) initSchema() error {
 		key TEXT PRIMARY KEY,
 		value TEXT
 	);`
-	_, err := s.db.Exec(query)
+	if _, err := s.db.Exec(query); err != nil {
+		return err
+	}
+	return s.migrate()
+}
+
+// migrate adiciona colunas introduzidas após a criação do schema original
+func (s *Store) migrate() error {
+	columns := []struct{ table, name, def string }{
+		{"battery_history", "serial", "TEXT DEFAULT ''"},
+		{"battery_history", "in_call", "INTEGER DEFAULT 0"},
+	}
+	for _, c := range columns {
+		exists, err := s.hasColumn(c.table, c.name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.name, c.def)); err != nil {
+			return fmt.Errorf("migrate %s.%s: %w", c.table, c.name, err)
+		}
+	}
+
+	_, err := s.db.Exec("CREATE INDEX IF NOT EXISTS idx_battery_history_serial ON battery_history (serial, timestamp)")
 	return err
 }
 
+// hasColumn verifica se a tabela já tem a coluna
+func (s *Store) hasColumn(table, column string) (bool, error) {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			ctype     string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &ctype, &notNull, &dfltValue, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
 func (s *Store) GetSetting(key, defaultValue string) string {
 	var value string
 	err := s.db.QueryRow("SELECT value FROM settings WHERE key = ?", key).Scan(&value)
@@ -93,6 +147,70 @@ func (s *Store) LogBattery(level int, status string) {
 	}
 }
 
+// BatterySample é uma leitura de bateria de um dispositivo
+type BatterySample struct {
+	Serial string
+	Level  int
+	Status string // discharging, charging, fully charged
+	InCall bool
+	Time   time.Time
+}
+
+// timestampLayout é o formato de CURRENT_TIMESTAMP do SQLite (UTC)
+const timestampLayout = "2006-01-02 15:04:05"
+
+// LogBatterySample grava uma leitura de bateria associada ao serial do
+// dispositivo; Time zero usa o horário atual
+func (s *Store) LogBatterySample(sample BatterySample) {
+	if sample.Time.IsZero() {
+		sample.Time = time.Now()
+	}
+	_, err := s.db.Exec("INSERT INTO battery_history (level, status, serial, in_call, timestamp) VALUES (?, ?, ?, ?, ?)",
+		sample.Level, sample.Status, sample.Serial, sample.InCall, sample.Time.UTC().Format(timestampLayout))
+	if err != nil {
+		log.Printf("[DB] Erro ao logar bateria: %v", err)
+	}
+}
+
+// GetBatterySamples retorna as leituras de um serial desde o horário
+// informado, em ordem cronológica
+func (s *Store) GetBatterySamples(serial string, since time.Time) ([]BatterySample, error) {
+	rows, err := s.db.Query(
+		"SELECT level, status, in_call, timestamp FROM battery_history WHERE serial = ? AND timestamp >= ? ORDER BY timestamp ASC, id ASC",
+		serial, since.UTC().Format(timestampLayout))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []BatterySample
+	for rows.Next() {
+		var (
+			sample BatterySample
+			ts     string
+		)
+		if err := rows.Scan(&sample.Level, &sample.Status, &sample.InCall, &ts); err != nil {
+			return nil, err
+		}
+		t, err := parseTimestamp(ts)
+		if err != nil {
+			continue
+		}
+		sample.Serial = serial
+		sample.Time = t
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
+
+// parseTimestamp interpreta os timestamps gravados pelo SQLite ou pelo driver
+func parseTimestamp(ts string) (time.Time, error) {
+	if t, err := time.Parse(timestampLayout, ts); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, ts)
+}
+
 func (s *Store) LogEvent(eventType, desc string) {
 	_, err := s.db.Exec("INSERT INTO hardware_events (event_type, description) VALUES (?, ?)", eventType, desc)
 	if err != nil {
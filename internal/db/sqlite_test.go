@@ -1,8 +1,13 @@
 package db
 
 import (
+	"database/sql"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/events"
 )
 
 func TestSQLiteStore(t *testing.T) {
@@ -35,3 +40,87 @@ func TestSQLiteStore(t *testing.T) {
 		// Se não deu erro no LogEvent, consideramos OK por agora
 	})
 }
+
+func TestBatterySamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.db")
+
+	// Banco criado pela versão anterior, sem serial/in_call
+	legacy, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legacy.Exec(`CREATE TABLE battery_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		level INTEGER,
+		status TEXT,
+		timestamp DATETIME DEFAULT CURRENT_TIMESTAMP
+	); INSERT INTO battery_history (level, status) VALUES (50, 'periodic_check');`); err != nil {
+		t.Fatal(err)
+	}
+	legacy.Close()
+
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("Erro ao migrar store: %v", err)
+	}
+
+	start := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	store.LogBatterySample(BatterySample{Serial: "A", Level: 90, Status: "discharging", Time: start.Add(5 * time.Minute), InCall: true})
+	store.LogBatterySample(BatterySample{Serial: "A", Level: 91, Status: "discharging", Time: start})
+	store.LogBatterySample(BatterySample{Serial: "B", Level: 10, Status: "charging", Time: start})
+
+	samples, err := store.GetBatterySamples("A", start.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("esperado 2 leituras do serial A, obtido %d", len(samples))
+	}
+	if samples[0].Level != 91 || samples[1].Level != 90 || !samples[1].InCall || samples[0].InCall {
+		t.Errorf("leituras fora de ordem ou incorretas: %+v", samples)
+	}
+	if !samples[1].Time.Equal(start.Add(5 * time.Minute)) {
+		t.Errorf("horário incorreto: %v", samples[1].Time)
+	}
+
+	if history, _ := store.GetBatteryHistory(10); len(history) != 4 {
+		t.Errorf("histórico legado deveria continuar legível: %d registros", len(history))
+	}
+}
+
+func TestStoreSubscribeEvents(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "events.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+
+	bus := events.NewBus()
+	store.SubscribeEvents(bus)
+	bus.Publish(events.DeviceAttached{DeviceID: 1, Name: "Jabra Engage 55"})
+	bus.Publish(events.Button{DeviceID: 1, Button: "Mute", Pressed: true})
+	bus.Publish(events.Button{DeviceID: 1, Button: "Mute", Pressed: false})
+	bus.Publish(events.Battery{DeviceID: 1, Level: 80})
+	bus.Publish(events.CallState{DeviceID: 1, From: "idle", To: "in_call", Event: "answer", Source: "button"})
+	bus.Publish(events.SettingChanged{DeviceID: 1, Key: "sidetone", Value: "high"})
+	bus.Publish(events.Wear{DeviceID: 1, Worn: false, CallState: "idle"})
+	bus.Publish(events.Mute{DeviceID: 1, Muted: true, Source: "os"})
+	bus.Close() // Aguarda o assinante gravar a fila
+
+	logs, err := store.GetLogs(100)
+	if err != nil {
+		t.Fatalf("GetLogs() erro inesperado: %v", err)
+	}
+	got := make(map[string]int)
+	for _, entry := range logs {
+		got[entry["type"].(string)]++
+	}
+	want := map[string]int{"connection_change": 1, "button": 1, "call_state": 1, "setting_change": 1, "wear_change": 1, "mute_change": 1}
+	if len(got) != len(want) {
+		t.Fatalf("eventos gravados = %v, want %v", got, want)
+	}
+	for eventType, n := range want {
+		if got[eventType] != n {
+			t.Errorf("%s gravado %d vezes, want %d", eventType, got[eventType], n)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package events
+
+import (
+	"log"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Policy define o que acontece quando a fila de um assinante está cheia.
+// Publish nunca bloqueia: o assinante lento é quem perde eventos.
+type Policy string
+
+const (
+	// DropNewest descarta o evento novo (mantém a fila)
+	DropNewest Policy = "drop_newest"
+
+	// DropOldest descarta o evento mais antigo da fila para caber o novo
+	DropOldest Policy = "drop_oldest"
+
+	// Disconnect cancela a assinatura; o canal do assinante é fechado
+	Disconnect Policy = "disconnect"
+)
+
+// DefaultBuffer é o tamanho da fila de um assinante sem Buffer definido
+const DefaultBuffer = 64
+
+// Options configura uma assinatura
+type Options struct {
+	Name   string // Identifica o assinante nos logs
+	Buffer int    // Tamanho da fila (DefaultBuffer se zero)
+	Policy Policy // Política para fila cheia (DropNewest se vazia)
+	Kinds  []Kind // Tipos assinados; vazio assina todos
+}
+
+// Subscription é a fila de eventos de um assinante
+type Subscription struct {
+	bus  *Bus
+	opts Options
+
+	mu      sync.Mutex // Protege envios e o fechamento do canal
+	ch      chan Event
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// Events retorna o canal de eventos, fechado ao cancelar a assinatura
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Dropped retorna quantos eventos o assinante perdeu por fila cheia
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Close cancela a assinatura
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+// wants retorna true se o assinante recebe eventos do tipo informado
+func (s *Subscription) wants(kind Kind) bool {
+	return len(s.opts.Kinds) == 0 || slices.Contains(s.opts.Kinds, kind)
+}
+
+// deliver entrega o evento sem bloquear. Retorna false se a política
+// Disconnect exige cancelar a assinatura.
+func (s *Subscription) deliver(event Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return true
+	}
+	select {
+	case s.ch <- event:
+		return true
+	default:
+	}
+
+	switch s.opts.Policy {
+	case Disconnect:
+		s.drop(event)
+		return false
+	case DropOldest:
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- event:
+		default:
+		}
+		s.drop(event)
+	default:
+		s.drop(event)
+	}
+	return true
+}
+
+// drop contabiliza um evento perdido, com log na primeira perda e a cada 100
+func (s *Subscription) drop(event Event) {
+	n := s.dropped.Add(1)
+	if n == 1 || n%100 == 0 {
+		log.Printf("[Events] Assinante %s lento (%s): %d eventos descartados, último %s", s.opts.Name, s.opts.Policy, n, event.Kind())
+	}
+}
+
+// close fecha o canal do assinante
+func (s *Subscription) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+// Bus distribui eventos entre os assinantes
+type Bus struct {
+	mu     sync.RWMutex
+	subs   []*Subscription
+	closed bool
+
+	// Handlers iniciados por Handle, aguardados no Close
+	handlers sync.WaitGroup
+}
+
+// NewBus cria um barramento vazio
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe cria uma assinatura. Com o barramento fechado, o canal
+// retornado já vem fechado.
+func (b *Bus) Subscribe(opts Options) *Subscription {
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBuffer
+	}
+	if opts.Policy == "" {
+		opts.Policy = DropNewest
+	}
+	s := &Subscription{bus: b, opts: opts, ch: make(chan Event, opts.Buffer)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.close()
+		return s
+	}
+	b.subs = append(b.subs, s)
+	return s
+}
+
+// Handle assina e processa os eventos numa goroutine própria até a
+// assinatura ser cancelada ou o barramento fechado. Panics do handler são
+// registrados e o processamento continua.
+func (b *Bus) Handle(opts Options, handler func(Event)) *Subscription {
+	s := b.Subscribe(opts)
+	b.handlers.Add(1)
+	go func() {
+		defer b.handlers.Done()
+		for event := range s.Events() {
+			dispatch(s.opts.Name, handler, event)
+		}
+	}()
+	return s
+}
+
+// dispatch executa o handler convertendo panics em log
+func dispatch(name string, handler func(Event), event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[Events] Panic no assinante %s (%s): %v\n%s", name, event.Kind(), r, debug.Stack())
+		}
+	}()
+	handler(event)
+}
+
+// Publish entrega o evento a todos os assinantes do tipo, sem bloquear
+func (b *Bus) Publish(event Event) {
+	kind := event.Kind()
+
+	var slow []*Subscription
+	b.mu.RLock()
+	for _, s := range b.subs {
+		if s.wants(kind) && !s.deliver(event) {
+			slow = append(slow, s)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, s := range slow {
+		log.Printf("[Events] Assinante %s desconectado por lentidão", s.opts.Name)
+		b.unsubscribe(s)
+	}
+}
+
+// unsubscribe remove a assinatura e fecha seu canal
+func (b *Bus) unsubscribe(s *Subscription) {
+	b.mu.Lock()
+	b.subs = slices.DeleteFunc(b.subs, func(sub *Subscription) bool { return sub == s })
+	b.mu.Unlock()
+	s.close()
+}
+
+// Close cancela todas as assinaturas e aguarda os handlers terminarem os
+// eventos já enfileirados
+func (b *Bus) Close() {
+	b.mu.Lock()
+	subs := b.subs
+	b.subs = nil
+	b.closed = true
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		s.close()
+	}
+	b.handlers.Wait()
+}
@@ -0,0 +1,122 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// next recebe um evento ou falha após o timeout
+func next(t *testing.T, s *Subscription) Event {
+	t.Helper()
+	select {
+	case event, ok := <-s.Events():
+		if !ok {
+			t.Fatal("assinatura fechada")
+		}
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("timeout aguardando evento")
+	}
+	return nil
+}
+
+func TestBusFiltersByKind(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	buttons := bus.Subscribe(Options{Name: "buttons", Kinds: []Kind{KindButton}})
+	all := bus.Subscribe(Options{Name: "all"})
+
+	bus.Publish(Battery{DeviceID: 1, Level: 50})
+	bus.Publish(Button{DeviceID: 1, Button: "Mute", Pressed: true})
+
+	if event := next(t, buttons); event.Kind() != KindButton {
+		t.Errorf("assinante de botões recebeu %s", event.Kind())
+	}
+	if event := next(t, all); event.Kind() != KindBattery {
+		t.Errorf("primeiro evento = %s, want %s", event.Kind(), KindBattery)
+	}
+	if button, ok := next(t, all).(Button); !ok || button.Button != "Mute" {
+		t.Errorf("segundo evento inesperado: %+v", button)
+	}
+}
+
+func TestBusSlowConsumerPolicies(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	newest := bus.Subscribe(Options{Name: "newest", Buffer: 2, Policy: DropNewest})
+	oldest := bus.Subscribe(Options{Name: "oldest", Buffer: 2, Policy: DropOldest})
+	disconnect := bus.Subscribe(Options{Name: "disconnect", Buffer: 2, Policy: Disconnect})
+
+	// Publish nunca bloqueia, mesmo sem ninguém consumindo
+	done := make(chan struct{})
+	go func() {
+		for level := 1; level <= 5; level++ {
+			bus.Publish(Battery{Level: level})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish bloqueou com assinantes lentos")
+	}
+
+	levels := func(s *Subscription) []int {
+		var got []int
+		for len(got) < 2 {
+			got = append(got, next(t, s).(Battery).Level)
+		}
+		return got
+	}
+	if got := levels(newest); got[0] != 1 || got[1] != 2 {
+		t.Errorf("DropNewest manteve %v, want [1 2]", got)
+	}
+	if got := levels(oldest); got[0] != 4 || got[1] != 5 {
+		t.Errorf("DropOldest manteve %v, want [4 5]", got)
+	}
+	if newest.Dropped() != 3 || oldest.Dropped() != 3 {
+		t.Errorf("Dropped() = %d/%d, want 3/3", newest.Dropped(), oldest.Dropped())
+	}
+
+	// Disconnect: recebe o que coube e o canal é fechado
+	levels(disconnect)
+	if _, ok := <-disconnect.Events(); ok {
+		t.Error("assinante Disconnect deveria ter o canal fechado")
+	}
+}
+
+func TestBusHandleAndClose(t *testing.T) {
+	bus := NewBus()
+
+	var mu sync.Mutex
+	var got []string
+	bus.Handle(Options{Name: "handler", Kinds: []Kind{KindSocket}}, func(event Event) {
+		socket := event.(SocketEvent)
+		if socket.Name == "panic" {
+			panic("handler com defeito")
+		}
+		mu.Lock()
+		got = append(got, socket.Name)
+		mu.Unlock()
+	})
+
+	bus.Publish(SocketEvent{Name: "panic"})
+	bus.Publish(SocketEvent{Name: "ligacao_interna"})
+	bus.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 1 || got[0] != "ligacao_interna" {
+		t.Errorf("handler deveria continuar após panic e drenar a fila no Close: %v", got)
+	}
+
+	// Após Close, novas assinaturas já vêm fechadas e Publish é no-op
+	late := bus.Subscribe(Options{Name: "late"})
+	bus.Publish(SocketEvent{Name: "connected"})
+	if _, ok := <-late.Events(); ok {
+		t.Error("assinatura após Close deveria vir fechada")
+	}
+}
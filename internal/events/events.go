@@ -0,0 +1,126 @@
+// Package events é o barramento interno (pub/sub) do agente: drivers e o
+// socket publicam eventos tipados; executor, whitelist, store, API e o
+// próprio monitor os consomem sem ligações diretas entre si.
+package events
+
+// Kind identifica o tipo de um evento
+type Kind string
+
+const (
+	KindDeviceAttached Kind = "device_attached"
+	KindDeviceDetached Kind = "device_detached"
+	KindButton         Kind = "button"
+	KindBattery        Kind = "battery"
+	KindCallState      Kind = "call_state"
+	KindSocket         Kind = "socket"
+	KindSettingChanged Kind = "setting_changed"
+	KindWear           Kind = "wear"
+	KindMute           Kind = "mute"
+	KindVolume         Kind = "volume"
+)
+
+// Event é um evento publicado no barramento
+type Event interface {
+	Kind() Kind
+}
+
+// DeviceAttached é publicado quando um dispositivo fica online
+type DeviceAttached struct {
+	DeviceID   uint16  `json:"device_id"`
+	Serial     string  `json:"serial"`
+	Name       string  `json:"name"`
+	Model      string  `json:"model"`
+	FormFactor string  `json:"form_factor"`
+	ParentID   *uint16 `json:"parent_id,omitempty"`
+}
+
+// DeviceDetached é publicado quando um dispositivo fica offline
+type DeviceDetached struct {
+	DeviceID uint16 `json:"device_id"`
+	Serial   string `json:"serial"`
+	Name     string `json:"name"`
+}
+
+// Button é um evento de botão de um dispositivo online
+type Button struct {
+	DeviceID uint16 `json:"device_id"`
+	Serial   string `json:"serial"`
+	Button   string `json:"button"` // Nome do botão (ex.: "Mute", "OffHook")
+	Pressed  bool   `json:"pressed"`
+}
+
+// Battery é uma leitura de bateria de um dispositivo online
+type Battery struct {
+	DeviceID uint16 `json:"device_id"`
+	Serial   string `json:"serial"`
+	Level    int    `json:"level"`
+	Status   string `json:"status"` // discharging, charging, fully charged
+}
+
+// CallState é uma transição da máquina de chamada de um dispositivo
+type CallState struct {
+	DeviceID uint16 `json:"device_id"`
+	Serial   string `json:"serial"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Event    string `json:"event"`  // Evento que causou a transição (ex.: "answer")
+	Source   string `json:"source"` // Origem (ex.: "button", "socket:ligacao_atendida")
+}
+
+// Nomes de SocketEvent além dos eventos recebidos do servidor
+const (
+	SocketConnected    = "connected"
+	SocketDisconnected = "disconnected"
+)
+
+// SocketEvent é um evento do servidor ACC (notificar_carro, ligacao_*) ou
+// uma mudança de conexão do cliente Socket.IO
+type SocketEvent struct {
+	Name     string `json:"name"`
+	Ramal    string `json:"ramal,omitempty"` // Ramal que atendeu/solicitou
+	Own      bool   `json:"own"`             // Ramal é o configurado neste agente
+	TemCarro bool   `json:"tem_carro,omitempty"`
+}
+
+// SettingChanged é publicado quando uma configuração do dispositivo é alterada
+type SettingChanged struct {
+	DeviceID uint16 `json:"device_id"`
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+}
+
+// Wear é publicado quando o sensor de uso detecta o headset colocado ou
+// retirado
+type Wear struct {
+	DeviceID  uint16 `json:"device_id"`
+	Serial    string `json:"serial"`
+	Worn      bool   `json:"worn"`
+	CallState string `json:"call_state"` // Estado da chamada no momento (ex.: "idle")
+}
+
+// Mute é publicado quando o mute do microfone de um dispositivo muda
+type Mute struct {
+	DeviceID uint16 `json:"device_id"`
+	Serial   string `json:"serial"`
+	Muted    bool   `json:"muted"`
+	Source   string `json:"source"` // Origem (ex.: "button", "os")
+}
+
+// Volume é publicado quando o volume de um dispositivo muda
+type Volume struct {
+	DeviceID uint16 `json:"device_id"`
+	Serial   string `json:"serial"`
+	Level    int    `json:"level"`  // 0-100
+	Source   string `json:"source"` // Origem (ex.: "button", "api", "default")
+}
+
+func (DeviceAttached) Kind() Kind { return KindDeviceAttached }
+func (DeviceDetached) Kind() Kind { return KindDeviceDetached }
+func (Button) Kind() Kind         { return KindButton }
+func (Battery) Kind() Kind        { return KindBattery }
+func (CallState) Kind() Kind      { return KindCallState }
+func (SocketEvent) Kind() Kind    { return KindSocket }
+func (SettingChanged) Kind() Kind { return KindSettingChanged }
+func (Wear) Kind() Kind           { return KindWear }
+func (Mute) Kind() Kind           { return KindMute }
+func (Volume) Kind() Kind         { return KindVolume }
@@ -0,0 +1,478 @@
+// Package hidreport interpreta descritores de relatório HID (HID 1.11, seção 6.2.2)
+// e extrai/insere valores nos relatórios de entrada e saída descritos por eles.
+package hidreport
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kind identifica o tipo de relatório de um campo
+type Kind int
+
+const (
+	Input Kind = iota
+	Output
+	Feature
+)
+
+// String retorna o nome do tipo de relatório
+func (k Kind) String() string {
+	switch k {
+	case Input:
+		return "input"
+	case Output:
+		return "output"
+	case Feature:
+		return "feature"
+	}
+	return "unknown"
+}
+
+// Flags dos itens principais Input/Output/Feature
+const (
+	FlagConstant    = 1 << 0 // Data(0) / Constant(1)
+	FlagVariable    = 1 << 1 // Array(0) / Variable(1)
+	FlagRelative    = 1 << 2 // Absolute(0) / Relative(1)
+	FlagWrap        = 1 << 3
+	FlagNonLinear   = 1 << 4
+	FlagNoPreferred = 1 << 5
+	FlagNullState   = 1 << 6
+	FlagVolatile    = 1 << 7
+)
+
+// Limites de sanidade para descritores malformados
+const (
+	maxReportCount = 1024
+	maxReportSize  = 32
+	maxReportBits  = 8 * 4096
+	maxStackDepth  = 16
+)
+
+var (
+	// ErrTruncated indica que o descritor termina no meio de um item
+	ErrTruncated = errors.New("hidreport: truncated descriptor")
+
+	// ErrMalformed indica um descritor estruturalmente inválido
+	ErrMalformed = errors.New("hidreport: malformed descriptor")
+)
+
+// Usage combina página (16 bits altos) e ID de uso (16 bits baixos)
+type Usage uint32
+
+// NewUsage monta um Usage a partir de página e ID
+func NewUsage(page, id uint16) Usage {
+	return Usage(uint32(page)<<16 | uint32(id))
+}
+
+// Page retorna a página do uso
+func (u Usage) Page() uint16 { return uint16(u >> 16) }
+
+// ID retorna o ID do uso dentro da página
+func (u Usage) ID() uint16 { return uint16(u) }
+
+// String formata o uso como página:ID em hexadecimal
+func (u Usage) String() string {
+	return fmt.Sprintf("%04x:%04x", u.Page(), u.ID())
+}
+
+// Field descreve um controle dentro de um relatório.
+//
+// Campos variáveis (FlagVariable) são expandidos em um Field por uso, com
+// Count=1. Campos array mantêm Count slots cujos valores são índices em
+// Usages (a partir de LogicalMin).
+type Field struct {
+	Kind       Kind
+	ReportID   uint8
+	Usages     []Usage
+	BitOffset  int // a partir do início do payload (sem o byte de report ID)
+	BitSize    int // tamanho de cada slot
+	Count      int
+	LogicalMin int32
+	LogicalMax int32
+	Flags      uint32
+}
+
+// IsConstant retorna true para campos de preenchimento
+func (f Field) IsConstant() bool { return f.Flags&FlagConstant != 0 }
+
+// IsVariable retorna true para campos variáveis (um valor por uso)
+func (f Field) IsVariable() bool { return f.Flags&FlagVariable != 0 }
+
+// IsRelative retorna true para controles relativos
+func (f Field) IsRelative() bool { return f.Flags&FlagRelative != 0 }
+
+// Usage retorna o uso de um campo variável
+func (f Field) Usage() Usage {
+	if len(f.Usages) == 0 {
+		return 0
+	}
+	return f.Usages[0]
+}
+
+// Collection descreve uma coleção de aplicação de nível superior
+type Collection struct {
+	Usage Usage
+}
+
+// Descriptor é o resultado da interpretação de um descritor de relatório
+type Descriptor struct {
+	Fields       []Field
+	Applications []Collection
+
+	hasReportIDs bool
+	reportBits   map[reportKey]int
+}
+
+type reportKey struct {
+	kind Kind
+	id   uint8
+}
+
+// globalState contém os itens globais (sujeitos a Push/Pop)
+type globalState struct {
+	usagePage   uint16
+	logicalMin  int32
+	logicalMax  int32
+	logicalMaxU uint32
+	reportSize  int
+	reportID    uint8
+	reportCount int
+}
+
+// localState contém os itens locais (zerados após cada item principal)
+type localState struct {
+	usages   []Usage
+	usageMin Usage
+	usageMax Usage
+	hasMin   bool
+	hasMax   bool
+}
+
+// Parse interpreta um descritor de relatório HID
+func Parse(data []byte) (*Descriptor, error) {
+	d := &Descriptor{reportBits: make(map[reportKey]int)}
+
+	var global globalState
+	var local localState
+	var stack []globalState
+	depth := 0
+
+	for i := 0; i < len(data); {
+		prefix := data[i]
+		i++
+
+		// Item longo: 0xFE, tamanho, tag, dados
+		if prefix == 0xFE {
+			if i+2 > len(data) {
+				return nil, ErrTruncated
+			}
+			size := int(data[i])
+			i += 2 + size
+			if i > len(data) {
+				return nil, ErrTruncated
+			}
+			continue
+		}
+
+		size := int(prefix & 0x03)
+		if size == 3 {
+			size = 4
+		}
+		itemType := (prefix >> 2) & 0x03
+		tag := prefix >> 4
+
+		if i+size > len(data) {
+			return nil, ErrTruncated
+		}
+		raw := data[i : i+size]
+		i += size
+
+		udata := unsignedValue(raw)
+		sdata := signedValue(raw)
+
+		switch itemType {
+		case 0: // Main
+			switch tag {
+			case 0x8, 0x9, 0xB: // Input, Output, Feature
+				kind := Input
+				if tag == 0x9 {
+					kind = Output
+				} else if tag == 0xB {
+					kind = Feature
+				}
+				if err := d.addMain(kind, udata, &global, &local); err != nil {
+					return nil, err
+				}
+			case 0xA: // Collection
+				if depth == 0 && udata == 0x01 {
+					usage := Usage(0)
+					if len(local.usages) > 0 {
+						usage = local.usages[0]
+					}
+					d.Applications = append(d.Applications, Collection{Usage: usage})
+				}
+				depth++
+			case 0xC: // End Collection
+				if depth == 0 {
+					return nil, fmt.Errorf("%w: unbalanced end collection", ErrMalformed)
+				}
+				depth--
+			}
+			local = localState{}
+
+		case 1: // Global
+			switch tag {
+			case 0x0:
+				global.usagePage = uint16(udata)
+			case 0x1:
+				global.logicalMin = sdata
+			case 0x2:
+				global.logicalMax = sdata
+				global.logicalMaxU = udata
+			case 0x7:
+				if udata > maxReportSize {
+					return nil, fmt.Errorf("%w: report size %d", ErrMalformed, udata)
+				}
+				global.reportSize = int(udata)
+			case 0x8:
+				if udata == 0 || udata > 0xFF {
+					return nil, fmt.Errorf("%w: report id %d", ErrMalformed, udata)
+				}
+				global.reportID = uint8(udata)
+				d.hasReportIDs = true
+			case 0x9:
+				if udata > maxReportCount {
+					return nil, fmt.Errorf("%w: report count %d", ErrMalformed, udata)
+				}
+				global.reportCount = int(udata)
+			case 0xA: // Push
+				if len(stack) >= maxStackDepth {
+					return nil, fmt.Errorf("%w: global stack overflow", ErrMalformed)
+				}
+				stack = append(stack, global)
+			case 0xB: // Pop
+				if len(stack) == 0 {
+					return nil, fmt.Errorf("%w: global stack underflow", ErrMalformed)
+				}
+				global = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+
+		case 2: // Local
+			usage := Usage(udata)
+			if size < 4 {
+				usage = NewUsage(global.usagePage, uint16(udata))
+			}
+			switch tag {
+			case 0x0:
+				if len(local.usages) < maxReportCount {
+					local.usages = append(local.usages, usage)
+				}
+			case 0x1:
+				local.usageMin = usage
+				local.hasMin = true
+			case 0x2:
+				local.usageMax = usage
+				local.hasMax = true
+			}
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: unterminated collection", ErrMalformed)
+	}
+	return d, nil
+}
+
+// addMain registra os campos de um item Input/Output/Feature
+func (d *Descriptor) addMain(kind Kind, flags uint32, global *globalState, local *localState) error {
+	key := reportKey{kind: kind, id: global.reportID}
+	offset := d.reportBits[key]
+	bits := global.reportSize * global.reportCount
+	if offset+bits > maxReportBits {
+		return fmt.Errorf("%w: report %d too large", ErrMalformed, global.reportID)
+	}
+	d.reportBits[key] = offset + bits
+
+	if flags&FlagConstant != 0 || bits == 0 {
+		return nil
+	}
+
+	logicalMax := global.logicalMax
+	if global.logicalMin >= 0 && logicalMax < global.logicalMin {
+		// Descritores frequentemente codificam máximos sem sinal (ex.: 0xFF em 1 byte)
+		logicalMax = int32(global.logicalMaxU & 0x7FFFFFFF)
+	}
+
+	usages := local.usages
+	if local.hasMin && local.hasMax && local.usageMax >= local.usageMin {
+		span := int(local.usageMax - local.usageMin)
+		if span >= maxReportCount {
+			span = maxReportCount - 1
+		}
+		for u := 0; u <= span; u++ {
+			usages = append(usages, local.usageMin+Usage(u))
+		}
+	}
+
+	if flags&FlagVariable == 0 {
+		d.Fields = append(d.Fields, Field{
+			Kind:       kind,
+			ReportID:   global.reportID,
+			Usages:     usages,
+			BitOffset:  offset,
+			BitSize:    global.reportSize,
+			Count:      global.reportCount,
+			LogicalMin: global.logicalMin,
+			LogicalMax: logicalMax,
+			Flags:      flags,
+		})
+		return nil
+	}
+
+	for n := 0; n < global.reportCount; n++ {
+		var usage Usage
+		switch {
+		case n < len(usages):
+			usage = usages[n]
+		case len(usages) > 0:
+			usage = usages[len(usages)-1]
+		default:
+			continue
+		}
+		d.Fields = append(d.Fields, Field{
+			Kind:       kind,
+			ReportID:   global.reportID,
+			Usages:     []Usage{usage},
+			BitOffset:  offset + n*global.reportSize,
+			BitSize:    global.reportSize,
+			Count:      1,
+			LogicalMin: global.logicalMin,
+			LogicalMax: logicalMax,
+			Flags:      flags,
+		})
+	}
+	return nil
+}
+
+// HasReportIDs retorna true se os relatórios são prefixados por um byte de ID
+func (d *Descriptor) HasReportIDs() bool {
+	return d.hasReportIDs
+}
+
+// ReportLength retorna o tamanho em bytes do payload de um relatório (sem o byte de ID)
+func (d *Descriptor) ReportLength(kind Kind, reportID uint8) int {
+	return (d.reportBits[reportKey{kind: kind, id: reportID}] + 7) / 8
+}
+
+// HasApplication retorna true se existe uma coleção de aplicação na página informada
+func (d *Descriptor) HasApplication(page uint16) bool {
+	for _, c := range d.Applications {
+		if c.Usage.Page() == page {
+			return true
+		}
+	}
+	return false
+}
+
+// Find retorna o primeiro campo variável com o uso informado
+func (d *Descriptor) Find(kind Kind, usage Usage) (Field, bool) {
+	for _, f := range d.Fields {
+		if f.Kind == kind && f.IsVariable() && f.Usage() == usage {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Split separa o report ID do payload de um relatório lido do dispositivo
+func (d *Descriptor) Split(report []byte) (uint8, []byte) {
+	if !d.hasReportIDs {
+		return 0, report
+	}
+	if len(report) == 0 {
+		return 0, nil
+	}
+	return report[0], report[1:]
+}
+
+// Value extrai o valor do slot index do campo no payload.
+// Retorna false se o payload for curto demais.
+func (f Field) Value(payload []byte, index int) (int32, bool) {
+	if index < 0 || index >= f.Count || f.BitSize == 0 {
+		return 0, false
+	}
+	start := f.BitOffset + index*f.BitSize
+	if start+f.BitSize > len(payload)*8 {
+		return 0, false
+	}
+
+	var v uint32
+	for b := 0; b < f.BitSize; b++ {
+		bit := start + b
+		if payload[bit/8]&(1<<(bit%8)) != 0 {
+			v |= 1 << b
+		}
+	}
+
+	// Extensão de sinal quando o intervalo lógico admite negativos
+	if f.LogicalMin < 0 && f.BitSize < 32 && v&(1<<(f.BitSize-1)) != 0 {
+		v |= ^uint32(0) << f.BitSize
+	}
+	return int32(v), true
+}
+
+// SetValue grava o valor no slot index do campo no payload.
+// Retorna false se o payload for curto demais.
+func (f Field) SetValue(payload []byte, index int, value int32) bool {
+	if index < 0 || index >= f.Count || f.BitSize == 0 {
+		return false
+	}
+	start := f.BitOffset + index*f.BitSize
+	if start+f.BitSize > len(payload)*8 {
+		return false
+	}
+
+	v := uint32(value)
+	for b := 0; b < f.BitSize; b++ {
+		bit := start + b
+		if v&(1<<b) != 0 {
+			payload[bit/8] |= 1 << (bit % 8)
+		} else {
+			payload[bit/8] &^= 1 << (bit % 8)
+		}
+	}
+	return true
+}
+
+// ArrayUsage traduz o valor de um slot de campo array para o uso correspondente
+func (f Field) ArrayUsage(value int32) (Usage, bool) {
+	idx := int64(value) - int64(f.LogicalMin)
+	if idx < 0 || idx >= int64(len(f.Usages)) {
+		return 0, false
+	}
+	return f.Usages[idx], true
+}
+
+// unsignedValue decodifica os dados de um item curto (little-endian)
+func unsignedValue(raw []byte) uint32 {
+	var v uint32
+	for i, b := range raw {
+		v |= uint32(b) << (8 * i)
+	}
+	return v
+}
+
+// signedValue decodifica os dados de um item curto com sinal
+func signedValue(raw []byte) int32 {
+	switch len(raw) {
+	case 1:
+		return int32(int8(raw[0]))
+	case 2:
+		return int32(int16(unsignedValue(raw)))
+	case 4:
+		return int32(unsignedValue(raw))
+	}
+	return 0
+}
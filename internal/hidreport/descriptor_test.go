@@ -0,0 +1,228 @@
+package hidreport
+
+import (
+	"errors"
+	"testing"
+)
+
+// headsetDescriptor é um descritor típico de headset de telefonia:
+// report 2 (entrada) com Hook Switch, Phone Mute (relativo), Flash e Redial,
+// report 3 com teclado em array e report 2 (saída) com LEDs.
+var headsetDescriptor = []byte{
+	0x05, 0x0B, // Usage Page (Telephony)
+	0x09, 0x05, // Usage (Headset)
+	0xA1, 0x01, // Collection (Application)
+	0x85, 0x02, //   Report ID (2)
+	0x15, 0x00, //   Logical Minimum (0)
+	0x25, 0x01, //   Logical Maximum (1)
+	0x75, 0x01, //   Report Size (1)
+	0x95, 0x01, //   Report Count (1)
+	0x09, 0x20, //   Usage (Hook Switch)
+	0x81, 0x22, //   Input (Data,Var,Abs,NoPref)
+	0x09, 0x2F, //   Usage (Phone Mute)
+	0x81, 0x06, //   Input (Data,Var,Rel)
+	0x09, 0x21, //   Usage (Flash)
+	0x09, 0x24, //   Usage (Redial)
+	0x95, 0x02, //   Report Count (2)
+	0x81, 0x02, //   Input (Data,Var,Abs)
+	0x95, 0x04, //   Report Count (4)
+	0x81, 0x01, //   Input (Const) padding
+	0x85, 0x03, //   Report ID (3)
+	0x19, 0xB0, //   Usage Minimum (Phone Key 0)
+	0x29, 0xBB, //   Usage Maximum (Phone Key Pound)
+	0x15, 0x01, //   Logical Minimum (1)
+	0x25, 0x0C, //   Logical Maximum (12)
+	0x75, 0x04, //   Report Size (4)
+	0x95, 0x01, //   Report Count (1)
+	0x81, 0x00, //   Input (Data,Array,Abs)
+	0x81, 0x01, //   Input (Const) padding
+	0x05, 0x08, //   Usage Page (LED)
+	0x85, 0x02, //   Report ID (2)
+	0x15, 0x00, //   Logical Minimum (0)
+	0x25, 0x01, //   Logical Maximum (1)
+	0x75, 0x01, //   Report Size (1)
+	0x95, 0x01, //   Report Count (1)
+	0x09, 0x17, //   Usage (Off-Hook)
+	0x91, 0x22, //   Output (Data,Var,Abs,NoPref)
+	0x09, 0x09, //   Usage (Mute)
+	0x91, 0x22, //   Output (Data,Var,Abs,NoPref)
+	0x09, 0x18, //   Usage (Ring)
+	0x91, 0x22, //   Output (Data,Var,Abs,NoPref)
+	0x09, 0x20, //   Usage (Hold)
+	0x91, 0x22, //   Output (Data,Var,Abs,NoPref)
+	0x95, 0x04, //   Report Count (4)
+	0x91, 0x01, //   Output (Const) padding
+	0xC0, // End Collection
+}
+
+func TestParseHeadsetDescriptor(t *testing.T) {
+	d, err := Parse(headsetDescriptor)
+	if err != nil {
+		t.Fatalf("Parse() erro inesperado: %v", err)
+	}
+
+	if !d.HasReportIDs() {
+		t.Error("descritor deveria usar report IDs")
+	}
+	if !d.HasApplication(0x0B) {
+		t.Error("descritor deveria conter coleção de aplicação Telephony")
+	}
+
+	tests := []struct {
+		name      string
+		kind      Kind
+		usage     Usage
+		reportID  uint8
+		bitOffset int
+		relative  bool
+	}{
+		{"Hook Switch", Input, NewUsage(0x0B, 0x20), 2, 0, false},
+		{"Phone Mute", Input, NewUsage(0x0B, 0x2F), 2, 1, true},
+		{"Flash", Input, NewUsage(0x0B, 0x21), 2, 2, false},
+		{"Redial", Input, NewUsage(0x0B, 0x24), 2, 3, false},
+		{"LED Off-Hook", Output, NewUsage(0x08, 0x17), 2, 0, false},
+		{"LED Mute", Output, NewUsage(0x08, 0x09), 2, 1, false},
+		{"LED Ring", Output, NewUsage(0x08, 0x18), 2, 2, false},
+		{"LED Hold", Output, NewUsage(0x08, 0x20), 2, 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ok := d.Find(tt.kind, tt.usage)
+			if !ok {
+				t.Fatalf("campo %s não encontrado", tt.usage)
+			}
+			if f.ReportID != tt.reportID || f.BitOffset != tt.bitOffset || f.BitSize != 1 {
+				t.Errorf("campo = report %d bit %d size %d, want report %d bit %d size 1",
+					f.ReportID, f.BitOffset, f.BitSize, tt.reportID, tt.bitOffset)
+			}
+			if f.IsRelative() != tt.relative {
+				t.Errorf("IsRelative() = %v, want %v", f.IsRelative(), tt.relative)
+			}
+		})
+	}
+
+	if got := d.ReportLength(Input, 2); got != 1 {
+		t.Errorf("ReportLength(Input, 2) = %d, want 1", got)
+	}
+	if got := d.ReportLength(Output, 2); got != 1 {
+		t.Errorf("ReportLength(Output, 2) = %d, want 1", got)
+	}
+}
+
+func TestArrayUsage(t *testing.T) {
+	d, err := Parse(headsetDescriptor)
+	if err != nil {
+		t.Fatalf("Parse() erro inesperado: %v", err)
+	}
+
+	var keypad *Field
+	for i := range d.Fields {
+		if d.Fields[i].ReportID == 3 && !d.Fields[i].IsVariable() {
+			keypad = &d.Fields[i]
+		}
+	}
+	if keypad == nil {
+		t.Fatal("campo array do teclado não encontrado")
+	}
+
+	tests := []struct {
+		value int32
+		usage Usage
+		ok    bool
+	}{
+		{0, 0, false},
+		{1, NewUsage(0x0B, 0xB0), true},
+		{12, NewUsage(0x0B, 0xBB), true},
+		{13, 0, false},
+	}
+	for _, tt := range tests {
+		usage, ok := keypad.ArrayUsage(tt.value)
+		if ok != tt.ok || usage != tt.usage {
+			t.Errorf("ArrayUsage(%d) = %s, %v; want %s, %v", tt.value, usage, ok, tt.usage, tt.ok)
+		}
+	}
+}
+
+func TestValueAndSetValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		field Field
+		value int32
+	}{
+		{"1 bit", Field{BitOffset: 3, BitSize: 1, Count: 1, LogicalMax: 1}, 1},
+		{"nibble desalinhado", Field{BitOffset: 6, BitSize: 4, Count: 1, LogicalMax: 15}, 9},
+		{"byte com sinal", Field{BitOffset: 8, BitSize: 8, Count: 1, LogicalMin: -127, LogicalMax: 127}, -5},
+		{"16 bits", Field{BitOffset: 4, BitSize: 16, Count: 1, LogicalMax: 65535}, 0xBEEF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := make([]byte, 4)
+			if !tt.field.SetValue(payload, 0, tt.value) {
+				t.Fatal("SetValue() falhou")
+			}
+			got, ok := tt.field.Value(payload, 0)
+			if !ok || got != tt.value {
+				t.Errorf("Value() = %d, %v; want %d", got, ok, tt.value)
+			}
+		})
+	}
+
+	short := Field{BitOffset: 8, BitSize: 8, Count: 1}
+	if _, ok := short.Value([]byte{0x01}, 0); ok {
+		t.Error("Value() deveria falhar com payload curto")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"item truncado", []byte{0x05}, ErrTruncated},
+		{"item longo truncado", []byte{0xFE, 0x10, 0x00}, ErrTruncated},
+		{"end collection sem collection", []byte{0xC0}, ErrMalformed},
+		{"collection não terminada", []byte{0xA1, 0x01}, ErrMalformed},
+		{"report count excessivo", []byte{0x96, 0xFF, 0xFF}, ErrMalformed},
+		{"report size excessivo", []byte{0x75, 0x40}, ErrMalformed},
+		{"report id zero", []byte{0x85, 0x00}, ErrMalformed},
+		{"pop sem push", []byte{0xB4}, ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.data)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Parse() erro = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	f.Add(headsetDescriptor)
+	f.Add([]byte{0x05, 0x0B, 0xA4, 0x19, 0x00, 0x2A, 0xFF, 0xFF, 0xB4})
+	f.Add([]byte{0xFE, 0x02, 0x00, 0x01, 0x02})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		d, err := Parse(data)
+		if err != nil {
+			return
+		}
+
+		// Extração em payloads de qualquer tamanho não deve gerar pânico
+		payload := make([]byte, 64)
+		for i := range payload {
+			payload[i] = byte(i * 37)
+		}
+		for _, field := range d.Fields {
+			for i := 0; i < field.Count; i++ {
+				v, _ := field.Value(payload, i)
+				field.ArrayUsage(v)
+				field.SetValue(payload, i, v)
+			}
+		}
+	})
+}
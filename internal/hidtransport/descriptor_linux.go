@@ -0,0 +1,61 @@
+//go:build linux
+
+package hidtransport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readReportDescriptor lê o descritor de relatório HID de uma interface via sysfs.
+// O backend libusb usa paths no formato "bus:endereço:interface" (hexadecimal);
+// a leitura deve ocorrer antes de abrir o dispositivo, pois o libusb desanexa
+// o driver usbhid do kernel.
+func readReportDescriptor(info DeviceInfo) ([]byte, error) {
+	parts := strings.Split(info.Path, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("unexpected hid path %q", info.Path)
+	}
+	bus, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bus in hid path %q", info.Path)
+	}
+	addr, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address in hid path %q", info.Path)
+	}
+	iface, err := strconv.ParseUint(parts[2], 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interface in hid path %q", info.Path)
+	}
+
+	dirs, _ := filepath.Glob("/sys/bus/usb/devices/*")
+	for _, dir := range dirs {
+		if readSysfsUint(filepath.Join(dir, "busnum")) != bus ||
+			readSysfsUint(filepath.Join(dir, "devnum")) != addr {
+			continue
+		}
+
+		pattern := filepath.Join(fmt.Sprintf("%s:*.%d", dir, iface), "*", "report_descriptor")
+		matches, _ := filepath.Glob(pattern)
+		if len(matches) == 0 {
+			break
+		}
+		return os.ReadFile(matches[0])
+	}
+	return nil, errors.New("report descriptor not found in sysfs")
+}
+
+// readSysfsUint lê um atributo numérico decimal do sysfs (0 em caso de erro)
+func readSysfsUint(path string) uint64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	v, _ := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 16)
+	return v
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package hidtransport
+
+import "errors"
+
+// readReportDescriptor não está disponível nesta plataforma; os drivers
+// recorrem ao mapeamento legado de pacotes.
+func readReportDescriptor(info DeviceInfo) ([]byte, error) {
+	return nil, errors.New("report descriptor not available on this platform")
+}
@@ -0,0 +1,354 @@
+package hidtransport
+
+import (
+	"errors"
+	"sync"
+)
+
+// Fake é um transporte em memória para testes. Dispositivos são adicionados
+// e removidos programaticamente e cada FakeDevice pode receber relatórios de
+// entrada roteirizados e simular desconexões.
+type Fake struct {
+	mu       sync.Mutex
+	devices  []*FakeDevice
+	watchers []*fakeWatcher
+
+	// EnumerateErr, se definido, é retornado por Enumerate
+	EnumerateErr error
+
+	// WatchErr, se definido, é retornado por Watch (ex.: ErrUnsupported
+	// para exercitar o fallback de polling)
+	WatchErr error
+}
+
+// NewFake cria um transporte em memória vazio
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+// Add conecta um dispositivo com o descritor de relatório informado
+// (nil = descritor indisponível)
+func (f *Fake) Add(info DeviceInfo, descriptor []byte) *FakeDevice {
+	dev := &FakeDevice{
+		fake:       f,
+		info:       info,
+		descriptor: descriptor,
+		features:   make(map[byte][]byte),
+	}
+	dev.cond = sync.NewCond(&dev.mu)
+
+	f.mu.Lock()
+	f.devices = append(f.devices, dev)
+	f.mu.Unlock()
+
+	f.notify(HotplugAdd, info)
+	return dev
+}
+
+// Watch retorna um watcher que recebe os eventos de Add e Disconnect
+func (f *Fake) Watch() (Watcher, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.WatchErr != nil {
+		return nil, f.WatchErr
+	}
+	w := &fakeWatcher{fake: f, events: make(chan HotplugEvent, 64)}
+	f.watchers = append(f.watchers, w)
+	return w, nil
+}
+
+// Watchers retorna quantos watchers estão abertos
+func (f *Fake) Watchers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.watchers)
+}
+
+// notify entrega um evento de hotplug aos watchers abertos
+func (f *Fake) notify(action string, info DeviceInfo) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	ev := HotplugEvent{
+		Action:    action,
+		Subsystem: "hidraw",
+		DevPath:   info.Path,
+		VendorID:  info.VendorID,
+		ProductID: info.ProductID,
+	}
+	for _, w := range f.watchers {
+		select {
+		case w.events <- ev:
+		default:
+			// Como o netlink: eventos além do buffer são perdidos
+		}
+	}
+}
+
+// fakeWatcher é o Watcher retornado por Fake.Watch
+type fakeWatcher struct {
+	fake   *Fake
+	events chan HotplugEvent
+}
+
+func (w *fakeWatcher) Events() <-chan HotplugEvent {
+	return w.events
+}
+
+func (w *fakeWatcher) Close() error {
+	f := w.fake
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, other := range f.watchers {
+		if other == w {
+			f.watchers = append(f.watchers[:i], f.watchers[i+1:]...)
+			close(w.events)
+			break
+		}
+	}
+	return nil
+}
+
+// Enumerate lista os dispositivos conectados que casam com o filtro
+func (f *Fake) Enumerate(vendorID, productID uint16) ([]DeviceInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.EnumerateErr != nil {
+		return nil, f.EnumerateErr
+	}
+
+	var infos []DeviceInfo
+	for _, dev := range f.devices {
+		if (vendorID == 0 || dev.info.VendorID == vendorID) &&
+			(productID == 0 || dev.info.ProductID == productID) {
+			infos = append(infos, dev.info)
+		}
+	}
+	return infos, nil
+}
+
+// Open abre o dispositivo com o path informado
+func (f *Fake) Open(info DeviceInfo) (Device, error) {
+	dev := f.find(info.Path)
+	if dev == nil {
+		return nil, ErrDisconnected
+	}
+
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+	if dev.OpenErr != nil {
+		return nil, dev.OpenErr
+	}
+	dev.open = true
+	dev.closed = false
+	dev.opens++
+	return &fakeHandle{dev: dev}, nil
+}
+
+// ReportDescriptor retorna o descritor registrado em Add
+func (f *Fake) ReportDescriptor(info DeviceInfo) ([]byte, error) {
+	dev := f.find(info.Path)
+	if dev == nil {
+		return nil, ErrDisconnected
+	}
+	if dev.descriptor == nil {
+		return nil, errors.New("report descriptor not available")
+	}
+	return append([]byte(nil), dev.descriptor...), nil
+}
+
+func (f *Fake) find(path string) *FakeDevice {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, dev := range f.devices {
+		if dev.info.Path == path {
+			return dev
+		}
+	}
+	return nil
+}
+
+func (f *Fake) remove(dev *FakeDevice) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, d := range f.devices {
+		if d == dev {
+			f.devices = append(f.devices[:i], f.devices[i+1:]...)
+			return
+		}
+	}
+}
+
+// FakeDevice é um dispositivo roteirizável do transporte Fake
+type FakeDevice struct {
+	fake       *Fake
+	info       DeviceInfo
+	descriptor []byte
+
+	mu           sync.Mutex
+	cond         *sync.Cond
+	pending      [][]byte
+	written      [][]byte
+	features     map[byte][]byte
+	open         bool
+	closed       bool
+	disconnected bool
+	opens        int
+
+	// OpenErr, se definido, é retornado por Open
+	OpenErr error
+}
+
+// Info retorna as informações de enumeração do dispositivo
+func (d *FakeDevice) Info() DeviceInfo {
+	return d.info
+}
+
+// Push enfileira um relatório de entrada a ser retornado por Read
+func (d *FakeDevice) Push(report []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending = append(d.pending, append([]byte(nil), report...))
+	d.cond.Broadcast()
+}
+
+// Disconnect remove o dispositivo da enumeração; leituras em andamento
+// retornam ErrDisconnected
+func (d *FakeDevice) Disconnect() {
+	d.fake.remove(d)
+	d.fake.notify(HotplugRemove, d.info)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.disconnected = true
+	d.cond.Broadcast()
+}
+
+// Written retorna cópias dos relatórios de saída recebidos
+func (d *FakeDevice) Written() [][]byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	out := make([][]byte, len(d.written))
+	for i, report := range d.written {
+		out[i] = append([]byte(nil), report...)
+	}
+	return out
+}
+
+// SetFeatureReport define o conteúdo retornado por GetFeatureReport;
+// report[0] é o report ID
+func (d *FakeDevice) SetFeatureReport(report []byte) {
+	if len(report) == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.features[report[0]] = append([]byte(nil), report...)
+}
+
+// IsOpen retorna true se há um handle aberto e não fechado
+func (d *FakeDevice) IsOpen() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.open && !d.closed
+}
+
+// Opens retorna quantas vezes o dispositivo foi aberto
+func (d *FakeDevice) Opens() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.opens
+}
+
+// fakeHandle é o Device retornado por Fake.Open
+type fakeHandle struct {
+	dev *FakeDevice
+}
+
+func (h *fakeHandle) Read(buf []byte) (int, error) {
+	d := h.dev
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for len(d.pending) == 0 && !d.closed && !d.disconnected {
+		d.cond.Wait()
+	}
+	switch {
+	case d.closed:
+		return 0, ErrClosed
+	case len(d.pending) > 0:
+		report := d.pending[0]
+		d.pending = d.pending[1:]
+		return copy(buf, report), nil
+	default:
+		return 0, ErrDisconnected
+	}
+}
+
+func (h *fakeHandle) Write(report []byte) (int, error) {
+	d := h.dev
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.usable(); err != nil {
+		return 0, err
+	}
+	d.written = append(d.written, append([]byte(nil), report...))
+	return len(report), nil
+}
+
+func (h *fakeHandle) GetFeatureReport(buf []byte) (int, error) {
+	d := h.dev
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.usable(); err != nil {
+		return 0, err
+	}
+	if len(buf) == 0 {
+		return 0, errors.New("feature report buffer is empty")
+	}
+	report, ok := d.features[buf[0]]
+	if !ok {
+		return 0, ErrUnsupported
+	}
+	return copy(buf, report), nil
+}
+
+func (h *fakeHandle) SendFeatureReport(report []byte) (int, error) {
+	d := h.dev
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.usable(); err != nil {
+		return 0, err
+	}
+	if len(report) == 0 {
+		return 0, errors.New("feature report is empty")
+	}
+	d.features[report[0]] = append([]byte(nil), report...)
+	return len(report), nil
+}
+
+func (h *fakeHandle) Close() error {
+	d := h.dev
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.closed = true
+	d.cond.Broadcast()
+	return nil
+}
+
+// usable verifica se o handle ainda pode ser usado (deve ser chamado com lock)
+func (d *FakeDevice) usable() error {
+	if d.closed {
+		return ErrClosed
+	}
+	if d.disconnected {
+		return ErrDisconnected
+	}
+	return nil
+}
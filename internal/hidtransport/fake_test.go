@@ -0,0 +1,115 @@
+package hidtransport
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFakeEnumerateAndOpen(t *testing.T) {
+	fake := NewFake()
+	fake.Add(DeviceInfo{Path: "a", VendorID: 0x0b0e, ProductID: 1}, []byte{0x05, 0x0B})
+	fake.Add(DeviceInfo{Path: "b", VendorID: 0x046d, ProductID: 2}, nil)
+
+	infos, err := fake.Enumerate(0x0b0e, 0)
+	if err != nil || len(infos) != 1 || infos[0].Path != "a" {
+		t.Fatalf("Enumerate() = %+v, %v", infos, err)
+	}
+
+	if desc, err := fake.ReportDescriptor(infos[0]); err != nil || !bytes.Equal(desc, []byte{0x05, 0x0B}) {
+		t.Errorf("ReportDescriptor() = %x, %v", desc, err)
+	}
+	if _, err := fake.ReportDescriptor(DeviceInfo{Path: "b"}); err == nil {
+		t.Error("ReportDescriptor() deveria falhar sem descritor")
+	}
+	if _, err := fake.Open(DeviceInfo{Path: "c"}); !errors.Is(err, ErrDisconnected) {
+		t.Errorf("Open() de path inexistente = %v, want ErrDisconnected", err)
+	}
+
+	fake.EnumerateErr = errors.New("falha")
+	if _, err := fake.Enumerate(0, 0); err == nil {
+		t.Error("Enumerate() deveria retornar EnumerateErr")
+	}
+}
+
+func TestFakeDeviceReadWrite(t *testing.T) {
+	fake := NewFake()
+	dev := fake.Add(DeviceInfo{Path: "a"}, nil)
+
+	handle, err := fake.Open(dev.Info())
+	if err != nil {
+		t.Fatalf("Open() erro inesperado: %v", err)
+	}
+
+	dev.Push([]byte{0x02, 0x01})
+	buf := make([]byte, 64)
+	if n, err := handle.Read(buf); err != nil || !bytes.Equal(buf[:n], []byte{0x02, 0x01}) {
+		t.Errorf("Read() = %x, %v", buf[:n], err)
+	}
+
+	if _, err := handle.Write([]byte{0x02, 0x04}); err != nil {
+		t.Fatalf("Write() erro inesperado: %v", err)
+	}
+	if written := dev.Written(); len(written) != 1 || !bytes.Equal(written[0], []byte{0x02, 0x04}) {
+		t.Errorf("Written() = %x", written)
+	}
+
+	dev.SetFeatureReport([]byte{0x05, 0x64})
+	feature := []byte{0x05, 0x00}
+	if n, err := handle.GetFeatureReport(feature); err != nil || n != 2 || feature[1] != 0x64 {
+		t.Errorf("GetFeatureReport() = %x, %v", feature, err)
+	}
+	if _, err := handle.GetFeatureReport([]byte{0x09, 0x00}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("GetFeatureReport() de ID desconhecido = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestFakeDeviceDisconnectUnblocksRead(t *testing.T) {
+	fake := NewFake()
+	dev := fake.Add(DeviceInfo{Path: "a"}, nil)
+	handle, _ := fake.Open(dev.Info())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := handle.Read(make([]byte, 8))
+		done <- err
+	}()
+
+	dev.Disconnect()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrDisconnected) {
+			t.Errorf("Read() = %v, want ErrDisconnected", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read() não retornou após Disconnect()")
+	}
+
+	if infos, _ := fake.Enumerate(0, 0); len(infos) != 0 {
+		t.Errorf("dispositivo desconectado ainda enumerado: %+v", infos)
+	}
+	if _, err := handle.Write([]byte{0x00}); !errors.Is(err, ErrDisconnected) {
+		t.Errorf("Write() = %v, want ErrDisconnected", err)
+	}
+}
+
+func TestFakeDeviceCloseUnblocksRead(t *testing.T) {
+	fake := NewFake()
+	dev := fake.Add(DeviceInfo{Path: "a"}, nil)
+	handle, _ := fake.Open(dev.Info())
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := handle.Read(make([]byte, 8))
+		done <- err
+	}()
+
+	handle.Close()
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Errorf("Read() = %v, want ErrClosed", err)
+	}
+	if dev.IsOpen() {
+		t.Error("IsOpen() deveria ser false após Close()")
+	}
+}
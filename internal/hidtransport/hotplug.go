@@ -0,0 +1,88 @@
+package hidtransport
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// Ações de hotplug reportadas pelo kernel
+const (
+	HotplugAdd    = "add"
+	HotplugRemove = "remove"
+	HotplugBind   = "bind"
+	HotplugUnbind = "unbind"
+)
+
+// HotplugEvent é uma notificação de dispositivo adicionado ou removido
+type HotplugEvent struct {
+	Action    string // add, remove, bind, unbind
+	Subsystem string // hidraw, hid, usb
+	DevPath   string // Caminho no sysfs (ex.: /devices/pci0000:00/.../hidraw/hidraw3)
+	VendorID  uint16 // 0 quando o evento não informa
+	ProductID uint16
+}
+
+// Watcher entrega eventos de hotplug até ser fechado. O canal de eventos é
+// fechado quando o watcher para, por Close ou por erro de leitura.
+type Watcher interface {
+	Events() <-chan HotplugEvent
+	Close() error
+}
+
+// hotplugSubsystems são os subsistemas cujos eventos interessam ao driver HID
+var hotplugSubsystems = map[string]bool{"hidraw": true, "hid": true, "usb": true}
+
+// parseUevent interpreta uma mensagem de uevent do kernel:
+// "add@/devices/...\0ACTION=add\0DEVPATH=...\0SUBSYSTEM=hidraw\0...".
+// Retorna false para mensagens de outros subsistemas ou ações.
+func parseUevent(msg []byte) (HotplugEvent, bool) {
+	var ev HotplugEvent
+	for i, field := range bytes.Split(msg, []byte{0}) {
+		key, value, ok := strings.Cut(string(field), "=")
+		if !ok {
+			// Cabeçalho "ação@devpath" ou mensagens do udev ("libudev")
+			if i == 0 && !strings.Contains(string(field), "@") {
+				return ev, false
+			}
+			continue
+		}
+		switch key {
+		case "ACTION":
+			ev.Action = value
+		case "SUBSYSTEM":
+			ev.Subsystem = value
+		case "DEVPATH":
+			ev.DevPath = value
+		case "PRODUCT":
+			// usb: "b0e/245e/100" (hex, sem zeros à esquerda)
+			parts := strings.Split(value, "/")
+			if len(parts) >= 2 {
+				ev.VendorID = parseHex16(parts[0])
+				ev.ProductID = parseHex16(parts[1])
+			}
+		case "HID_ID":
+			// hid: "0003:00000B0E:0000245E"
+			parts := strings.Split(value, ":")
+			if len(parts) == 3 {
+				ev.VendorID = parseHex16(parts[1])
+				ev.ProductID = parseHex16(parts[2])
+			}
+		}
+	}
+
+	switch ev.Action {
+	case HotplugAdd, HotplugRemove, HotplugBind, HotplugUnbind:
+	default:
+		return ev, false
+	}
+	return ev, hotplugSubsystems[ev.Subsystem]
+}
+
+func parseHex16(s string) uint16 {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || v > 0xffff {
+		return 0
+	}
+	return uint16(v)
+}
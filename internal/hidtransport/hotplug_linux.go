@@ -0,0 +1,86 @@
+//go:build linux
+
+package hidtransport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// ueventGroupKernel é o grupo multicast dos uevents emitidos pelo kernel
+// (o grupo 2 recebe as mensagens reemitidas pelo udev, em outro formato)
+const ueventGroupKernel = 1
+
+// netlinkWatcher recebe uevents do kernel por um socket NETLINK_KOBJECT_UEVENT
+type netlinkWatcher struct {
+	file   *os.File
+	events chan HotplugEvent
+	once   sync.Once
+	done   chan struct{}
+}
+
+// watchHotplug abre o socket netlink de uevents do kernel
+func watchHotplug() (Watcher, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC|unix.SOCK_NONBLOCK, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("uevent socket: %w", err)
+	}
+	if err := unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: ueventGroupKernel}); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("uevent bind: %w", err)
+	}
+	// Rajadas de eventos (hub com vários dispositivos) não devem estourar o buffer
+	_ = unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUF, 1<<20)
+
+	// O socket não bloqueante é integrado ao poller do runtime, então Close
+	// desbloqueia a leitura em andamento
+	w := &netlinkWatcher{
+		file:   os.NewFile(uintptr(fd), "uevent"),
+		events: make(chan HotplugEvent, 64),
+		done:   make(chan struct{}),
+	}
+	go w.readLoop()
+	return w, nil
+}
+
+func (w *netlinkWatcher) readLoop() {
+	defer close(w.events)
+
+	buf := make([]byte, 16*1024)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			if errors.Is(err, unix.ENOBUFS) {
+				// Eventos perdidos; o driver reconcilia com a enumeração periódica
+				continue
+			}
+			return
+		}
+		ev, ok := parseUevent(buf[:n])
+		if !ok {
+			continue
+		}
+		select {
+		case w.events <- ev:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *netlinkWatcher) Events() <-chan HotplugEvent {
+	return w.events
+}
+
+func (w *netlinkWatcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		err = w.file.Close()
+	})
+	return err
+}
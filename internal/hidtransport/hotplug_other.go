@@ -0,0 +1,8 @@
+//go:build !linux
+
+package hidtransport
+
+// watchHotplug não tem implementação fora do Linux; o driver usa polling
+func watchHotplug() (Watcher, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,116 @@
+package hidtransport
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// uevent monta uma mensagem no formato do kernel
+func uevent(fields ...string) []byte {
+	return []byte(strings.Join(fields, "\x00") + "\x00")
+}
+
+func TestParseUevent(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  []byte
+		want HotplugEvent
+		ok   bool
+	}{
+		{
+			"hidraw add",
+			uevent("add@/devices/pci0000:00/0000:00:14.0/usb1/1-2/1-2:1.3/0003:0B0E:245E.0007/hidraw/hidraw3",
+				"ACTION=add", "DEVPATH=/devices/pci0000:00/0000:00:14.0/usb1/1-2/1-2:1.3/0003:0B0E:245E.0007/hidraw/hidraw3",
+				"SUBSYSTEM=hidraw", "MAJOR=241", "MINOR=3", "DEVNAME=hidraw3", "SEQNUM=4242"),
+			HotplugEvent{Action: "add", Subsystem: "hidraw", DevPath: "/devices/pci0000:00/0000:00:14.0/usb1/1-2/1-2:1.3/0003:0B0E:245E.0007/hidraw/hidraw3"},
+			true,
+		},
+		{
+			"usb remove com PRODUCT",
+			uevent("remove@/devices/pci0000:00/0000:00:14.0/usb1/1-2", "ACTION=remove",
+				"DEVPATH=/devices/pci0000:00/0000:00:14.0/usb1/1-2", "SUBSYSTEM=usb", "DEVTYPE=usb_device", "PRODUCT=b0e/245e/100"),
+			HotplugEvent{Action: "remove", Subsystem: "usb", DevPath: "/devices/pci0000:00/0000:00:14.0/usb1/1-2", VendorID: 0x0b0e, ProductID: 0x245e},
+			true,
+		},
+		{
+			"hid bind com HID_ID",
+			uevent("bind@/devices/x/0003:0B0E:245E.0007", "ACTION=bind", "DEVPATH=/devices/x/0003:0B0E:245E.0007",
+				"SUBSYSTEM=hid", "HID_ID=0003:00000B0E:0000245E"),
+			HotplugEvent{Action: "bind", Subsystem: "hid", DevPath: "/devices/x/0003:0B0E:245E.0007", VendorID: 0x0b0e, ProductID: 0x245e},
+			true,
+		},
+		{
+			"outro subsistema",
+			uevent("add@/devices/virtual/net/veth0", "ACTION=add", "DEVPATH=/devices/virtual/net/veth0", "SUBSYSTEM=net"),
+			HotplugEvent{},
+			false,
+		},
+		{
+			"ação change",
+			uevent("change@/devices/x/hidraw/hidraw3", "ACTION=change", "SUBSYSTEM=hidraw"),
+			HotplugEvent{},
+			false,
+		},
+		{
+			"mensagem do udev",
+			append([]byte("libudev\x00\xfe\xed\xca\xfe"), uevent("ACTION=add", "SUBSYSTEM=hidraw")...),
+			HotplugEvent{},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseUevent(tt.msg)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, esperado %v (%+v)", ok, tt.ok, got)
+			}
+			if ok && got != tt.want {
+				t.Errorf("evento = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFakeWatch(t *testing.T) {
+	fake := NewFake()
+	w, err := fake.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev := fake.Add(DeviceInfo{Path: "p", VendorID: 0x0b0e, ProductID: 1}, nil)
+	dev.Disconnect()
+
+	for _, want := range []string{HotplugAdd, HotplugRemove} {
+		if ev := <-w.Events(); ev.Action != want || ev.DevPath != "p" || ev.VendorID != 0x0b0e {
+			t.Errorf("evento = %+v, esperado %s", ev, want)
+		}
+	}
+
+	w.Close()
+	if _, ok := <-w.Events(); ok {
+		t.Error("canal deveria ser fechado por Close")
+	}
+	if fake.Watchers() != 0 {
+		t.Error("watcher deveria ser removido por Close")
+	}
+}
+
+func TestWatchHotplugClose(t *testing.T) {
+	w, err := watchHotplug()
+	if err != nil {
+		t.Skipf("hotplug indisponível neste ambiente: %v", err)
+	}
+
+	w.Close()
+	select {
+	case _, ok := <-w.Events():
+		if ok {
+			t.Error("nenhum evento esperado após Close")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close não encerrou a leitura do socket")
+	}
+}
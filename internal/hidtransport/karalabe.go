@@ -0,0 +1,88 @@
+package hidtransport
+
+import (
+	"github.com/karalabe/hid"
+)
+
+// karalabeTransport implementa Transport sobre github.com/karalabe/hid
+type karalabeTransport struct{}
+
+// New retorna o transporte padrão da plataforma (karalabe/hid)
+func New() Transport {
+	return karalabeTransport{}
+}
+
+// Enumerate lista as interfaces HID; plataformas sem suporte retornam lista vazia
+func (karalabeTransport) Enumerate(vendorID, productID uint16) ([]DeviceInfo, error) {
+	found := hid.Enumerate(vendorID, productID)
+
+	infos := make([]DeviceInfo, 0, len(found))
+	for _, info := range found {
+		infos = append(infos, DeviceInfo{
+			Path:         info.Path,
+			VendorID:     info.VendorID,
+			ProductID:    info.ProductID,
+			Release:      info.Release,
+			Serial:       info.Serial,
+			Manufacturer: info.Manufacturer,
+			Product:      info.Product,
+			UsagePage:    info.UsagePage,
+			Usage:        info.Usage,
+			Interface:    info.Interface,
+		})
+	}
+	return infos, nil
+}
+
+// Open abre a interface pelo path
+func (karalabeTransport) Open(info DeviceInfo) (Device, error) {
+	device, err := hid.DeviceInfo{
+		Path:      info.Path,
+		VendorID:  info.VendorID,
+		ProductID: info.ProductID,
+		Serial:    info.Serial,
+		Product:   info.Product,
+		Interface: info.Interface,
+	}.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &karalabeDevice{device: device}, nil
+}
+
+// ReportDescriptor lê o descritor do sistema (ver descriptor_*.go)
+func (karalabeTransport) ReportDescriptor(info DeviceInfo) ([]byte, error) {
+	return readReportDescriptor(info)
+}
+
+// Watch observa os uevents do kernel (ver hotplug_*.go)
+func (karalabeTransport) Watch() (Watcher, error) {
+	return watchHotplug()
+}
+
+// karalabeDevice adapta *hid.Device à interface Device
+type karalabeDevice struct {
+	device *hid.Device
+}
+
+func (d *karalabeDevice) Read(buf []byte) (int, error) {
+	return d.device.Read(buf)
+}
+
+func (d *karalabeDevice) Write(report []byte) (int, error) {
+	return d.device.Write(report)
+}
+
+// GetFeatureReport não é exposto pelo karalabe/hid
+func (d *karalabeDevice) GetFeatureReport(buf []byte) (int, error) {
+	return 0, ErrUnsupported
+}
+
+// SendFeatureReport não é exposto pelo karalabe/hid
+func (d *karalabeDevice) SendFeatureReport(report []byte) (int, error) {
+	return 0, ErrUnsupported
+}
+
+func (d *karalabeDevice) Close() error {
+	return d.device.Close()
+}
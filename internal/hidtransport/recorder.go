@@ -0,0 +1,250 @@
+package hidtransport
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Tipos de registro de um arquivo de captura
+const (
+	RecordMeta       = "meta"       // Metadados da captura (primeira linha)
+	RecordAttach     = "attach"     // Interface apareceu na enumeração
+	RecordDetach     = "detach"     // Interface sumiu da enumeração
+	RecordDescriptor = "descriptor" // Descritor de relatório lido
+	RecordInput      = "input"      // Relatório de entrada lido
+	RecordOutput     = "output"     // Relatório de saída enviado
+	RecordFeature    = "feature"    // Relatório de feature lido/enviado
+)
+
+// CaptureVersion é a versão do formato de captura
+const CaptureVersion = 1
+
+// Record é uma linha do arquivo de captura (JSON Lines). Os dados brutos
+// são gravados em hexadecimal para facilitar a leitura manual.
+type Record struct {
+	Time   time.Time         `json:"time"`
+	Type   string            `json:"type"`
+	Path   string            `json:"path,omitempty"`
+	Device *DeviceInfo       `json:"device,omitempty"`
+	Data   string            `json:"data,omitempty"`
+	Meta   map[string]string `json:"meta,omitempty"`
+}
+
+// Bytes decodifica o campo Data
+func (r Record) Bytes() ([]byte, error) {
+	return hex.DecodeString(r.Data)
+}
+
+// Recorder envolve um Transport e grava em uma captura tudo o que passa por
+// ele: interfaces enumeradas, descritores e relatórios de entrada/saída.
+type Recorder struct {
+	inner Transport
+
+	mu   sync.Mutex
+	w    *bufio.Writer
+	out  io.Writer
+	seen map[string]DeviceInfo
+	now  func() time.Time
+	err  error
+}
+
+// NewRecorder cria o gravador sobre o transporte informado e grava o
+// registro de metadados inicial
+func NewRecorder(inner Transport, out io.Writer, meta map[string]string) *Recorder {
+	r := &Recorder{
+		inner: inner,
+		w:     bufio.NewWriter(out),
+		out:   out,
+		seen:  make(map[string]DeviceInfo),
+		now:   time.Now,
+	}
+
+	m := map[string]string{"version": fmt.Sprint(CaptureVersion)}
+	for k, v := range meta {
+		m[k] = v
+	}
+	r.write(Record{Type: RecordMeta, Meta: m})
+	return r
+}
+
+// Err retorna o primeiro erro de escrita da captura, se houver
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close descarrega a captura e fecha o destino, se ele for um io.Closer
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.w.Flush(); err != nil && r.err == nil {
+		r.err = err
+	}
+	if c, ok := r.out.(io.Closer); ok {
+		if err := c.Close(); err != nil && r.err == nil {
+			r.err = err
+		}
+	}
+	return r.err
+}
+
+// write grava um registro; cada linha é descarregada imediatamente para que
+// a captura sobreviva a um encerramento abrupto do agente
+func (r *Recorder) write(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	rec.Time = r.now()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		r.err = err
+		return
+	}
+	r.w.Write(data)
+	r.w.WriteByte('\n')
+	r.err = r.w.Flush()
+}
+
+// Enumerate repassa ao transporte e registra interfaces que apareceram ou sumiram
+func (r *Recorder) Enumerate(vendorID, productID uint16) ([]DeviceInfo, error) {
+	infos, err := r.inner.Enumerate(vendorID, productID)
+	if err != nil {
+		return infos, err
+	}
+
+	current := make(map[string]bool, len(infos))
+	var attached []DeviceInfo
+	var detached []string
+
+	r.mu.Lock()
+	for _, info := range infos {
+		current[info.Path] = true
+		if _, ok := r.seen[info.Path]; !ok {
+			r.seen[info.Path] = info
+			attached = append(attached, info)
+		}
+	}
+	for path := range r.seen {
+		if !current[path] {
+			delete(r.seen, path)
+			detached = append(detached, path)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, path := range detached {
+		r.write(Record{Type: RecordDetach, Path: path})
+	}
+	for i := range attached {
+		info := attached[i]
+		r.write(Record{Type: RecordAttach, Path: info.Path, Device: &info})
+	}
+	return infos, nil
+}
+
+// Open abre a interface e devolve um handle que registra o tráfego
+func (r *Recorder) Open(info DeviceInfo) (Device, error) {
+	device, err := r.inner.Open(info)
+	if err != nil {
+		return nil, err
+	}
+	return &recordingDevice{inner: device, path: info.Path, rec: r}, nil
+}
+
+// ReportDescriptor repassa ao transporte e registra o descritor lido
+func (r *Recorder) ReportDescriptor(info DeviceInfo) ([]byte, error) {
+	data, err := r.inner.ReportDescriptor(info)
+	if err == nil {
+		r.write(Record{Type: RecordDescriptor, Path: info.Path, Data: hex.EncodeToString(data)})
+	}
+	return data, err
+}
+
+// Watch repassa ao transporte; eventos de hotplug não são gravados, as
+// conexões aparecem na captura pela enumeração seguinte
+func (r *Recorder) Watch() (Watcher, error) {
+	return r.inner.Watch()
+}
+
+// recordingDevice registra os relatórios trocados com um dispositivo aberto
+type recordingDevice struct {
+	inner Device
+	path  string
+	rec   *Recorder
+}
+
+func (d *recordingDevice) Read(buf []byte) (int, error) {
+	n, err := d.inner.Read(buf)
+	if n > 0 {
+		d.rec.write(Record{Type: RecordInput, Path: d.path, Data: hex.EncodeToString(buf[:n])})
+	}
+	return n, err
+}
+
+func (d *recordingDevice) Write(report []byte) (int, error) {
+	n, err := d.inner.Write(report)
+	if err == nil {
+		d.rec.write(Record{Type: RecordOutput, Path: d.path, Data: hex.EncodeToString(report)})
+	}
+	return n, err
+}
+
+func (d *recordingDevice) GetFeatureReport(buf []byte) (int, error) {
+	n, err := d.inner.GetFeatureReport(buf)
+	if err == nil && n > 0 {
+		d.rec.write(Record{Type: RecordFeature, Path: d.path, Data: hex.EncodeToString(buf[:n])})
+	}
+	return n, err
+}
+
+func (d *recordingDevice) SendFeatureReport(report []byte) (int, error) {
+	n, err := d.inner.SendFeatureReport(report)
+	if err == nil {
+		d.rec.write(Record{Type: RecordFeature, Path: d.path, Data: hex.EncodeToString(report)})
+	}
+	return n, err
+}
+
+func (d *recordingDevice) Close() error {
+	return d.inner.Close()
+}
+
+// ReadCapture lê todos os registros de um arquivo de captura
+func ReadCapture(in io.Reader) ([]Record, error) {
+	var records []Record
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("capture line %d: %w", line, err)
+		}
+		if _, err := rec.Bytes(); err != nil {
+			return nil, fmt.Errorf("capture line %d: invalid data: %w", line, err)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || records[0].Type != RecordMeta {
+		return nil, fmt.Errorf("capture does not start with a %q record", RecordMeta)
+	}
+	return records, nil
+}
@@ -0,0 +1,86 @@
+package hidtransport
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRecorderCapture(t *testing.T) {
+	fake := NewFake()
+	dev := fake.Add(DeviceInfo{Path: "1:2:3", VendorID: 0x0b0e, ProductID: 0x0123, Serial: "ABC"}, []byte{0x05, 0x0B})
+
+	var out bytes.Buffer
+	recorder := NewRecorder(fake, &out, map[string]string{"hostname": "mesa-01"})
+
+	infos, _ := recorder.Enumerate(0x0b0e, 0)
+	recorder.Enumerate(0x0b0e, 0) // sem mudanças: nada gravado
+	recorder.ReportDescriptor(infos[0])
+
+	handle, err := recorder.Open(infos[0])
+	if err != nil {
+		t.Fatalf("Open() erro inesperado: %v", err)
+	}
+	dev.Push([]byte{0x02, 0x01})
+	handle.Read(make([]byte, 64))
+	handle.Write([]byte{0x02, 0x02})
+
+	dev.Disconnect()
+	recorder.Enumerate(0x0b0e, 0)
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() erro inesperado: %v", err)
+	}
+
+	records, err := ReadCapture(&out)
+	if err != nil {
+		t.Fatalf("ReadCapture() erro inesperado: %v", err)
+	}
+
+	want := []struct {
+		typ  string
+		data string
+	}{
+		{RecordMeta, ""},
+		{RecordAttach, ""},
+		{RecordDescriptor, "050b"},
+		{RecordInput, "0201"},
+		{RecordOutput, "0202"},
+		{RecordDetach, ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("esperado %d registros, obtido %d: %+v", len(want), len(records), records)
+	}
+	for i, w := range want {
+		if records[i].Type != w.typ || records[i].Data != w.data {
+			t.Errorf("registro %d = %s %q, want %s %q", i, records[i].Type, records[i].Data, w.typ, w.data)
+		}
+		if records[i].Time.IsZero() {
+			t.Errorf("registro %d sem timestamp", i)
+		}
+	}
+
+	if records[0].Meta["hostname"] != "mesa-01" || records[0].Meta["version"] != "1" {
+		t.Errorf("metadados inesperados: %v", records[0].Meta)
+	}
+	if records[1].Device == nil || records[1].Device.Serial != "ABC" {
+		t.Errorf("attach sem informações do dispositivo: %+v", records[1])
+	}
+}
+
+func TestReadCaptureErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"vazio", ""},
+		{"sem meta", `{"type":"input","data":"00"}` + "\n"},
+		{"json inválido", `{"type":"meta"}` + "\n{\n"},
+		{"hex inválido", `{"type":"meta"}` + "\n" + `{"type":"input","data":"zz"}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadCapture(bytes.NewBufferString(tt.input)); err == nil {
+				t.Error("ReadCapture() deveria falhar")
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Package hidtransport abstrai o acesso a dispositivos HID (enumeração,
+// abertura, leitura/escrita de relatórios), permitindo trocar o backend
+// karalabe/hid por um dispositivo em memória nos testes.
+package hidtransport
+
+import "errors"
+
+var (
+	// ErrUnsupported indica uma operação não suportada pelo backend
+	ErrUnsupported = errors.New("hid transport: operation not supported")
+
+	// ErrClosed indica uso de um dispositivo já fechado
+	ErrClosed = errors.New("hid transport: device closed")
+
+	// ErrDisconnected indica que o dispositivo foi removido durante o uso
+	ErrDisconnected = errors.New("hid transport: device disconnected")
+)
+
+// DeviceInfo descreve uma interface HID enumerada
+type DeviceInfo struct {
+	Path         string // Caminho específico da plataforma
+	VendorID     uint16
+	ProductID    uint16
+	Release      uint16
+	Serial       string
+	Manufacturer string
+	Product      string
+	UsagePage    uint16 // Apenas Windows/macOS
+	Usage        uint16 // Apenas Windows/macOS
+	Interface    int
+}
+
+// Device é um handle aberto para uma interface HID
+type Device interface {
+	// Read bloqueia até receber um relatório de entrada
+	Read(buf []byte) (int, error)
+
+	// Write envia um relatório de saída; o primeiro byte é o report ID
+	// (0 quando o dispositivo não usa IDs)
+	Write(report []byte) (int, error)
+
+	// GetFeatureReport lê um relatório de feature; buf[0] indica o report ID
+	GetFeatureReport(buf []byte) (int, error)
+
+	// SendFeatureReport envia um relatório de feature; report[0] é o report ID
+	SendFeatureReport(report []byte) (int, error)
+
+	// Close libera o handle; chamadas repetidas são seguras
+	Close() error
+}
+
+// Transport enumera e abre dispositivos HID
+type Transport interface {
+	// Enumerate lista as interfaces HID do fabricante/produto (0 = qualquer)
+	Enumerate(vendorID, productID uint16) ([]DeviceInfo, error)
+
+	// Open abre a interface para leitura e escrita
+	Open(info DeviceInfo) (Device, error)
+
+	// ReportDescriptor retorna o descritor de relatório bruto da interface.
+	// Deve ser chamado antes de Open em backends que desanexam o driver do kernel.
+	ReportDescriptor(info DeviceInfo) ([]byte, error)
+
+	// Watch inicia a observação de hotplug (dispositivos adicionados e
+	// removidos). Retorna ErrUnsupported quando a plataforma não notifica,
+	// caso em que o chamador deve enumerar periodicamente.
+	Watch() (Watcher, error)
+}
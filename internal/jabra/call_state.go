@@ -0,0 +1,163 @@
+package jabra
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/models"
+)
+
+// CallState é o estado de chamada de um dispositivo
+type CallState string
+
+const (
+	CallIdle    CallState = "idle"    // Sem chamada
+	CallRinging CallState = "ringing" // Chamada entrante tocando
+	CallInCall  CallState = "in_call" // Em chamada (off-hook)
+	CallHeld    CallState = "held"    // Chamada em espera
+	CallMuted   CallState = "muted"   // Em chamada com microfone mudo
+	CallEnded   CallState = "ended"   // Chamada encerrada (até o próximo evento)
+)
+
+// CallStates lista os estados de chamada, na ordem usada em documentação e validação
+var CallStates = []CallState{CallIdle, CallRinging, CallInCall, CallHeld, CallMuted, CallEnded}
+
+// Active retorna true nos estados com chamada estabelecida
+func (s CallState) Active() bool {
+	return s == CallInCall || s == CallHeld || s == CallMuted
+}
+
+// CallEvent é um evento que alimenta a máquina de estados de chamada
+type CallEvent string
+
+const (
+	CallEventIncoming          CallEvent = "incoming"           // Chamada entrante (ligacao_interna, ringer)
+	CallEventAnswer            CallEvent = "answer"             // Off-hook
+	CallEventHangup            CallEvent = "hangup"             // On-hook
+	CallEventReject            CallEvent = "reject"             // Chamada entrante recusada
+	CallEventAnsweredElsewhere CallEvent = "answered_elsewhere" // Atendida por outro ramal
+	CallEventHold              CallEvent = "hold"
+	CallEventResume            CallEvent = "resume"
+	CallEventMute              CallEvent = "mute"
+	CallEventUnmute            CallEvent = "unmute"
+	CallEventReset             CallEvent = "reset" // Fim do período em ended, volta a idle
+)
+
+// ErrInvalidCallTransition indica um evento que não é válido no estado atual
+var ErrInvalidCallTransition = errors.New("invalid call transition")
+
+// callTransitions define os estados de destino por estado e evento.
+// Destino igual à origem é aceito sem alterar o estado (eventos repetidos,
+// ex.: ligacao_atendida após o off-hook do próprio headset).
+var callTransitions = map[CallState]map[CallEvent]CallState{
+	CallIdle: {
+		CallEventIncoming:          CallRinging,
+		CallEventAnswer:            CallInCall, // Chamada originada
+		CallEventHangup:            CallIdle,
+		CallEventAnsweredElsewhere: CallIdle,
+	},
+	CallRinging: {
+		CallEventIncoming:          CallRinging,
+		CallEventAnswer:            CallInCall,
+		CallEventHangup:            CallEnded,
+		CallEventReject:            CallEnded,
+		CallEventAnsweredElsewhere: CallEnded,
+	},
+	CallInCall: {
+		CallEventAnswer:            CallInCall,
+		CallEventAnsweredElsewhere: CallInCall,
+		CallEventHangup:            CallEnded,
+		CallEventHold:              CallHeld,
+		CallEventResume:            CallInCall,
+		CallEventMute:              CallMuted,
+		CallEventUnmute:            CallInCall,
+	},
+	CallHeld: {
+		CallEventAnswer:            CallHeld,
+		CallEventAnsweredElsewhere: CallHeld,
+		CallEventHangup:            CallEnded,
+		CallEventHold:              CallHeld,
+		CallEventResume:            CallInCall,
+	},
+	CallMuted: {
+		CallEventAnswer:            CallMuted,
+		CallEventAnsweredElsewhere: CallMuted,
+		CallEventHangup:            CallEnded,
+		CallEventHold:              CallHeld,
+		CallEventMute:              CallMuted,
+		CallEventUnmute:            CallInCall,
+	},
+	CallEnded: {
+		CallEventIncoming:          CallRinging,
+		CallEventAnswer:            CallInCall,
+		CallEventHangup:            CallEnded,
+		CallEventAnsweredElsewhere: CallEnded,
+		CallEventReset:             CallIdle,
+	},
+}
+
+// CallStateMachine acompanha o estado de chamada de um dispositivo.
+// Não é segura para uso concorrente; o Monitor a protege com seu lock.
+type CallStateMachine struct {
+	state     CallState
+	since     time.Time
+	startedAt time.Time
+	endedAt   time.Time
+	lastEvent CallEvent
+	now       func() time.Time
+}
+
+// NewCallStateMachine cria a máquina no estado idle
+func NewCallStateMachine() *CallStateMachine {
+	c := &CallStateMachine{state: CallIdle, now: time.Now}
+	c.since = c.now()
+	return c
+}
+
+// State retorna o estado atual
+func (c *CallStateMachine) State() CallState {
+	return c.state
+}
+
+// Fire aplica um evento. Retorna true se o estado mudou; eventos inválidos
+// no estado atual retornam ErrInvalidCallTransition e não alteram o estado.
+func (c *CallStateMachine) Fire(event CallEvent) (bool, error) {
+	next, ok := callTransitions[c.state][event]
+	if !ok {
+		return false, fmt.Errorf("%w: %s in state %s", ErrInvalidCallTransition, event, c.state)
+	}
+	if next == c.state {
+		return false, nil
+	}
+
+	now := c.now()
+	if next.Active() && !c.state.Active() {
+		c.startedAt = now
+	}
+	if next == CallEnded {
+		c.endedAt = now
+	}
+	c.state = next
+	c.since = now
+	c.lastEvent = event
+	return true, nil
+}
+
+// Info retorna o estado para a telemetria
+func (c *CallStateMachine) Info() models.CallInfo {
+	info := models.CallInfo{
+		State:     string(c.state),
+		Since:     c.since,
+		LastEvent: string(c.lastEvent),
+	}
+	if !c.startedAt.IsZero() {
+		startedAt := c.startedAt
+		info.StartedAt = &startedAt
+	}
+	if !c.endedAt.IsZero() {
+		endedAt := c.endedAt
+		info.EndedAt = &endedAt
+	}
+	return info
+}
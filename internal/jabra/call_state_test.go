@@ -0,0 +1,309 @@
+package jabra
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+)
+
+func TestCallStateMachine(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []CallEvent
+		want   CallState
+	}{
+		{"Chamada entrante atendida", []CallEvent{CallEventIncoming, CallEventAnswer}, CallInCall},
+		{"Chamada originada", []CallEvent{CallEventAnswer}, CallInCall},
+		{"Recusada", []CallEvent{CallEventIncoming, CallEventReject}, CallEnded},
+		{"Atendida por outro ramal", []CallEvent{CallEventIncoming, CallEventAnsweredElsewhere}, CallEnded},
+		{"Espera e retorno", []CallEvent{CallEventAnswer, CallEventHold, CallEventResume}, CallInCall},
+		{"Mute durante chamada", []CallEvent{CallEventAnswer, CallEventMute}, CallMuted},
+		{"Encerrada mutada", []CallEvent{CallEventAnswer, CallEventMute, CallEventHangup}, CallEnded},
+		{"Encerrada volta a idle", []CallEvent{CallEventAnswer, CallEventHangup, CallEventReset}, CallIdle},
+		{"Nova chamada após encerrar", []CallEvent{CallEventAnswer, CallEventHangup, CallEventIncoming}, CallRinging},
+		{"Eventos repetidos", []CallEvent{CallEventAnswer, CallEventAnswer, CallEventHangup, CallEventHangup}, CallEnded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCallStateMachine()
+			for _, event := range tt.events {
+				if _, err := c.Fire(event); err != nil {
+					t.Fatalf("Fire(%s): %v", event, err)
+				}
+			}
+			if c.State() != tt.want {
+				t.Errorf("estado = %s, esperado %s", c.State(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCallStateMachineInvalidTransitions(t *testing.T) {
+	invalid := []struct {
+		events []CallEvent
+		bad    CallEvent
+	}{
+		{nil, CallEventHold},
+		{nil, CallEventMute},
+		{nil, CallEventReject},
+		{[]CallEvent{CallEventAnswer}, CallEventIncoming},
+		{[]CallEvent{CallEventAnswer}, CallEventReject},
+		{[]CallEvent{CallEventAnswer, CallEventHold}, CallEventMute},
+	}
+
+	for _, tt := range invalid {
+		c := NewCallStateMachine()
+		for _, event := range tt.events {
+			c.Fire(event)
+		}
+		before := c.Info()
+		if _, err := c.Fire(tt.bad); !errors.Is(err, ErrInvalidCallTransition) {
+			t.Errorf("%s em %s: esperado ErrInvalidCallTransition, obtido %v", tt.bad, before.State, err)
+		}
+		if after := c.Info(); after.State != before.State || !after.Since.Equal(before.Since) {
+			t.Errorf("%s em %s: transição inválida alterou o estado", tt.bad, before.State)
+		}
+	}
+}
+
+func TestCallStateMachineTimestamps(t *testing.T) {
+	now := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	c := NewCallStateMachine()
+	c.now = func() time.Time { return now }
+
+	c.Fire(CallEventIncoming)
+	now = now.Add(5 * time.Second)
+	c.Fire(CallEventAnswer)
+	now = now.Add(time.Minute)
+	c.Fire(CallEventHold)
+	now = now.Add(time.Minute)
+	c.Fire(CallEventHangup)
+
+	info := c.Info()
+	if info.State != "ended" || info.LastEvent != "hangup" || !info.Since.Equal(now) {
+		t.Errorf("estado final incorreto: %+v", info)
+	}
+	if info.StartedAt == nil || !info.StartedAt.Equal(now.Add(-2*time.Minute)) {
+		t.Errorf("início da chamada incorreto: %v", info.StartedAt)
+	}
+	if info.EndedAt == nil || !info.EndedAt.Equal(now) {
+		t.Errorf("fim da chamada incorreto: %v", info.EndedAt)
+	}
+}
+
+func TestMonitorCallState(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "call_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	m := NewMonitor(nil, "", store)
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: &DeviceInfo{ID: 1, Name: "Jabra Engage 55", SerialNumber: "HEADSET-1"}})
+
+	state := func() string { return m.GetTelemetry().State.Call.State }
+
+	m.HandleCallEvent(CallEventIncoming, "socket:ligacao_interna")
+	if state() != "ringing" {
+		t.Fatalf("esperado ringing, obtido %s", state())
+	}
+
+	// Hook absoluto: pacotes repetidos não invertem o estado
+	m.handleButtonEvent(ButtonEvent{DeviceID: 1, ButtonID: ButtonHookSwitch, Pressed: true, Absolute: true})
+	m.handleButtonEvent(ButtonEvent{DeviceID: 1, ButtonID: ButtonHookSwitch, Pressed: true, Absolute: true})
+	if tel := m.GetTelemetry(); tel.State.Call.State != "in_call" || !tel.State.IsInCall {
+		t.Fatalf("esperado in_call, obtido %+v", tel.State.Call)
+	}
+
+	// Atendimento confirmado pelo socket é idempotente
+	m.HandleCallEvent(CallEventAnswer, "socket:ligacao_atendida")
+
+	m.handleButtonEvent(ButtonEvent{DeviceID: 1, ButtonID: ButtonMute, Pressed: true})
+	if state() != "muted" {
+		t.Fatalf("esperado muted, obtido %s", state())
+	}
+	m.handleButtonEvent(ButtonEvent{DeviceID: 1, ButtonID: ButtonMute, Pressed: true})
+	if state() != "in_call" {
+		t.Fatalf("esperado in_call após desmutar, obtido %s", state())
+	}
+
+	if err := m.SetHold(1, true); err != nil || state() != "held" {
+		t.Fatalf("SetHold: %v (estado %s)", err, state())
+	}
+
+	// Toggle do hook durante a chamada encerra, qualquer que seja o histórico
+	m.handleButtonEvent(ButtonEvent{DeviceID: 1, ButtonID: ButtonHookSwitch, Pressed: true})
+	if tel := m.GetTelemetry(); tel.State.Call.State != "ended" || tel.State.IsInCall {
+		t.Fatalf("esperado ended, obtido %+v", tel.State.Call)
+	}
+
+	// Transição inválida é ignorada
+	m.HandleCallEvent(CallEventHold, "teste")
+	if state() != "ended" {
+		t.Errorf("transição inválida alterou o estado: %s", state())
+	}
+
+	if err := m.SetHookState(1, true); err != nil || state() != "in_call" {
+		t.Errorf("SetHookState: %v (estado %s)", err, state())
+	}
+	if err := m.SetHookState(9, true); err == nil {
+		t.Error("esperado erro para dispositivo desconhecido")
+	}
+}
+
+// Pacotes legados de hook (sem descritor) informam o sentido: on-hook em
+// idle não inicia chamada
+func TestMonitorLegacyHookPackets(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "call_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	m := NewMonitor(nil, "", store)
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: &DeviceInfo{ID: 1, Name: "Jabra Link 380"}})
+
+	state := func() string { return m.GetTelemetry().State.Call.State }
+	packet := func(data ...byte) {
+		t.Helper()
+		event, ok := legacyButtonEvent(data)
+		if !ok {
+			t.Fatalf("pacote %x não reconhecido", data)
+		}
+		event.DeviceID = 1
+		m.handleButtonEvent(event)
+	}
+
+	packet(0x01, 0x04)
+	if state() != "idle" {
+		t.Fatalf("on-hook em idle iniciou chamada: %s", state())
+	}
+	packet(0x01, 0x01)
+	packet(0x01, 0x01)
+	if state() != "in_call" {
+		t.Fatalf("esperado in_call após off-hook, obtido %s", state())
+	}
+	packet(0x01, 0x04)
+	packet(0x01, 0x04)
+	if state() != "ended" {
+		t.Errorf("esperado ended após on-hook, obtido %s", state())
+	}
+}
+
+func TestMonitorMuteDuringHold(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "call_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	m := NewMonitor(nil, "", store)
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: &DeviceInfo{ID: 1, Name: "Jabra Engage 55", SerialNumber: "HEADSET-1"}})
+
+	state := func() string { return m.GetTelemetry().State.Call.State }
+
+	m.HandleCallEvent(CallEventAnswer, "teste")
+	if err := m.SetMute(1, true, "teste"); err != nil || state() != "muted" {
+		t.Fatalf("SetMute: %v (estado %s)", err, state())
+	}
+	if err := m.SetHold(1, true); err != nil || state() != "held" {
+		t.Fatalf("SetHold(true): %v (estado %s)", err, state())
+	}
+
+	// Retorno da espera com o microfone ainda mudo
+	if err := m.SetHold(1, false); err != nil || state() != "muted" {
+		t.Fatalf("SetHold(false): %v (estado %s), want muted", err, state())
+	}
+
+	// Desmutado durante a espera, o retorno vai para in_call
+	m.SetHold(1, true)
+	m.SetMute(1, false, "teste")
+	if err := m.SetHold(1, false); err != nil || state() != "in_call" {
+		t.Errorf("SetHold(false): %v (estado %s), want in_call", err, state())
+	}
+}
+
+func TestMonitorCallEndsOnDisconnect(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "call_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	m := NewMonitor(nil, "", store)
+	device := &DeviceInfo{ID: 1, Name: "Jabra Engage 55", SerialNumber: "HEADSET-1"}
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: device})
+
+	m.HandleCallEvent(CallEventAnswer, "teste")
+	m.HandleCallEvent(CallEventMute, "teste")
+	m.handleDeviceDisconnected(DeviceEvent{DeviceID: 1, Device: device})
+
+	tel := m.GetTelemetry()
+	if tel.State.Call.State != "ended" || tel.State.IsInCall || tel.State.Call.LastEvent != "hangup" {
+		t.Errorf("chamada após desconexão = %+v (IsInCall %v), want ended", tel.State.Call, tel.State.IsInCall)
+	}
+
+	// Reconectado, o dispositivo aceita uma nova chamada
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: device})
+	m.HandleCallEvent(CallEventIncoming, "teste")
+	if state := m.GetTelemetry().State.Call.State; state != "ringing" {
+		t.Errorf("esperado ringing após reconectar, obtido %s", state)
+	}
+}
+
+func TestMonitorEventBus(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "bus_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	bus := events.NewBus()
+	defer bus.Close()
+
+	m := NewMonitor(nil, "", store)
+	m.SetEventBus(bus)
+	sub := bus.Subscribe(events.Options{Name: "test"})
+
+	next := func() events.Event {
+		t.Helper()
+		select {
+		case event := <-sub.Events():
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("timeout aguardando evento")
+		}
+		return nil
+	}
+
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: &DeviceInfo{ID: 1, Name: "Jabra Engage 55", SerialNumber: "HEADSET-1"}})
+	if attached, ok := next().(events.DeviceAttached); !ok || attached.Serial != "HEADSET-1" {
+		t.Fatalf("esperado DeviceAttached, obtido %+v", attached)
+	}
+
+	// ligacao_interna de outro ramal toca no headset
+	bus.Publish(events.SocketEvent{Name: "ligacao_interna", Ramal: "2001"})
+	if socket, ok := next().(events.SocketEvent); !ok || socket.Name != "ligacao_interna" {
+		t.Fatalf("esperado SocketEvent, obtido %+v", socket)
+	}
+	if call, ok := next().(events.CallState); !ok || call.To != "ringing" || call.Source != "socket:ligacao_interna" {
+		t.Fatalf("esperado CallState ringing, obtido %+v", call)
+	}
+
+	m.handleButtonEvent(ButtonEvent{DeviceID: 1, ButtonID: ButtonOffHook, Pressed: true})
+	if button, ok := next().(events.Button); !ok || button.Button != "OffHook" || !button.Pressed {
+		t.Fatalf("esperado Button, obtido %+v", button)
+	}
+	if call, ok := next().(events.CallState); !ok || call.To != "in_call" {
+		t.Fatalf("esperado CallState in_call, obtido %+v", call)
+	}
+
+	m.handleBatteryUpdate(1, BatteryStatus{Level: 70})
+	if battery, ok := next().(events.Battery); !ok || battery.Level != 70 {
+		t.Fatalf("esperado Battery, obtido %+v", battery)
+	}
+
+	// Desconexão encerra a chamada antes de publicar a remoção
+	m.handleDeviceDisconnected(DeviceEvent{DeviceID: 1})
+	if call, ok := next().(events.CallState); !ok || call.To != "ended" || call.Source != "disconnect" {
+		t.Fatalf("esperado CallState ended, obtido %+v", call)
+	}
+	if detached, ok := next().(events.DeviceDetached); !ok || detached.DeviceID != 1 {
+		t.Fatalf("esperado DeviceDetached, obtido %+v", detached)
+	}
+}
@@ -0,0 +1,93 @@
+package jabra
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Capability identifica uma operação de controle ou leitura do dispositivo
+type Capability string
+
+const (
+	CapabilityMute      Capability = "mute"
+	CapabilityRinger    Capability = "ringer"
+	CapabilityHook      Capability = "hook"
+	CapabilityBusylight Capability = "busylight"
+	CapabilityHold      Capability = "hold"
+	CapabilityVolume    Capability = "volume"
+	CapabilityBattery   Capability = "battery"
+	CapabilitySettings  Capability = "settings"
+	CapabilityWear      Capability = "wear"
+)
+
+// Capabilities informa o que um dispositivo suporta no driver atual
+type Capabilities struct {
+	Mute      bool `json:"mute"`
+	Ringer    bool `json:"ringer"`
+	Hook      bool `json:"hook"`
+	Busylight bool `json:"busylight"`
+	Hold      bool `json:"hold"`
+	Volume    bool `json:"volume"`
+	Battery   bool `json:"battery"`
+	Settings  bool `json:"settings"` // Leitura/escrita de configurações do dispositivo
+	Wear      bool `json:"wear"`     // Sensor de uso (headset colocado/retirado)
+
+	// VolumeButtons indica que os botões de volume ajustam o volume no
+	// próprio dispositivo; o passo da ação volume do keymap não é somado
+	VolumeButtons bool `json:"volume_buttons"`
+}
+
+// AllCapabilities retorna um conjunto com todas as capacidades
+func AllCapabilities() Capabilities {
+	return Capabilities{Mute: true, Ringer: true, Hook: true, Busylight: true, Hold: true, Volume: true, Battery: true, Settings: true, Wear: true}
+}
+
+// Has retorna true se a capacidade é suportada
+func (c Capabilities) Has(capability Capability) bool {
+	switch capability {
+	case CapabilityMute:
+		return c.Mute
+	case CapabilityRinger:
+		return c.Ringer
+	case CapabilityHook:
+		return c.Hook
+	case CapabilityBusylight:
+		return c.Busylight
+	case CapabilityHold:
+		return c.Hold
+	case CapabilityVolume:
+		return c.Volume
+	case CapabilityBattery:
+		return c.Battery
+	case CapabilitySettings:
+		return c.Settings
+	case CapabilityWear:
+		return c.Wear
+	}
+	return false
+}
+
+// ErrNotSupported indica uma operação que o dispositivo ou o driver não
+// suporta. Os drivers retornam *NotSupportedError, que casa com
+// errors.Is(err, ErrNotSupported).
+var ErrNotSupported = errors.New("operation not supported")
+
+// NotSupportedError detalha a capacidade ausente
+type NotSupportedError struct {
+	Capability Capability
+	DeviceID   uint16
+}
+
+func (e *NotSupportedError) Error() string {
+	return fmt.Sprintf("%s not supported by device %d", e.Capability, e.DeviceID)
+}
+
+// Is faz errors.Is(err, ErrNotSupported) reconhecer o erro
+func (e *NotSupportedError) Is(target error) bool {
+	return target == ErrNotSupported
+}
+
+// notSupported cria o erro tipado para a capacidade do dispositivo
+func notSupported(capability Capability, deviceID uint16) error {
+	return &NotSupportedError{Capability: capability, DeviceID: deviceID}
+}
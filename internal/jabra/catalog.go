@@ -0,0 +1,236 @@
+package jabra
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FormFactor é o tipo físico do produto
+type FormFactor string
+
+const (
+	FormFactorUnknown      FormFactor = "unknown"
+	FormFactorDongle       FormFactor = "dongle"
+	FormFactorHeadset      FormFactor = "headset"
+	FormFactorSpeakerphone FormFactor = "speakerphone"
+)
+
+// Product descreve um modelo Jabra do catálogo
+type Product struct {
+	ProductID    uint16       `json:"-"`
+	Model        string       `json:"model"`
+	Family       string       `json:"family"`
+	FormFactor   FormFactor   `json:"form_factor"`
+	Wireless     bool         `json:"wireless"`
+	Capabilities Capabilities `json:"capabilities"` // Capacidades conhecidas do hardware
+}
+
+// builtinProducts são os modelos conhecidos de fábrica (Vendor ID 0x0b0e).
+// Produtos sem Product ID (0) são casados pelo nome reportado pelo
+// dispositivo: Engage 55, Evolve2 65 e Link 380/400 têm um Product ID por
+// variante (mono/stereo, USB-A/USB-C). Modelos ausentes são adicionados por
+// arquivo (Catalog.LoadFile).
+var builtinProducts = []Product{
+	{ProductID: 0x0348, Model: "Jabra UC Voice 550a MS", Family: "UC Voice", FormFactor: FormFactorHeadset,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true}},
+	{ProductID: 0x0412, Model: "Jabra Speak 410", Family: "Speak", FormFactor: FormFactorSpeakerphone,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true}},
+	{ProductID: 0x0420, Model: "Jabra Speak 510", Family: "Speak", FormFactor: FormFactorSpeakerphone, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true, Battery: true}},
+	{ProductID: 0x0422, Model: "Jabra Speak 510", Family: "Speak", FormFactor: FormFactorSpeakerphone, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true, Battery: true}},
+	{ProductID: 0x1017, Model: "Jabra Pro 930", Family: "Pro", FormFactor: FormFactorHeadset, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true}},
+	{ProductID: 0x1022, Model: "Jabra Pro 9450", Family: "Pro", FormFactor: FormFactorHeadset, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Hold: true, Volume: true, Battery: true}},
+	{ProductID: 0x1041, Model: "Jabra Pro 9460", Family: "Pro", FormFactor: FormFactorHeadset, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Hold: true, Volume: true, Battery: true}},
+	{ProductID: 0x1900, Model: "Jabra Biz 1900", Family: "Biz", FormFactor: FormFactorHeadset,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true}},
+	{ProductID: 0x2007, Model: "Jabra GN2000 Stereo", Family: "GN2000", FormFactor: FormFactorHeadset,
+		Capabilities: Capabilities{Mute: true, Hook: true, Volume: true}},
+	{ProductID: 0x2456, Model: "Jabra Speak 810", Family: "Speak", FormFactor: FormFactorSpeakerphone,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true}},
+	{ProductID: 0x245e, Model: "Jabra Link 370", Family: "Link", FormFactor: FormFactorDongle, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Busylight: true, Hold: true, Volume: true, Battery: true}},
+	{ProductID: 0x620c, Model: "Jabra BT620s", Family: "BT", FormFactor: FormFactorHeadset, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Hook: true, Volume: true, Battery: true}},
+	{ProductID: 0x9330, Model: "Jabra GN9330", Family: "GN9300", FormFactor: FormFactorHeadset, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Volume: true}},
+
+	// Modelos em uso nos postos, casados pelo nome
+	{Model: "Jabra Engage 55", Family: "Engage", FormFactor: FormFactorHeadset, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Busylight: true, Hold: true, Volume: true, Battery: true}},
+	{Model: "Jabra Evolve2 65", Family: "Evolve2", FormFactor: FormFactorHeadset, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Busylight: true, Hold: true, Volume: true, Battery: true}},
+	{Model: "Jabra Link 380", Family: "Link", FormFactor: FormFactorDongle, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Busylight: true, Hold: true, Volume: true, Battery: true}},
+	{Model: "Jabra Link 400", Family: "Link", FormFactor: FormFactorDongle, Wireless: true,
+		Capabilities: Capabilities{Mute: true, Ringer: true, Hook: true, Busylight: true, Hold: true, Volume: true, Battery: true}},
+}
+
+// Catalog mapeia Product IDs (ou, sem Product ID, nomes) para modelos
+type Catalog struct {
+	mu       sync.RWMutex
+	products map[uint16]Product
+	named    []Product // Produtos sem Product ID, casados pelo nome
+}
+
+// NewCatalog cria um catálogo com os produtos informados
+func NewCatalog(products []Product) *Catalog {
+	c := &Catalog{products: make(map[uint16]Product, len(products))}
+	for _, p := range products {
+		c.set(p)
+	}
+	return c
+}
+
+// set adiciona ou substitui um produto (deve ser chamado com lock ou
+// durante a construção)
+func (c *Catalog) set(p Product) {
+	if p.ProductID != 0 {
+		c.products[p.ProductID] = p
+		return
+	}
+	for i, named := range c.named {
+		if strings.EqualFold(named.Model, p.Model) {
+			c.named[i] = p
+			return
+		}
+	}
+	c.named = append(c.named, p)
+}
+
+// defaultCatalog é o catálogo usado pelos drivers
+var defaultCatalog = NewCatalog(builtinProducts)
+
+// DefaultCatalog retorna o catálogo usado pelos drivers para enriquecer o
+// DeviceInfo; overrides carregados nele valem para as próximas conexões
+func DefaultCatalog() *Catalog {
+	return defaultCatalog
+}
+
+// Lookup retorna o produto de um Product ID
+func (c *Catalog) Lookup(productID uint16) (Product, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.products[productID]
+	return p, ok
+}
+
+// LookupName retorna o produto sem Product ID cujo modelo aparece no nome
+// reportado pelo dispositivo ("Jabra Link 380a MS" casa "Jabra Link 380").
+// Com mais de um candidato vale o modelo mais longo.
+func (c *Catalog) LookupName(name string) (Product, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	lower := strings.ToLower(name)
+	var best Product
+	found := false
+	for _, p := range c.named {
+		if strings.Contains(lower, strings.ToLower(p.Model)) && (!found || len(p.Model) > len(best.Model)) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
+
+// Products retorna todos os produtos do catálogo
+func (c *Catalog) Products() []Product {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	products := make([]Product, 0, len(c.products)+len(c.named))
+	for _, p := range c.products {
+		products = append(products, p)
+	}
+	return append(products, c.named...)
+}
+
+// Set adiciona ou substitui um produto; sem Product ID o produto é casado
+// pelo nome
+func (c *Catalog) Set(p Product) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(p)
+}
+
+// LoadFile aplica overrides de um arquivo JSON indexado pelo Product ID em
+// hexadecimal: {"0x245e": {"model": "...", "form_factor": "dongle", ...}}
+func (c *Catalog) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var entries map[string]Product
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	products := make([]Product, 0, len(entries))
+	for key, p := range entries {
+		id, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(key), "0x"), 16, 16)
+		if err != nil {
+			return fmt.Errorf("%s: invalid product id %q", path, key)
+		}
+		if p.FormFactor == "" {
+			p.FormFactor = FormFactorUnknown
+		}
+		p.ProductID = uint16(id)
+		products = append(products, p)
+	}
+	for _, p := range products {
+		c.Set(p)
+	}
+
+	log.Printf("[Jabra] Catálogo: %d produtos carregados de %s", len(products), path)
+	return nil
+}
+
+// Enrich preenche modelo, família, formato e conectividade do DeviceInfo
+// pelo Product ID ou, sem ele no catálogo, pelo nome. Produtos fora do
+// catálogo mantêm o nome do driver e o formato é inferido pelo nome (ex.:
+// "Link" é dongle).
+func (c *Catalog) Enrich(info *DeviceInfo) {
+	p, ok := c.Lookup(info.ProductID)
+	if !ok {
+		p, ok = c.LookupName(info.Name)
+	}
+	if !ok {
+		info.Model = info.Name
+		if info.FormFactor == "" {
+			info.FormFactor = inferFormFactor(info.Name, info.IsDongle)
+		}
+		info.IsDongle = info.FormFactor == FormFactorDongle
+		return
+	}
+
+	if info.Name == "" {
+		info.Name = p.Model
+	}
+	info.Model = p.Model
+	info.Family = p.Family
+	info.FormFactor = p.FormFactor
+	info.Wireless = p.Wireless
+	info.IsDongle = p.FormFactor == FormFactorDongle
+}
+
+// inferFormFactor estima o formato de produtos desconhecidos
+func inferFormFactor(name string, dongle bool) FormFactor {
+	lower := strings.ToLower(name)
+	switch {
+	case dongle || strings.Contains(lower, "link"):
+		return FormFactorDongle
+	case strings.Contains(lower, "speak"):
+		return FormFactorSpeakerphone
+	case name == "":
+		return FormFactorUnknown
+	}
+	return FormFactorHeadset
+}
@@ -0,0 +1,122 @@
+package jabra
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCatalogEnrich(t *testing.T) {
+	catalog := NewCatalog(builtinProducts)
+
+	tests := []struct {
+		name       string
+		info       DeviceInfo
+		model      string
+		formFactor FormFactor
+		dongle     bool
+	}{
+		{"dongle do catálogo", DeviceInfo{ProductID: 0x245e}, "Jabra Link 370", FormFactorDongle, true},
+		{"speakerphone do catálogo", DeviceInfo{ProductID: 0x0422, Name: "Jabra SPEAK 510 USB"}, "Jabra Speak 510", FormFactorSpeakerphone, false},
+		{"speakerphone com fio", DeviceInfo{ProductID: 0x0412}, "Jabra Speak 410", FormFactorSpeakerphone, false},
+		{"dongle pelo nome", DeviceInfo{ProductID: 0xfff0, Name: "Jabra Link 380a MS"}, "Jabra Link 380", FormFactorDongle, true},
+		{"headset pelo nome", DeviceInfo{ProductID: 0xfff1, Name: "Jabra Engage 55 Mono"}, "Jabra Engage 55", FormFactorHeadset, false},
+		{"dongle desconhecido", DeviceInfo{ProductID: 0xfff4, Name: "Jabra Link 390"}, "Jabra Link 390", FormFactorDongle, true},
+		{"headset desconhecido", DeviceInfo{ProductID: 0xfff5, Name: "Jabra Evolve 40"}, "Jabra Evolve 40", FormFactorHeadset, false},
+		{"nome iniciado por L não é dongle", DeviceInfo{ProductID: 0xfff2, Name: "Lync Headset"}, "Lync Headset", FormFactorHeadset, false},
+		{"dongle informado pelo SDK", DeviceInfo{ProductID: 0xfff3, Name: "Jabra", IsDongle: true}, "Jabra", FormFactorDongle, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := tt.info
+			catalog.Enrich(&info)
+			if info.Model != tt.model || info.FormFactor != tt.formFactor || info.IsDongle != tt.dongle {
+				t.Errorf("Enrich() = model %q, form %q, dongle %v; want %q, %q, %v",
+					info.Model, info.FormFactor, info.IsDongle, tt.model, tt.formFactor, tt.dongle)
+			}
+			if info.Name == "" {
+				t.Error("nome deveria ser preenchido pelo modelo")
+			}
+		})
+	}
+}
+
+// Os pares dongle/headset dos cenários de simulação são os modelos em uso
+func TestCatalogScenarioDevices(t *testing.T) {
+	catalog := NewCatalog(builtinProducts)
+	paths, err := filepath.Glob(filepath.Join("..", "..", "config", "scenarios", "*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("cenários não encontrados: %v", err)
+	}
+
+	pairs := 0
+	for _, path := range paths {
+		scenario, err := LoadSimScenario(path)
+		if err != nil {
+			t.Fatalf("LoadSimScenario(%s): %v", path, err)
+		}
+		devices := make(map[uint16]DeviceInfo)
+		for _, step := range scenario.Steps {
+			if step.Action != SimAttach {
+				continue
+			}
+			info := DeviceInfo{Name: step.Name, ProductID: step.ProductID, IsDongle: step.Dongle}
+			catalog.Enrich(&info)
+			if info.Family == "" {
+				t.Errorf("%s: %q fora do catálogo", scenario.Name, step.Name)
+			}
+			devices[step.Device] = info
+
+			if step.Parent == nil {
+				continue
+			}
+			pairs++
+			parent := devices[*step.Parent]
+			if !parent.IsDongle || !parent.Wireless || info.FormFactor != FormFactorHeadset || !info.Wireless {
+				t.Errorf("%s: par %q -> %q = %+v / %+v, want dongle e headset sem fio",
+					scenario.Name, parent.Name, info.Name, parent, info)
+			}
+		}
+	}
+	if pairs == 0 {
+		t.Error("nenhum par dongle/headset nos cenários")
+	}
+}
+
+func TestCatalogLoadFile(t *testing.T) {
+	catalog := NewCatalog(builtinProducts)
+	path := filepath.Join(t.TempDir(), "products.json")
+	data := `{
+		"0x245E": {"model": "Jabra Link 370 MS", "family": "Link", "form_factor": "dongle", "wireless": true},
+		"fff0": {"model": "Jabra Engage 55 Mono", "family": "Engage", "form_factor": "headset", "wireless": true,
+			"capabilities": {"mute": true, "battery": true}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := catalog.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() erro inesperado: %v", err)
+	}
+
+	if p, _ := catalog.Lookup(0x245e); p.Model != "Jabra Link 370 MS" {
+		t.Errorf("override não aplicado: %+v", p)
+	}
+	p, ok := catalog.Lookup(0xfff0)
+	if !ok || p.Family != "Engage" || !p.Wireless || !p.Capabilities.Battery || p.Capabilities.Volume {
+		t.Errorf("produto novo inesperado: %+v", p)
+	}
+	if _, ok := catalog.Lookup(0x0420); !ok {
+		t.Error("produtos de fábrica devem ser mantidos")
+	}
+
+	if err := os.WriteFile(path, []byte(`{"xyz": {"model": "?"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := catalog.LoadFile(path); err == nil {
+		t.Error("Product ID inválido deveria falhar")
+	}
+	if err := catalog.LoadFile(filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("arquivo ausente deveria retornar os.ErrNotExist: %v", err)
+	}
+}
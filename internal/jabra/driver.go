@@ -1,6 +1,9 @@
 package jabra
 
-import "time"
+import (
+	"sort"
+	"time"
+)
 
 // ButtonID representa os IDs de botões traduzidos do Jabra SDK
 type ButtonID int
@@ -51,59 +54,90 @@ const (
 	ButtonVolumeUp
 )
 
+// buttonNames são os nomes usados em logs e no keymap
+var buttonNames = map[ButtonID]string{
+	ButtonCyclic:           "Cyclic",
+	ButtonCyclicEnd:        "CyclicEnd",
+	ButtonDecline:          "Decline",
+	ButtonDialNext:         "DialNext",
+	ButtonDialPrev:         "DialPrev",
+	ButtonEndCall:          "EndCall",
+	ButtonFireAlarm:        "FireAlarm",
+	ButtonFlash:            "Flash",
+	ButtonFlexibleBootMute: "FlexibleBootMute",
+	ButtonGN1:              "GN1",
+	ButtonGN2:              "GN2",
+	ButtonGN3:              "GN3",
+	ButtonGN4:              "GN4",
+	ButtonGN5:              "GN5",
+	ButtonGN6:              "GN6",
+	ButtonHookSwitch:       "HookSwitch",
+	ButtonJabra:            "Jabra",
+	ButtonKey0:             "Key0",
+	ButtonKey1:             "Key1",
+	ButtonKey2:             "Key2",
+	ButtonKey3:             "Key3",
+	ButtonKey4:             "Key4",
+	ButtonKey5:             "Key5",
+	ButtonKey6:             "Key6",
+	ButtonKey7:             "Key7",
+	ButtonKey8:             "Key8",
+	ButtonKey9:             "Key9",
+	ButtonKeyClear:         "KeyClear",
+	ButtonKeyPound:         "KeyPound",
+	ButtonKeyStar:          "KeyStar",
+	ButtonLineBusy:         "LineBusy",
+	ButtonMute:             "Mute",
+	ButtonOffline:          "Offline",
+	ButtonOffHook:          "OffHook",
+	ButtonOnline:           "Online",
+	ButtonPseudoOffHook:    "PseudoOffHook",
+	ButtonRedial:           "Redial",
+	ButtonRejectCall:       "RejectCall",
+	ButtonSpeedDial:        "SpeedDial",
+	ButtonTransfer:         "Transfer",
+	ButtonVoiceMail:        "VoiceMail",
+	ButtonVolumeDown:       "VolumeDown",
+	ButtonVolumeUp:         "VolumeUp",
+}
+
 // String retorna o nome do botão para logging e keymap
 func (b ButtonID) String() string {
-	names := map[ButtonID]string{
-		ButtonCyclic:           "Cyclic",
-		ButtonCyclicEnd:        "CyclicEnd",
-		ButtonDecline:          "Decline",
-		ButtonDialNext:         "DialNext",
-		ButtonDialPrev:         "DialPrev",
-		ButtonEndCall:          "EndCall",
-		ButtonFireAlarm:        "FireAlarm",
-		ButtonFlash:            "Flash",
-		ButtonFlexibleBootMute: "FlexibleBootMute",
-		ButtonGN1:              "GN1",
-		ButtonGN2:              "GN2",
-		ButtonGN3:              "GN3",
-		ButtonGN4:              "GN4",
-		ButtonGN5:              "GN5",
-		ButtonGN6:              "GN6",
-		ButtonHookSwitch:       "HookSwitch",
-		ButtonJabra:            "Jabra",
-		ButtonKey0:             "Key0",
-		ButtonKey1:             "Key1",
-		ButtonKey2:             "Key2",
-		ButtonKey3:             "Key3",
-		ButtonKey4:             "Key4",
-		ButtonKey5:             "Key5",
-		ButtonKey6:             "Key6",
-		ButtonKey7:             "Key7",
-		ButtonKey8:             "Key8",
-		ButtonKey9:             "Key9",
-		ButtonKeyClear:         "KeyClear",
-		ButtonKeyPound:         "KeyPound",
-		ButtonKeyStar:          "KeyStar",
-		ButtonLineBusy:         "LineBusy",
-		ButtonMute:             "Mute",
-		ButtonOffline:          "Offline",
-		ButtonOffHook:          "OffHook",
-		ButtonOnline:           "Online",
-		ButtonPseudoOffHook:    "PseudoOffHook",
-		ButtonRedial:           "Redial",
-		ButtonRejectCall:       "RejectCall",
-		ButtonSpeedDial:        "SpeedDial",
-		ButtonTransfer:         "Transfer",
-		ButtonVoiceMail:        "VoiceMail",
-		ButtonVolumeDown:       "VolumeDown",
-		ButtonVolumeUp:         "VolumeUp",
-	}
-	if name, ok := names[b]; ok {
+	if name, ok := buttonNames[b]; ok {
 		return name
 	}
 	return "Unknown"
 }
 
+// ParseButtonID converte o nome usado no keymap de volta para o ButtonID
+func ParseButtonID(name string) (ButtonID, bool) {
+	for id, n := range buttonNames {
+		if n == name {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// ButtonNames retorna os nomes de botão aceitos no keymap, em ordem alfabética
+func ButtonNames() []string {
+	names := make([]string, 0, len(buttonNames))
+	for _, name := range buttonNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LinkState é o estado do rádio entre um headset sem fio e seu dongle/base
+type LinkState string
+
+const (
+	LinkNone       LinkState = ""             // Sem rádio (dispositivo com fio) ou desconhecido
+	LinkConnected  LinkState = "connected"    // Headset ao alcance do dongle
+	LinkOutOfRange LinkState = "out_of_range" // Dongle presente, headset fora de alcance
+)
+
 // DeviceInfo contém informações sobre um dispositivo Jabra
 type DeviceInfo struct {
 	ID           uint16
@@ -114,6 +148,22 @@ type DeviceInfo struct {
 	IsDongle     bool
 	Connected    bool
 	ConnectedAt  time.Time
+
+	// Metadados do catálogo de produtos (Catalog.Enrich)
+	Model      string
+	Family     string
+	FormFactor FormFactor
+	Wireless   bool
+
+	// Topologia: ParentID é o dongle/base ao qual o headset está pareado
+	// (nil = nenhum; 0 é um ID válido). LinkState é o rádio do headset com
+	// o pai; em dongles, o rádio com o headset pareado. Mudanças de rádio
+	// chegam ao monitor como ButtonOnline/ButtonOffline do dispositivo.
+	// Só o simulador preenche ParentID: o SDK não exporta a consulta do
+	// pareamento e reporta o rádio pelo Online/Offline do dongle; no HID
+	// genérico dongle e headset são um único dispositivo USB, sem topologia.
+	ParentID  *uint16
+	LinkState LinkState
 }
 
 // BatteryStatus contém informações sobre a bateria do dispositivo
@@ -128,6 +178,17 @@ type ButtonEvent struct {
 	DeviceID uint16
 	ButtonID ButtonID
 	Pressed  bool // true = pressionado, false = liberado
+
+	// Absolute indica um controle on/off absoluto (ex.: Hook Switch):
+	// Pressed reflete o estado atual em vez de um pulso de toggle
+	Absolute bool
+}
+
+// WearEvent informa que o headset foi colocado (don) ou retirado (doff),
+// reportado pelo sensor de uso dos modelos que o possuem
+type WearEvent struct {
+	DeviceID uint16
+	Worn     bool
 }
 
 // DeviceEvent representa um evento de dispositivo (conectado/desconectado)
@@ -155,6 +216,10 @@ type Driver interface {
 	// GetDevice retorna informações de um dispositivo específico
 	GetDevice(deviceID uint16) (*DeviceInfo, error)
 
+	// Capabilities informa as operações suportadas pelo dispositivo.
+	// Operações fora dele retornam ErrNotSupported (*NotSupportedError).
+	Capabilities(deviceID uint16) (Capabilities, error)
+
 	// GetBatteryStatus obtém status da bateria de um dispositivo
 	GetBatteryStatus(deviceID uint16) (*BatteryStatus, error)
 
@@ -182,6 +247,19 @@ type Driver interface {
 	// GetVolume obtém volume do dispositivo
 	GetVolume(deviceID uint16) (int, error)
 
+	// GetWearState informa se o headset está sendo usado (sensor de uso)
+	GetWearState(deviceID uint16) (bool, error)
+
+	// ListSettings retorna as configurações do dispositivo com os valores atuais
+	ListSettings(deviceID uint16) ([]Setting, error)
+
+	// GetSetting retorna uma configuração (ErrUnknownSetting se não existir)
+	GetSetting(deviceID uint16, key string) (Setting, error)
+
+	// SetSetting altera uma configuração; o valor é validado pelo descritor
+	// (ErrInvalidSettingValue)
+	SetSetting(deviceID uint16, key string, value any) error
+
 	// OnDeviceConnected registra callback para dispositivo conectado
 	OnDeviceConnected(handler func(event DeviceEvent))
 
@@ -193,6 +271,9 @@ type Driver interface {
 
 	// OnBatteryUpdate registra callback para atualização de bateria
 	OnBatteryUpdate(handler func(deviceID uint16, status BatteryStatus))
+
+	// OnWearEvent registra callback para headset colocado/retirado
+	OnWearEvent(handler func(event WearEvent))
 }
 
 // DriverConfig contém configurações para inicialização do driver
@@ -203,19 +284,20 @@ type DriverConfig struct {
 	// VendorID para filtrar dispositivos (HID)
 	VendorID uint16
 
-	// PollInterval é o intervalo de polling para HID driver
+	// PollInterval é o intervalo de polling do HID driver quando a
+	// plataforma não oferece eventos de hotplug
 	PollInterval time.Duration
 
-	// SimulationMode ativa modo de simulação quando hardware não está disponível
-	SimulationMode bool
+	// CaptureFile, se definido, grava o tráfego HID bruto neste arquivo
+	// para reprodução posterior com o ReplayDriver (HID)
+	CaptureFile string
 }
 
 // DefaultConfig retorna configuração padrão
 func DefaultConfig() DriverConfig {
 	return DriverConfig{
-		AppID:          "88b7-5cbde35c-e588-49b3-a6d5-f54278270e28",
-		VendorID:       0x0b0e, // Jabra Vendor ID
-		PollInterval:   2 * time.Second,
-		SimulationMode: false,
+		AppID:        "88b7-5cbde35c-e588-49b3-a6d5-f54278270e28",
+		VendorID:     0x0b0e, // Jabra Vendor ID
+		PollInterval: 2 * time.Second,
 	}
 }
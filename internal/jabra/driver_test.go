@@ -0,0 +1,31 @@
+package jabra
+
+import (
+	"testing"
+	"time"
+)
+
+// waitFor aguarda a condição ou falha o teste após o timeout
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout aguardando %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// receive aguarda um valor no canal ou falha o teste após o timeout
+func receive[T any](t *testing.T, ch chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout aguardando evento")
+	}
+	var zero T
+	return zero
+}
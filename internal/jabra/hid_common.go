@@ -0,0 +1,54 @@
+package jabra
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aiknow/acc_jabra_agent/internal/hidtransport"
+)
+
+// hidDeviceKey identifica um dispositivo físico entre as interfaces HID
+// enumeradas. Sem serial, usa o path sem o sufixo de interface.
+func hidDeviceKey(info hidtransport.DeviceInfo) string {
+	id := info.Serial
+	if id == "" {
+		id = info.Path
+		if i := strings.LastIndex(id, ":"); i > 0 {
+			id = id[:i]
+		}
+	}
+	return fmt.Sprintf("%04x:%04x:%s", info.VendorID, info.ProductID, id)
+}
+
+// legacyButtonEvent interpreta os padrões de pacote observados em alguns
+// headsets, usado quando o descritor de relatório não está disponível. Os
+// pacotes de hook informam o sentido (off/on) e viram eventos absolutos do
+// Hook Switch, como no decodificador por descritor.
+func legacyButtonEvent(data []byte) (ButtonEvent, bool) {
+	if len(data) < 2 {
+		return ButtonEvent{}, false
+	}
+
+	// Protocolo HID Jabra simplificado
+	// Byte 0: Report ID
+	// Byte 1: Estado dos botões
+
+	reportID := data[0]
+	buttonState := data[1]
+
+	if reportID != 0x01 { // Telephony page
+		return ButtonEvent{}, false
+	}
+
+	switch buttonState {
+	case 0x01: // Hook off
+		return ButtonEvent{ButtonID: ButtonHookSwitch, Pressed: true, Absolute: true}, true
+	case 0x02: // Mute
+		return ButtonEvent{ButtonID: ButtonMute, Pressed: true}, true
+	case 0x04: // Hook on
+		return ButtonEvent{ButtonID: ButtonHookSwitch, Pressed: false, Absolute: true}, true
+	case 0x08: // Flash
+		return ButtonEvent{ButtonID: ButtonFlash, Pressed: true}, true
+	}
+	return ButtonEvent{}, false
+}
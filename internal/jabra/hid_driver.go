@@ -6,15 +6,29 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"os"
+	"runtime"
 	"sync"
 	"time"
 
-	"github.com/karalabe/hid"
+	"github.com/aiknow/acc_jabra_agent/internal/hidtransport"
 )
 
 const (
 	// JabraVendorID é o Vendor ID USB da Jabra
 	JabraVendorID uint16 = 0x0b0e
+
+	// hotplugSettleDelay agrupa a rajada de eventos de um mesmo dispositivo
+	// (usb, hid e hidraw de cada interface) em uma única enumeração
+	hotplugSettleDelay = 20 * time.Millisecond
+
+	// hotplugRetryDelay repete a enumeração após um evento, para o caso de
+	// o udev ainda não ter ajustado as permissões do nó no primeiro scan
+	hotplugRetryDelay = time.Second
+
+	// hotplugReconcileInterval é a enumeração de segurança com hotplug ativo,
+	// cobrindo eventos perdidos pelo socket
+	hotplugReconcileInterval = 30 * time.Second
 )
 
 // HIDDriver implementa Driver usando comunicação HID genérica (Linux/macOS)
@@ -24,30 +38,85 @@ type HIDDriver struct {
 	running bool
 	stopCh  chan struct{}
 
+	// Goroutines do driver (scan, leitura e callbacks), aguardadas no Stop
+	workers sync.WaitGroup
+
+	// Acesso ao hardware HID (karalabe/hid ou fake em testes)
+	transport hidtransport.Transport
+
+	// Gravador opcional do tráfego HID (DriverConfig.CaptureFile)
+	recorder *hidtransport.Recorder
+
 	// Dispositivos conectados
 	devices map[uint16]*DeviceInfo
 
-	// Device HID atualmente aberto
-	currentDevice *hid.Device
+	// Handles HID abertos, por dispositivo
+	handles map[uint16]hidtransport.Device
+
+	// Decodificadores baseados no descritor de relatório, por dispositivo
+	decoders map[uint16]*TelephonyDecoder
+
+	// Indicadores de saída (LEDs/ringer), por dispositivo
+	indicators map[uint16]*IndicatorWriter
+
+	// IDs atribuídos por dispositivo físico (estáveis entre reconexões)
+	deviceIDs map[string]uint16
+	nextID    uint16
 
 	// Callbacks
 	onDeviceConnected    func(event DeviceEvent)
 	onDeviceDisconnected func(event DeviceEvent)
 	onButtonEvent        func(event ButtonEvent)
 	onBatteryUpdate      func(deviceID uint16, status BatteryStatus)
-
-	// Estado simulado (quando hardware não disponível)
-	simulationMode  bool
-	simulatedBattery int
 }
 
 // NewHIDDriver cria uma nova instância do driver HID
 func NewHIDDriver(config DriverConfig) (*HIDDriver, error) {
+	return NewHIDDriverWithTransport(config, hidtransport.New())
+}
+
+// newCaptureRecorder abre o arquivo de captura e envolve o transporte
+func newCaptureRecorder(path string, transport hidtransport.Transport) (*hidtransport.Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create capture file: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	recorder := hidtransport.NewRecorder(transport, f, map[string]string{
+		"hostname": hostname,
+		"os":       runtime.GOOS,
+		"driver":   "hid",
+	})
+	log.Printf("[HID Driver] Gravando captura HID em %s", path)
+	return recorder, nil
+}
+
+// NewHIDDriverWithTransport cria o driver HID sobre o transporte informado
+func NewHIDDriverWithTransport(config DriverConfig, transport hidtransport.Transport) (*HIDDriver, error) {
+	if transport == nil {
+		return nil, errors.New("hid transport is nil")
+	}
+
+	var recorder *hidtransport.Recorder
+	if config.CaptureFile != "" {
+		var err error
+		if recorder, err = newCaptureRecorder(config.CaptureFile, transport); err != nil {
+			return nil, err
+		}
+		transport = recorder
+	}
+
 	return &HIDDriver{
-		config:           config,
-		devices:          make(map[uint16]*DeviceInfo),
-		stopCh:           make(chan struct{}),
-		simulatedBattery: 100,
+		config:     config,
+		transport:  transport,
+		recorder:   recorder,
+		devices:    make(map[uint16]*DeviceInfo),
+		handles:    make(map[uint16]hidtransport.Device),
+		decoders:   make(map[uint16]*TelephonyDecoder),
+		indicators: make(map[uint16]*IndicatorWriter),
+		deviceIDs:  make(map[string]uint16),
+		stopCh:     make(chan struct{}),
 	}, nil
 }
 
@@ -64,34 +133,53 @@ func (d *HIDDriver) Start() error {
 	d.stopCh = make(chan struct{})
 
 	// Inicia scanner de dispositivos
-	go d.scanLoop()
+	stopCh := d.stopCh
+	d.spawn(func() { d.scanLoop(stopCh) })
 
 	log.Println("[HID Driver] Iniciado")
 	return nil
 }
 
-// Stop para o driver
+// Stop para o driver e aguarda o término de suas goroutines
 func (d *HIDDriver) Stop() error {
 	d.mu.Lock()
-	defer d.mu.Unlock()
-
 	if !d.running {
+		d.mu.Unlock()
 		return nil
 	}
 
 	close(d.stopCh)
 	d.running = false
 
-	// Fecha dispositivo atual
-	if d.currentDevice != nil {
-		d.currentDevice.Close()
-		d.currentDevice = nil
+	// Fecha dispositivos abertos
+	for id, handle := range d.handles {
+		handle.Close()
+		delete(d.handles, id)
+	}
+
+	if d.recorder != nil {
+		if err := d.recorder.Close(); err != nil {
+			log.Printf("[HID Driver] Erro ao finalizar captura: %v", err)
+		}
 	}
+	d.mu.Unlock()
+
+	// Fora do lock: leituras e callbacks em curso podem precisar dele
+	d.workers.Wait()
 
 	log.Println("[HID Driver] Parado")
 	return nil
 }
 
+// spawn executa fn numa goroutine acompanhada pelo Stop
+func (d *HIDDriver) spawn(fn func()) {
+	d.workers.Add(1)
+	go func() {
+		defer d.workers.Done()
+		fn()
+	}()
+}
+
 // IsRunning retorna se o driver está ativo
 func (d *HIDDriver) IsRunning() bool {
 	d.mu.RLock()
@@ -99,17 +187,69 @@ func (d *HIDDriver) IsRunning() bool {
 	return d.running
 }
 
-// scanLoop procura dispositivos Jabra periodicamente
-func (d *HIDDriver) scanLoop() {
-	ticker := time.NewTicker(d.config.PollInterval)
-	defer ticker.Stop()
-
+// scanLoop procura dispositivos Jabra a cada evento de hotplug do sistema;
+// sem suporte a hotplug, enumera periodicamente (PollInterval)
+func (d *HIDDriver) scanLoop(stopCh chan struct{}) {
 	// Scan inicial
 	d.scanDevices()
 
+	watcher, err := d.transport.Watch()
+	if err != nil {
+		if !errors.Is(err, hidtransport.ErrUnsupported) {
+			log.Printf("[HID Driver] Hotplug indisponível: %v", err)
+		}
+		log.Printf("[HID Driver] Usando polling a cada %v", d.config.PollInterval)
+		d.pollLoop(stopCh)
+		return
+	}
+	defer watcher.Close()
+	log.Println("[HID Driver] Hotplug por eventos do sistema ativo")
+
+	settle := time.NewTimer(time.Hour)
+	settle.Stop()
+	retry := time.NewTimer(time.Hour)
+	retry.Stop()
+	reconcile := time.NewTicker(hotplugReconcileInterval)
+	defer func() {
+		settle.Stop()
+		retry.Stop()
+		reconcile.Stop()
+	}()
+
+	events := watcher.Events()
 	for {
 		select {
-		case <-d.stopCh:
+		case <-stopCh:
+			return
+		case ev, ok := <-events:
+			if !ok {
+				log.Printf("[HID Driver] Hotplug encerrado, voltando ao polling a cada %v", d.config.PollInterval)
+				d.pollLoop(stopCh)
+				return
+			}
+			if ev.VendorID != 0 && ev.VendorID != JabraVendorID {
+				continue
+			}
+			settle.Reset(hotplugSettleDelay)
+			retry.Reset(hotplugRetryDelay)
+		case <-settle.C:
+			d.scanDevices()
+		case <-retry.C:
+			d.scanDevices()
+		case <-reconcile.C:
+			d.scanDevices()
+		}
+	}
+}
+
+// pollLoop enumera os dispositivos a cada PollInterval
+func (d *HIDDriver) pollLoop(stopCh chan struct{}) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stopCh:
 			return
 		case <-ticker.C:
 			d.scanDevices()
@@ -117,97 +257,176 @@ func (d *HIDDriver) scanLoop() {
 	}
 }
 
-// scanDevices enumera dispositivos HID Jabra
+// scanDevices enumera dispositivos HID Jabra. Os callbacks de conexão e
+// desconexão são chamados em ordem pela própria goroutine do scan, fora do
+// lock, com cópias de DeviceInfo.
 func (d *HIDDriver) scanDevices() {
-	devices := hid.Enumerate(JabraVendorID, 0)
+	devices, err := d.transport.Enumerate(JabraVendorID, 0)
+	if err != nil {
+		log.Printf("[HID Driver] Erro ao enumerar dispositivos: %v", err)
+		return
+	}
+
+	var notify []func()
+	defer func() {
+		for _, fn := range notify {
+			fn()
+		}
+	}()
 
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	// Marca dispositivos conhecidos como potencialmente desconectados
+	// Agrupa as interfaces HID por dispositivo físico
 	currentDeviceIDs := make(map[uint16]bool)
+	interfaces := make(map[uint16][]hidtransport.DeviceInfo)
+	var order []uint16
 
 	for _, devInfo := range devices {
-		deviceID := uint16(devInfo.ProductID)
+		key := hidDeviceKey(devInfo)
+		deviceID, known := d.deviceIDs[key]
+		if !known {
+			d.nextID++
+			deviceID = d.nextID
+			d.deviceIDs[key] = deviceID
+		}
+
+		if !currentDeviceIDs[deviceID] {
+			order = append(order, deviceID)
+		}
 		currentDeviceIDs[deviceID] = true
+		interfaces[deviceID] = append(interfaces[deviceID], devInfo)
+	}
+
+	for _, deviceID := range order {
+		devInfo := interfaces[deviceID][0]
 
 		// Dispositivo novo?
 		if _, exists := d.devices[deviceID]; !exists {
 			info := &DeviceInfo{
-				ID:          deviceID,
-				Name:        devInfo.Product,
+				ID:           deviceID,
+				Name:         devInfo.Product,
 				SerialNumber: devInfo.Serial,
-				VendorID:    uint16(devInfo.VendorID),
-				ProductID:   uint16(devInfo.ProductID),
-				IsDongle:    d.isDongle(devInfo.Product),
-				Connected:   true,
-				ConnectedAt: time.Now(),
+				VendorID:     uint16(devInfo.VendorID),
+				ProductID:    uint16(devInfo.ProductID),
+				Connected:    true,
+				ConnectedAt:  time.Now(),
 			}
+			defaultCatalog.Enrich(info)
 
 			d.devices[deviceID] = info
-			d.simulationMode = false
 
 			log.Printf("[HID Driver] Dispositivo conectado: %s (ID: %d)", info.Name, info.ID)
 
 			// Notifica callback
-			if d.onDeviceConnected != nil {
-				go d.onDeviceConnected(DeviceEvent{
+			if handler := d.onDeviceConnected; handler != nil {
+				device := *info
+				event := DeviceEvent{
 					DeviceID:  deviceID,
 					Connected: true,
-					Device:    info,
-				})
+					Device:    &device,
+				}
+				notify = append(notify, func() { handler(event) })
 			}
 
 			// Tenta abrir dispositivo para leitura de eventos
-			go d.tryOpenDevice(devInfo)
+			devInterfaces := interfaces[deviceID]
+			d.spawn(func() { d.tryOpenDevice(devInterfaces, deviceID) })
 		}
 	}
 
 	// Verifica dispositivos desconectados
 	for id, info := range d.devices {
 		if !currentDeviceIDs[id] && info.Connected {
-			info.Connected = false
-
 			log.Printf("[HID Driver] Dispositivo desconectado: %s (ID: %d)", info.Name, info.ID)
 
-			if d.onDeviceDisconnected != nil {
-				go d.onDeviceDisconnected(DeviceEvent{
+			if handler := d.onDeviceDisconnected; handler != nil {
+				device := *info
+				device.Connected = false
+				event := DeviceEvent{
 					DeviceID:  id,
 					Connected: false,
-					Device:    info,
-				})
+					Device:    &device,
+				}
+				notify = append(notify, func() { handler(event) })
 			}
 
 			delete(d.devices, id)
+			delete(d.decoders, id)
+			delete(d.indicators, id)
+			if handle, ok := d.handles[id]; ok {
+				handle.Close()
+				delete(d.handles, id)
+			}
 		}
 	}
+}
+
+// selectInterface escolhe a interface de telefonia do dispositivo com base
+// nos descritores de relatório. Sem descritor, usa a primeira interface e o
+// decodificador legado (nil).
+func (d *HIDDriver) selectInterface(interfaces []hidtransport.DeviceInfo) (hidtransport.DeviceInfo, *TelephonyDecoder) {
+	var fallback *TelephonyDecoder
+	fallbackInfo := interfaces[0]
 
-	// Ativa modo simulação se nenhum dispositivo
-	if len(d.devices) == 0 && d.config.SimulationMode && !d.simulationMode {
-		d.simulationMode = true
-		log.Println("[HID Driver] Modo simulação ativado")
-		go d.simulationLoop()
+	for _, info := range interfaces {
+		raw, err := d.transport.ReportDescriptor(info)
+		if err != nil {
+			continue
+		}
+		decoder, err := ParseTelephonyDecoder(raw)
+		if err != nil {
+			log.Printf("[HID Driver] Descritor inválido em %s: %v", info.Path, err)
+			continue
+		}
+		if decoder == nil {
+			continue
+		}
+		if decoder.Descriptor().HasApplication(UsagePageTelephony) {
+			return info, decoder
+		}
+		if fallback == nil {
+			fallback, fallbackInfo = decoder, info
+		}
 	}
+	return fallbackInfo, fallback
 }
 
 // tryOpenDevice tenta abrir um dispositivo para leitura de eventos
-func (d *HIDDriver) tryOpenDevice(devInfo hid.DeviceInfo) {
-	device, err := devInfo.Open()
+func (d *HIDDriver) tryOpenDevice(interfaces []hidtransport.DeviceInfo, deviceID uint16) {
+	devInfo, decoder := d.selectInterface(interfaces)
+	if decoder == nil {
+		log.Printf("[HID Driver] Descritor HID indisponível para %s, usando mapeamento legado", devInfo.Product)
+	}
+
+	device, err := d.transport.Open(devInfo)
 	if err != nil {
 		log.Printf("[HID Driver] Não foi possível abrir dispositivo: %v", err)
 		return
 	}
 
 	d.mu.Lock()
-	d.currentDevice = device
+	if _, ok := d.devices[deviceID]; !ok || !d.running {
+		// Removido enquanto abríamos
+		d.mu.Unlock()
+		device.Close()
+		return
+	}
+	d.handles[deviceID] = device
+	if decoder != nil {
+		d.decoders[deviceID] = decoder
+		if writer := NewIndicatorWriter(decoder.Descriptor()); writer != nil {
+			d.indicators[deviceID] = writer
+		}
+	}
 	d.mu.Unlock()
 
 	// Inicia leitura de eventos HID
-	go d.readHIDEvents(device, uint16(devInfo.ProductID))
+	d.spawn(func() { d.readHIDEvents(device, deviceID) })
 }
 
 // readHIDEvents lê eventos HID do dispositivo
-func (d *HIDDriver) readHIDEvents(device *hid.Device, deviceID uint16) {
+func (d *HIDDriver) readHIDEvents(device hidtransport.Device, deviceID uint16) {
 	buf := make([]byte, 64)
 
 	for {
@@ -221,7 +440,16 @@ func (d *HIDDriver) readHIDEvents(device *hid.Device, deviceID uint16) {
 
 		n, err := device.Read(buf)
 		if err != nil {
-			log.Printf("[HID Driver] Erro ao ler HID: %v", err)
+			d.mu.Lock()
+			if d.running {
+				// Após o Stop o erro é o próprio fechamento do handle
+				log.Printf("[HID Driver] Erro ao ler HID: %v", err)
+			}
+			if d.handles[deviceID] == device {
+				delete(d.handles, deviceID)
+			}
+			d.mu.Unlock()
+			device.Close()
 			return
 		}
 
@@ -233,97 +461,25 @@ func (d *HIDDriver) readHIDEvents(device *hid.Device, deviceID uint16) {
 
 // processHIDData processa dados HID brutos e emite eventos de botão
 func (d *HIDDriver) processHIDData(deviceID uint16, data []byte) {
-	if len(data) < 2 {
-		return
-	}
-
-	// Protocolo HID Jabra simplificado
-	// Byte 0: Report ID
-	// Byte 1: Estado dos botões
-
-	reportID := data[0]
-	buttonState := data[1]
-
-	var buttonID ButtonID
-	var pressed bool
-
-	switch reportID {
-	case 0x01: // Telephony page
-		switch buttonState {
-		case 0x01: // Hook off
-			buttonID = ButtonOffHook
-			pressed = true
-		case 0x02: // Mute
-			buttonID = ButtonMute
-			pressed = true
-		case 0x04: // Hook on
-			buttonID = ButtonHookSwitch
-			pressed = true
-		case 0x08: // Flash
-			buttonID = ButtonFlash
-			pressed = true
-		default:
-			return
-		}
-	default:
-		return
-	}
-
 	d.mu.RLock()
+	decoder := d.decoders[deviceID]
 	handler := d.onButtonEvent
 	d.mu.RUnlock()
 
-	if handler != nil {
-		handler(ButtonEvent{
-			DeviceID: deviceID,
-			ButtonID: buttonID,
-			Pressed:  pressed,
-		})
+	var events []ButtonEvent
+	if decoder != nil {
+		events = decoder.Decode(data)
+	} else if event, ok := legacyButtonEvent(data); ok {
+		events = []ButtonEvent{event}
 	}
-}
-
-// simulationLoop simula eventos quando não há hardware
-func (d *HIDDriver) simulationLoop() {
-	ticker := time.NewTicker(30 * time.Second)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-d.stopCh:
-			return
-		case <-ticker.C:
-			d.mu.RLock()
-			simMode := d.simulationMode
-			d.mu.RUnlock()
-
-			if !simMode {
-				return
-			}
 
-			// Simula descarga de bateria
-			d.mu.Lock()
-			d.simulatedBattery -= 1
-			if d.simulatedBattery < 0 {
-				d.simulatedBattery = 100
-			}
-			battery := d.simulatedBattery
-			d.mu.Unlock()
-
-			if d.onBatteryUpdate != nil {
-				d.onBatteryUpdate(0, BatteryStatus{
-					Level:      battery,
-					IsCharging: false,
-					IsLow:      battery < 20,
-				})
-			}
-		}
+	if handler == nil {
+		return
+	}
+	for _, event := range events {
+		event.DeviceID = deviceID
+		handler(event)
 	}
-}
-
-// isDongle verifica se o dispositivo é um dongle baseado no nome
-func (d *HIDDriver) isDongle(name string) bool {
-	// Dongles comuns: Link 370, Link 380, etc.
-	return len(name) > 0 && (name[0] == 'L' || name[0] == 'l')
 }
 
 // GetDevices retorna lista de dispositivos conectados
@@ -336,16 +492,6 @@ func (d *HIDDriver) GetDevices() []DeviceInfo {
 		devices = append(devices, *dev)
 	}
 
-	// Adiciona dispositivo simulado se em modo simulação
-	if d.simulationMode {
-		devices = append(devices, DeviceInfo{
-			ID:          0,
-			Name:        "Jabra Simulado",
-			Connected:   true,
-			ConnectedAt: time.Now(),
-		})
-	}
-
 	return devices
 }
 
@@ -354,14 +500,6 @@ func (d *HIDDriver) GetDevice(deviceID uint16) (*DeviceInfo, error) {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	if d.simulationMode && deviceID == 0 {
-		return &DeviceInfo{
-			ID:        0,
-			Name:      "Jabra Simulado",
-			Connected: true,
-		}, nil
-	}
-
 	dev, ok := d.devices[deviceID]
 	if !ok {
 		return nil, fmt.Errorf("device %d not found", deviceID)
@@ -369,72 +507,139 @@ func (d *HIDDriver) GetDevice(deviceID uint16) (*DeviceInfo, error) {
 	return dev, nil
 }
 
-// GetBatteryStatus obtém status da bateria
+// GetBatteryStatus - HID genérico não expõe a bateria
 func (d *HIDDriver) GetBatteryStatus(deviceID uint16) (*BatteryStatus, error) {
+	if _, err := d.GetDevice(deviceID); err != nil {
+		return nil, err
+	}
+	return nil, notSupported(CapabilityBattery, deviceID)
+}
+
+// indicatorCapabilities associa cada indicador à capacidade que ele implementa
+var indicatorCapabilities = map[Indicator]Capability{
+	IndicatorMute:    CapabilityMute,
+	IndicatorRing:    CapabilityRinger,
+	IndicatorOffHook: CapabilityHook,
+	IndicatorBusy:    CapabilityBusylight,
+	IndicatorHold:    CapabilityHold,
+}
+
+// Capabilities deriva as capacidades dos LEDs presentes no descritor da
+// interface aberta; volume e bateria não existem no HID genérico
+func (d *HIDDriver) Capabilities(deviceID uint16) (Capabilities, error) {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
 
-	if d.simulationMode {
-		return &BatteryStatus{
-			Level:      d.simulatedBattery,
-			IsCharging: false,
-			IsLow:      d.simulatedBattery < 20,
-		}, nil
+	if _, ok := d.devices[deviceID]; !ok {
+		return Capabilities{}, fmt.Errorf("device %d not found", deviceID)
 	}
 
-	// HID genérico não suporta leitura de bateria diretamente
-	// Retorna valor padrão
-	return &BatteryStatus{
-		Level:      -1, // Desconhecido
-		IsCharging: false,
-		IsLow:      false,
-	}, nil
+	var caps Capabilities
+	writer := d.indicators[deviceID]
+	if writer == nil || d.handles[deviceID] == nil {
+		return caps, nil
+	}
+	caps.Mute = writer.Supports(IndicatorMute)
+	caps.Ringer = writer.Supports(IndicatorRing)
+	caps.Hook = writer.Supports(IndicatorOffHook)
+	caps.Busylight = writer.Supports(IndicatorBusy)
+	caps.Hold = writer.Supports(IndicatorHold)
+	return caps, nil
 }
 
-// SetMute - HID genérico não suporta controle de mute
-func (d *HIDDriver) SetMute(deviceID uint16, mute bool) error {
-	log.Printf("[HID Driver] SetMute não suportado (mute=%v)", mute)
+// setIndicator envia o relatório de saída que liga/desliga um indicador.
+// Dispositivos sem o indicador no descritor retornam ErrNotSupported.
+func (d *HIDDriver) setIndicator(deviceID uint16, indicator Indicator, on bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.devices[deviceID]; !ok {
+		return fmt.Errorf("device %d not found", deviceID)
+	}
+
+	writer := d.indicators[deviceID]
+	handle := d.handles[deviceID]
+	if writer == nil || handle == nil || !writer.Supports(indicator) {
+		return notSupported(indicatorCapabilities[indicator], deviceID)
+	}
+
+	report, err := writer.Report(indicator, on)
+	if err != nil {
+		return err
+	}
+	if _, err := handle.Write(report); err != nil {
+		return fmt.Errorf("write %s output report: %w", indicator, err)
+	}
 	return nil
 }
 
-// GetMute - HID genérico não suporta leitura de mute
+// SetMute acende/apaga o LED de mute
+func (d *HIDDriver) SetMute(deviceID uint16, mute bool) error {
+	return d.setIndicator(deviceID, IndicatorMute, mute)
+}
+
+// GetMute retorna o último estado gravado no LED de mute
 func (d *HIDDriver) GetMute(deviceID uint16) (bool, error) {
-	return false, nil
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if _, ok := d.devices[deviceID]; !ok {
+		return false, fmt.Errorf("device %d not found", deviceID)
+	}
+	if writer := d.indicators[deviceID]; writer != nil && writer.Supports(IndicatorMute) {
+		return writer.State(IndicatorMute), nil
+	}
+	return false, notSupported(CapabilityMute, deviceID)
 }
 
-// SetRinger - HID genérico não suporta controle de ringer
+// SetRinger liga/desliga o toque (LED Ring ou Telephony Ringer)
 func (d *HIDDriver) SetRinger(deviceID uint16, ring bool) error {
-	log.Printf("[HID Driver] SetRinger não suportado (ring=%v)", ring)
-	return nil
+	return d.setIndicator(deviceID, IndicatorRing, ring)
 }
 
-// SetHookState - HID genérico não suporta controle de hook
+// SetHookState define o LED Off-Hook (atendendo)
 func (d *HIDDriver) SetHookState(deviceID uint16, offHook bool) error {
-	log.Printf("[HID Driver] SetHookState não suportado (offHook=%v)", offHook)
-	return nil
+	return d.setIndicator(deviceID, IndicatorOffHook, offHook)
 }
 
-// SetBusylight - HID genérico não suporta controle de LED
+// SetBusylight define o LED de ocupado
 func (d *HIDDriver) SetBusylight(deviceID uint16, on bool) error {
-	log.Printf("[HID Driver] SetBusylight não suportado (on=%v)", on)
-	return nil
+	return d.setIndicator(deviceID, IndicatorBusy, on)
 }
 
-// SetHold - HID genérico não suporta controle de hold
+// SetHold define o LED de chamada em espera
 func (d *HIDDriver) SetHold(deviceID uint16, hold bool) error {
-	log.Printf("[HID Driver] SetHold não suportado (hold=%v)", hold)
-	return nil
+	return d.setIndicator(deviceID, IndicatorHold, hold)
 }
 
 // SetVolume - HID genérico não suporta controle de volume
 func (d *HIDDriver) SetVolume(deviceID uint16, volume int) error {
-	log.Printf("[HID Driver] SetVolume não suportado (volume=%d)", volume)
-	return nil
+	return notSupported(CapabilityVolume, deviceID)
 }
 
 // GetVolume - HID genérico não suporta leitura de volume
 func (d *HIDDriver) GetVolume(deviceID uint16) (int, error) {
-	return -1, nil
+	return 0, notSupported(CapabilityVolume, deviceID)
+}
+
+// GetWearState - o sensor de uso não faz parte das páginas HID padrão
+func (d *HIDDriver) GetWearState(deviceID uint16) (bool, error) {
+	return false, notSupported(CapabilityWear, deviceID)
+}
+
+// ListSettings - o HID genérico não expõe configurações do dispositivo
+func (d *HIDDriver) ListSettings(deviceID uint16) ([]Setting, error) {
+	return nil, notSupported(CapabilitySettings, deviceID)
+}
+
+// GetSetting - o HID genérico não expõe configurações do dispositivo
+func (d *HIDDriver) GetSetting(deviceID uint16, key string) (Setting, error) {
+	return Setting{}, notSupported(CapabilitySettings, deviceID)
+}
+
+// SetSetting - o HID genérico não expõe configurações do dispositivo
+func (d *HIDDriver) SetSetting(deviceID uint16, key string, value any) error {
+	return notSupported(CapabilitySettings, deviceID)
 }
 
 // OnDeviceConnected registra callback
@@ -464,3 +669,6 @@ func (d *HIDDriver) OnBatteryUpdate(handler func(deviceID uint16, status Battery
 	defer d.mu.Unlock()
 	d.onBatteryUpdate = handler
 }
+
+// OnWearEvent - o HID genérico não reporta o sensor de uso
+func (d *HIDDriver) OnWearEvent(handler func(event WearEvent)) {}
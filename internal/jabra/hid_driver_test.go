@@ -0,0 +1,308 @@
+//go:build !windows
+
+package jabra
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/hidtransport"
+)
+
+// newFakeHIDDriver cria um driver HID sobre o transporte fake com polling
+// rápido (usado quando o fake não oferece hotplug)
+func newFakeHIDDriver(t *testing.T, fake *hidtransport.Fake) (*HIDDriver, chan DeviceEvent, chan ButtonEvent) {
+	t.Helper()
+
+	config := DefaultConfig()
+	config.PollInterval = 10 * time.Millisecond
+	return newFakeHIDDriverWithConfig(t, fake, config)
+}
+
+// newFakeHIDDriverWithConfig cria e inicia o driver HID sobre o transporte fake
+func newFakeHIDDriverWithConfig(t *testing.T, fake *hidtransport.Fake, config DriverConfig) (*HIDDriver, chan DeviceEvent, chan ButtonEvent) {
+	t.Helper()
+
+	driver, err := NewHIDDriverWithTransport(config, fake)
+	if err != nil {
+		t.Fatalf("NewHIDDriverWithTransport() erro inesperado: %v", err)
+	}
+
+	devices := make(chan DeviceEvent, 8)
+	buttons := make(chan ButtonEvent, 8)
+	driver.OnDeviceConnected(func(event DeviceEvent) { devices <- event })
+	driver.OnDeviceDisconnected(func(event DeviceEvent) { devices <- event })
+	driver.OnButtonEvent(func(event ButtonEvent) { buttons <- event })
+
+	if err := driver.Start(); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	t.Cleanup(func() { driver.Stop() })
+	return driver, devices, buttons
+}
+
+func TestHIDDriverFakeDevice(t *testing.T) {
+	fake := hidtransport.NewFake()
+	// Duas interfaces do mesmo headset: apenas a segunda é de telefonia
+	other := fake.Add(hidtransport.DeviceInfo{Path: "1:2:0", VendorID: JabraVendorID, ProductID: 0x0123, Serial: "ABC", Product: "Jabra Engage 55"}, nil)
+	dev := fake.Add(hidtransport.DeviceInfo{Path: "1:2:3", VendorID: JabraVendorID, ProductID: 0x0123, Serial: "ABC", Product: "Jabra Engage 55", Interface: 3}, testHeadsetDescriptor)
+
+	driver, devices, buttons := newFakeHIDDriver(t, fake)
+
+	connected := receive(t, devices)
+	if !connected.Connected || connected.Device.SerialNumber != "ABC" {
+		t.Fatalf("evento de conexão inesperado: %+v", connected)
+	}
+	if got := len(driver.GetDevices()); got != 1 {
+		t.Fatalf("esperado 1 dispositivo (interfaces agrupadas), obtido %d", got)
+	}
+	waitFor(t, "abertura da interface de telefonia", dev.IsOpen)
+
+	dev.Push([]byte{0x02, 0x01})
+	event := receive(t, buttons)
+	if event.DeviceID != connected.DeviceID || event.ButtonID != ButtonHookSwitch || !event.Pressed || !event.Absolute {
+		t.Errorf("evento de botão inesperado: %+v", event)
+	}
+
+	if err := driver.SetMute(connected.DeviceID, true); err != nil {
+		t.Fatalf("SetMute() erro inesperado: %v", err)
+	}
+	written := dev.Written()
+	if len(written) != 1 || !bytes.Equal(written[0], []byte{0x02, 0x02}) {
+		t.Errorf("relatórios de saída = %x, want [0202]", written)
+	}
+	if mute, _ := driver.GetMute(connected.DeviceID); !mute {
+		t.Error("GetMute() deveria refletir o LED gravado")
+	}
+	caps, err := driver.Capabilities(connected.DeviceID)
+	if err != nil || !caps.Mute || caps.Volume || caps.Battery {
+		t.Errorf("Capabilities() = %+v, %v; want mute sem volume/bateria", caps, err)
+	}
+	if err := driver.SetVolume(connected.DeviceID, 50); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("SetVolume() = %v, want ErrNotSupported", err)
+	}
+
+	other.Disconnect()
+	dev.Disconnect()
+	disconnected := receive(t, devices)
+	if disconnected.Connected || disconnected.DeviceID != connected.DeviceID {
+		t.Errorf("evento de desconexão inesperado: %+v", disconnected)
+	}
+	waitFor(t, "remoção do dispositivo", func() bool { return len(driver.GetDevices()) == 0 })
+	if err := driver.SetMute(connected.DeviceID, false); err == nil {
+		t.Error("SetMute() deveria falhar após a desconexão")
+	}
+}
+
+func TestHIDDriverLegacyFallback(t *testing.T) {
+	fake := hidtransport.NewFake()
+	dev := fake.Add(hidtransport.DeviceInfo{Path: "1:5:0", VendorID: JabraVendorID, ProductID: 0x0456, Product: "Jabra Link 380"}, nil)
+
+	driver, devices, buttons := newFakeHIDDriver(t, fake)
+	connected := receive(t, devices)
+	waitFor(t, "abertura do dispositivo", dev.IsOpen)
+
+	dev.Push([]byte{0x01, 0x02})
+	if !connected.Device.IsDongle || connected.Device.FormFactor != FormFactorDongle {
+		t.Errorf("Link 380 deveria ser reconhecido como dongle: %+v", connected.Device)
+	}
+	if event := receive(t, buttons); event.ButtonID != ButtonMute || event.DeviceID != connected.DeviceID {
+		t.Errorf("evento legado inesperado: %+v", event)
+	}
+
+	// Sem descritor não há LEDs: o comando não é suportado e nada é enviado
+	if err := driver.SetRinger(connected.DeviceID, true); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("SetRinger() = %v, want ErrNotSupported", err)
+	}
+	if caps, _ := driver.Capabilities(connected.DeviceID); caps != (Capabilities{}) {
+		t.Errorf("Capabilities() = %+v, want nenhuma", caps)
+	}
+	if _, err := driver.ListSettings(connected.DeviceID); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ListSettings() = %v, want ErrNotSupported", err)
+	}
+	if written := dev.Written(); len(written) != 0 {
+		t.Errorf("nenhum relatório deveria ser enviado, obtido %x", written)
+	}
+}
+
+func TestHIDDriverStableIDs(t *testing.T) {
+	fake := hidtransport.NewFake()
+	info := hidtransport.DeviceInfo{Path: "1:7:0", VendorID: JabraVendorID, ProductID: 0x0789, Serial: "XYZ"}
+	dev := fake.Add(info, testHeadsetDescriptor)
+
+	_, devices, _ := newFakeHIDDriver(t, fake)
+	first := receive(t, devices)
+
+	dev.Disconnect()
+	receive(t, devices)
+
+	fake.Add(info, testHeadsetDescriptor)
+	again := receive(t, devices)
+	if !again.Connected || again.DeviceID != first.DeviceID {
+		t.Errorf("reconexão deveria manter o ID %d, obtido %+v", first.DeviceID, again)
+	}
+}
+
+func TestHIDDriverQuickReplug(t *testing.T) {
+	fake := hidtransport.NewFake()
+	fake.WatchErr = hidtransport.ErrUnsupported
+
+	config := DefaultConfig()
+	config.PollInterval = time.Millisecond
+	driver, err := NewHIDDriverWithTransport(config, fake)
+	if err != nil {
+		t.Fatalf("NewHIDDriverWithTransport() erro inesperado: %v", err)
+	}
+
+	// Conexões e desconexões precisam chegar em ordem, alternadas
+	var mu sync.Mutex
+	online := false
+	var outOfOrder []string
+	track := func(event DeviceEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if event.Connected == online || event.Device.Connected != event.Connected {
+			outOfOrder = append(outOfOrder, fmt.Sprintf("%+v", event))
+		}
+		online = event.Connected
+	}
+	driver.OnDeviceConnected(track)
+	driver.OnDeviceDisconnected(track)
+	if err := driver.Start(); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	t.Cleanup(func() { driver.Stop() })
+
+	info := hidtransport.DeviceInfo{Path: "1:12:0", VendorID: JabraVendorID, ProductID: 0x0456, Serial: "REPLUG"}
+	dev := fake.Add(info, testHeadsetDescriptor)
+	for i := 0; i < 50; i++ {
+		dev.Disconnect()
+		time.Sleep(time.Duration(i%3) * time.Millisecond)
+		dev = fake.Add(info, testHeadsetDescriptor)
+		time.Sleep(time.Duration(i%2) * time.Millisecond)
+	}
+
+	waitFor(t, "dispositivo online", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return online
+	})
+	time.Sleep(20 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !online || len(outOfOrder) > 0 {
+		t.Errorf("online = %v, eventos fora de ordem: %v", online, outOfOrder)
+	}
+	if devices := driver.GetDevices(); len(devices) != 1 || !devices[0].Connected {
+		t.Errorf("GetDevices() = %+v, want dispositivo conectado", devices)
+	}
+}
+
+func TestHIDDriverHotplug(t *testing.T) {
+	fake := hidtransport.NewFake()
+
+	// Sem polling efetivo: conexões só podem vir dos eventos de hotplug
+	config := DefaultConfig()
+	config.PollInterval = time.Hour
+	_, devices, _ := newFakeHIDDriverWithConfig(t, fake, config)
+	waitFor(t, "watcher de hotplug", func() bool { return fake.Watchers() == 1 })
+
+	info := hidtransport.DeviceInfo{Path: "1:9:0", VendorID: JabraVendorID, ProductID: 0x0456, Serial: "HOT"}
+	for i := 0; i < 3; i++ {
+		start := time.Now()
+		dev := fake.Add(info, testHeadsetDescriptor)
+		if event := receive(t, devices); !event.Connected {
+			t.Fatalf("esperado evento de conexão, obtido %+v", event)
+		}
+		dev.Disconnect()
+		if event := receive(t, devices); event.Connected {
+			t.Fatalf("esperado evento de desconexão, obtido %+v", event)
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("ciclo de conexão/desconexão levou %v", elapsed)
+		}
+	}
+
+	// Eventos de outros fabricantes não disparam enumeração nem callbacks
+	fake.Add(hidtransport.DeviceInfo{Path: "1:10:0", VendorID: 0x046d, ProductID: 0x0001}, nil)
+	select {
+	case event := <-devices:
+		t.Errorf("evento inesperado para outro fabricante: %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestHIDDriverPollingFallback(t *testing.T) {
+	fake := hidtransport.NewFake()
+	fake.WatchErr = hidtransport.ErrUnsupported
+
+	driver, devices, _ := newFakeHIDDriver(t, fake)
+	fake.Add(hidtransport.DeviceInfo{Path: "1:11:0", VendorID: JabraVendorID, ProductID: 0x0456, Serial: "POLL"}, testHeadsetDescriptor)
+	if event := receive(t, devices); !event.Connected {
+		t.Fatalf("esperado evento de conexão por polling, obtido %+v", event)
+	}
+
+	driver.Stop()
+	if fake.Watchers() != 0 {
+		t.Error("nenhum watcher deveria ficar aberto")
+	}
+}
+
+func TestHIDDriverCaptureReplay(t *testing.T) {
+	fake := hidtransport.NewFake()
+	dev := fake.Add(hidtransport.DeviceInfo{Path: "1:9:3", VendorID: JabraVendorID, ProductID: 0x0123, Serial: "CAP", Product: "Jabra Engage 55"}, testHeadsetDescriptor)
+
+	capture := filepath.Join(t.TempDir(), "capture.jsonl")
+	config := DefaultConfig()
+	config.PollInterval = 10 * time.Millisecond
+	config.CaptureFile = capture
+	driver, err := NewHIDDriverWithTransport(config, fake)
+	if err != nil {
+		t.Fatalf("NewHIDDriverWithTransport() erro inesperado: %v", err)
+	}
+	buttons := make(chan ButtonEvent, 8)
+	driver.OnButtonEvent(func(event ButtonEvent) { buttons <- event })
+	driver.Start()
+
+	waitFor(t, "abertura do dispositivo", dev.IsOpen)
+	dev.Push([]byte{0x02, 0x03})
+	recorded := []ButtonEvent{receive(t, buttons), receive(t, buttons)}
+	driver.Stop()
+
+	replay, err := OpenReplayDriver(capture, 0)
+	if err != nil {
+		t.Fatalf("OpenReplayDriver() erro inesperado: %v", err)
+	}
+	var replayed []ButtonEvent
+	replay.OnButtonEvent(func(event ButtonEvent) { replayed = append(replayed, event) })
+	replay.Start()
+	<-replay.Done()
+
+	if len(replayed) != len(recorded) {
+		t.Fatalf("reprodução = %+v, gravação = %+v", replayed, recorded)
+	}
+	for i := range recorded {
+		if replayed[i].ButtonID != recorded[i].ButtonID || replayed[i].Pressed != recorded[i].Pressed {
+			t.Errorf("evento %d reproduzido = %+v, gravado %+v", i, replayed[i], recorded[i])
+		}
+	}
+}
+
+func TestHIDDriverStopWaitsForGoroutines(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	fake := hidtransport.NewFake()
+	fake.Add(hidtransport.DeviceInfo{Path: "1:2:3", VendorID: JabraVendorID, ProductID: 0x0123, Serial: "STOP", Product: "Jabra Engage 55"}, testHeadsetDescriptor)
+	driver, devices, _ := newFakeHIDDriver(t, fake)
+	receive(t, devices)
+
+	driver.Stop()
+	settleGoroutines(t, before)
+}
@@ -0,0 +1,136 @@
+package jabra
+
+import (
+	"fmt"
+
+	"github.com/aiknow/acc_jabra_agent/internal/hidreport"
+)
+
+// Usos da página LED (HID Usage Tables, seção 11)
+const (
+	UsageLEDMute    uint16 = 0x09
+	UsageLEDOffHook uint16 = 0x17
+	UsageLEDRing    uint16 = 0x18
+	UsageLEDHold    uint16 = 0x20
+	UsageLEDBusy    uint16 = 0x2C
+)
+
+// Indicator identifica um indicador de saída do headset (LED ou ringer)
+type Indicator int
+
+const (
+	IndicatorMute Indicator = iota
+	IndicatorOffHook
+	IndicatorRing
+	IndicatorHold
+	IndicatorBusy
+)
+
+// String retorna o nome do indicador para logging
+func (i Indicator) String() string {
+	switch i {
+	case IndicatorMute:
+		return "Mute"
+	case IndicatorOffHook:
+		return "OffHook"
+	case IndicatorRing:
+		return "Ring"
+	case IndicatorHold:
+		return "Hold"
+	case IndicatorBusy:
+		return "Busy"
+	}
+	return "Unknown"
+}
+
+// indicatorUsages lista, em ordem de preferência, os usos de saída que
+// controlam cada indicador. Headsets sem LED Ring costumam expor o
+// Ringer da página Telephony.
+var indicatorUsages = map[Indicator][]hidreport.Usage{
+	IndicatorMute:    {hidreport.NewUsage(UsagePageLED, UsageLEDMute)},
+	IndicatorOffHook: {hidreport.NewUsage(UsagePageLED, UsageLEDOffHook)},
+	IndicatorRing: {
+		hidreport.NewUsage(UsagePageLED, UsageLEDRing),
+		hidreport.NewUsage(UsagePageTelephony, UsageRinger),
+	},
+	IndicatorHold: {hidreport.NewUsage(UsagePageLED, UsageLEDHold)},
+	IndicatorBusy: {hidreport.NewUsage(UsagePageLED, UsageLEDBusy)},
+}
+
+// IndicatorWriter monta relatórios de saída para os indicadores declarados
+// no descritor. Mantém o último conteúdo de cada relatório para que ligar um
+// LED não apague os demais campos do mesmo relatório.
+type IndicatorWriter struct {
+	desc    *hidreport.Descriptor
+	fields  map[Indicator]hidreport.Field
+	reports map[uint8][]byte
+	state   map[Indicator]bool
+}
+
+// NewIndicatorWriter cria o escritor de indicadores para o descritor.
+// Retorna nil se o descritor não declara nenhum indicador conhecido.
+func NewIndicatorWriter(desc *hidreport.Descriptor) *IndicatorWriter {
+	w := &IndicatorWriter{
+		desc:    desc,
+		fields:  make(map[Indicator]hidreport.Field),
+		reports: make(map[uint8][]byte),
+		state:   make(map[Indicator]bool),
+	}
+
+	for indicator, usages := range indicatorUsages {
+		for _, usage := range usages {
+			if f, ok := desc.Find(hidreport.Output, usage); ok {
+				w.fields[indicator] = f
+				break
+			}
+		}
+	}
+
+	if len(w.fields) == 0 {
+		return nil
+	}
+	return w
+}
+
+// Supports retorna true se o dispositivo declara o indicador
+func (w *IndicatorWriter) Supports(indicator Indicator) bool {
+	_, ok := w.fields[indicator]
+	return ok
+}
+
+// State retorna o último estado gravado do indicador
+func (w *IndicatorWriter) State(indicator Indicator) bool {
+	return w.state[indicator]
+}
+
+// Report atualiza o indicador e retorna o relatório de saída completo a ser
+// enviado ao dispositivo. O primeiro byte é sempre o report ID (0 quando o
+// descritor não usa IDs), conforme esperado pelo hidapi.
+func (w *IndicatorWriter) Report(indicator Indicator, on bool) ([]byte, error) {
+	f, ok := w.fields[indicator]
+	if !ok {
+		return nil, fmt.Errorf("indicator %s not declared by device", indicator)
+	}
+
+	payload, ok := w.reports[f.ReportID]
+	if !ok {
+		payload = make([]byte, w.desc.ReportLength(hidreport.Output, f.ReportID))
+		w.reports[f.ReportID] = payload
+	}
+
+	value := f.LogicalMin
+	if on {
+		value = f.LogicalMax
+		if value <= f.LogicalMin {
+			value = f.LogicalMin + 1
+		}
+	}
+	if !f.SetValue(payload, 0, value) {
+		return nil, fmt.Errorf("output report %d too short for indicator %s", f.ReportID, indicator)
+	}
+	w.state[indicator] = on
+
+	report := make([]byte, 0, len(payload)+1)
+	report = append(report, f.ReportID)
+	return append(report, payload...), nil
+}
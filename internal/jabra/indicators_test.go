@@ -0,0 +1,98 @@
+package jabra
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aiknow/acc_jabra_agent/internal/hidreport"
+)
+
+func TestIndicatorWriter(t *testing.T) {
+	desc, err := hidreport.Parse(testHeadsetDescriptor)
+	if err != nil {
+		t.Fatalf("Parse() erro inesperado: %v", err)
+	}
+	writer := NewIndicatorWriter(desc)
+	if writer == nil {
+		t.Fatal("NewIndicatorWriter() = nil")
+	}
+
+	// LEDs no report 2: Off-Hook bit 0, Mute bit 1, Ring bit 2, Hold bit 3.
+	// Cada passo preserva os demais LEDs do relatório.
+	steps := []struct {
+		indicator Indicator
+		on        bool
+		want      []byte
+	}{
+		{IndicatorMute, true, []byte{0x02, 0x02}},
+		{IndicatorRing, true, []byte{0x02, 0x06}},
+		{IndicatorOffHook, true, []byte{0x02, 0x07}},
+		{IndicatorRing, false, []byte{0x02, 0x03}},
+		{IndicatorHold, true, []byte{0x02, 0x0B}},
+		{IndicatorMute, false, []byte{0x02, 0x09}},
+	}
+	for _, step := range steps {
+		got, err := writer.Report(step.indicator, step.on)
+		if err != nil {
+			t.Fatalf("Report(%s, %v) erro inesperado: %v", step.indicator, step.on, err)
+		}
+		if !bytes.Equal(got, step.want) {
+			t.Errorf("Report(%s, %v) = %x, want %x", step.indicator, step.on, got, step.want)
+		}
+	}
+
+	if !writer.State(IndicatorHold) || writer.State(IndicatorMute) {
+		t.Error("State() não reflete os últimos valores gravados")
+	}
+	if writer.Supports(IndicatorBusy) {
+		t.Error("Busy não é declarado no descritor")
+	}
+	if _, err := writer.Report(IndicatorBusy, true); err == nil {
+		t.Error("Report(Busy) deveria falhar sem o LED no descritor")
+	}
+}
+
+func TestIndicatorWriterRingerFallback(t *testing.T) {
+	// Sem report IDs: Telephony Ringer (saída) e LED Busy
+	raw := []byte{
+		0x05, 0x0B, 0x09, 0x05, 0xA1, 0x01,
+		0x15, 0x00, 0x25, 0x01, 0x75, 0x01, 0x95, 0x01,
+		0x09, 0x9E, 0x91, 0x22, // Ringer
+		0x05, 0x08, 0x09, 0x2C, 0x91, 0x22, // LED Busy
+		0x95, 0x06, 0x91, 0x01,
+		0xC0,
+	}
+	desc, err := hidreport.Parse(raw)
+	if err != nil {
+		t.Fatalf("Parse() erro inesperado: %v", err)
+	}
+	writer := NewIndicatorWriter(desc)
+	if writer == nil {
+		t.Fatal("NewIndicatorWriter() = nil")
+	}
+
+	tests := []struct {
+		indicator Indicator
+		want      []byte
+	}{
+		{IndicatorRing, []byte{0x00, 0x01}},
+		{IndicatorBusy, []byte{0x00, 0x03}},
+	}
+	for _, tt := range tests {
+		got, err := writer.Report(tt.indicator, true)
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("Report(%s) = %x, %v; want %x", tt.indicator, got, err, tt.want)
+		}
+	}
+}
+
+func TestIndicatorWriterWithoutOutputs(t *testing.T) {
+	desc, err := hidreport.Parse([]byte{0x05, 0x0B, 0x09, 0x05, 0xA1, 0x01,
+		0x15, 0x00, 0x25, 0x01, 0x75, 0x01, 0x95, 0x01, 0x09, 0x20, 0x81, 0x02, 0xC0})
+	if err != nil {
+		t.Fatalf("Parse() erro inesperado: %v", err)
+	}
+	if NewIndicatorWriter(desc) != nil {
+		t.Error("NewIndicatorWriter() deveria ser nil sem saídas conhecidas")
+	}
+}
@@ -0,0 +1,73 @@
+package jabra
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+)
+
+// settleGoroutines espera o número de goroutines voltar a no máximo want
+func settleGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			buf := make([]byte, 1<<16)
+			n := runtime.Stack(buf, true)
+			t.Fatalf("goroutines vazadas: %d > %d\n%s", runtime.NumGoroutine(), want, buf[:n])
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMonitorLifecycle(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "lifecycle_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 3; i++ {
+		sim := NewSimulationDriver(nil)
+		m := NewMonitor(sim, "", store)
+		if err := m.Start(context.Background()); err != nil {
+			t.Fatalf("Start() erro inesperado: %v", err)
+		}
+		if err := m.Start(context.Background()); err == nil {
+			t.Error("segundo Start() deveria falhar")
+		}
+		if err := sim.Execute(SimCommand{Action: SimAttach, Device: 1}); err != nil {
+			t.Fatalf("attach: %v", err)
+		}
+		if err := m.Stop(); err != nil {
+			t.Errorf("Stop() erro inesperado: %v", err)
+		}
+		if sim.IsRunning() {
+			t.Error("Stop() deveria parar o driver")
+		}
+		if err := m.Stop(); err != nil {
+			t.Errorf("Stop() repetido deveria ser no-op: %v", err)
+		}
+	}
+	settleGoroutines(t, before)
+}
+
+func TestMonitorStopOnContextCancel(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m := NewMonitor(nil, "", nil)
+	if err := m.Start(ctx); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	cancel()
+	settleGoroutines(t, before)
+
+	if err := m.Stop(); err != nil {
+		t.Errorf("Stop() após cancelamento: %v", err)
+	}
+}
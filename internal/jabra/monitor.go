@@ -1,32 +1,94 @@
 package jabra
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"slices"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/aiknow/acc_jabra_agent/internal/battery"
 	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
 	"github.com/aiknow/acc_jabra_agent/internal/models"
+	"github.com/aiknow/acc_jabra_agent/internal/supervisor"
 	"github.com/gen2brain/beeep"
-	"github.com/karalabe/hid"
 )
 
 const JabraVID = 0x0b0e
 
+// batteryHistoryWindow é o período do histórico usado para aprender as taxas
+const batteryHistoryWindow = 14 * 24 * time.Hour
+
+// unknownSerial identifica na telemetria os dispositivos que não informam serial
+const unknownSerial = "USB-HID-DEVICE"
+
+// DefaultCallEndedDelay é o tempo em ended antes de a chamada voltar a idle
+const DefaultCallEndedDelay = 5 * time.Second
+
+// deviceState é o estado de telemetria de um dispositivo conhecido
+type deviceState struct {
+	payload     models.TelemetryPayload
+	online      bool
+	connectedAt time.Time
+	call        *CallStateMachine
+
+	// Volta a chamada de ended para idle após o callEndedDelay
+	endedTimer *time.Timer
+}
+
 type Monitor struct {
-	currentState models.TelemetryPayload
-	lastUpdate   time.Time
-	mu           sync.RWMutex
-	store        *db.Store
+	// placeholder é reportado como telemetria enquanto nenhum dispositivo foi visto
+	placeholder models.TelemetryPayload
+	lastUpdate  time.Time
+	mu          sync.RWMutex
+	store       *db.Store
+	driver      Driver
+
+	// Estado por dispositivo, indexado pelo ID do driver
+	devices map[uint16]*deviceState
+
+	// Último dispositivo desconectado (reportado quando nenhum está online)
+	lastDeviceID uint16
+	hasLast      bool
+
+	// Ouvintes de eventos de botão (ex.: executor do keymap)
+	buttonHandlers []func(event ButtonEvent)
+
+	// Barramento onde o monitor publica conexões, botões, bateria e
+	// chamadas (nil não publica)
+	bus *events.Bus
+
+	// Taxas de bateria aprendidas por serial
+	batteryModels map[string]battery.Model
+
+	// Tempo em ended antes de voltar a idle (SetCallEndedDelay)
+	callEndedDelay time.Duration
+
+	// Ciclo de vida (Start/Stop): workers supervisionados e cancelamento
+	lifecycleMu sync.Mutex
+	cancel      context.CancelFunc
+	workers     *supervisor.Supervisor
 }
 
-func NewMonitor(serial string, store *db.Store) *Monitor {
+// NewMonitor cria o monitor de telemetria sobre o driver informado e
+// registra seus callbacks no driver. Nada é iniciado até Start; driver nil
+// mantém apenas o estado (útil em testes).
+func NewMonitor(driver Driver, serial string, store *db.Store) *Monitor {
 	m := &Monitor{
-		store: store,
-		currentState: models.TelemetryPayload{
+		store:   store,
+		driver:  driver,
+		devices: make(map[uint16]*deviceState),
+
+		batteryModels:  make(map[string]battery.Model),
+		callEndedDelay: DefaultCallEndedDelay,
+		placeholder: models.TelemetryPayload{
 			Module: "jabra_telemetry",
-			Device: "Engage 55 Mono SE",
+			Device: "Nenhum dispositivo",
 			Serial: serial,
 			State: models.DeviceState{
 				Battery:       models.BatteryInfo{Level: 0, Status: "unknown"},
@@ -34,166 +96,926 @@ func NewMonitor(serial string, store *db.Store) *Monitor {
 				SessionUptime: "00h 00m",
 				CustomID:      "Aguardando...",
 				CustomColor:   "#9e9e9e",
+				Call:          models.CallInfo{State: string(CallIdle), Since: time.Now()},
 			},
 			Events: models.DeviceEvents{LastPowerOn: time.Now()},
 		},
 		lastUpdate: time.Now(),
 	}
 
-	go m.startHIDScanner()
-	go m.batteryLogger()
-	go m.uptimeUpdater()
+	if driver != nil {
+		driver.OnDeviceConnected(m.handleDeviceConnected)
+		driver.OnDeviceDisconnected(m.handleDeviceDisconnected)
+		driver.OnButtonEvent(m.handleButtonEvent)
+		driver.OnBatteryUpdate(m.handleBatteryUpdate)
+		driver.OnWearEvent(m.handleWearEvent)
+	}
 	return m
 }
 
-func (m *Monitor) uptimeUpdater() {
+// Start inicia o driver e os workers de segundo plano (log de bateria e
+// uptime), supervisionados até ctx ser cancelado ou Stop ser chamado.
+// Falha ao iniciar o driver é registrada e o monitor segue sem hardware.
+func (m *Monitor) Start(ctx context.Context) error {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	if m.cancel != nil {
+		return errors.New("monitor already started")
+	}
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.workers = supervisor.New("Jabra")
+
+	if m.driver != nil {
+		if err := m.driver.Start(); err != nil {
+			log.Printf("[Jabra] Erro ao iniciar driver: %v", err)
+		}
+	}
+
+	m.workers.Go(ctx, "battery_logger", m.batteryLogger)
+	m.workers.Go(ctx, "uptime", m.uptimeUpdater)
+	return nil
+}
+
+// Stop cancela os workers, aguarda seu término e para o driver
+func (m *Monitor) Stop() error {
+	m.lifecycleMu.Lock()
+	defer m.lifecycleMu.Unlock()
+
+	if m.cancel == nil {
+		return nil
+	}
+	m.cancel()
+	m.workers.Wait()
+	m.cancel = nil
+
+	if m.driver != nil {
+		return m.driver.Stop()
+	}
+	return nil
+}
+
+// Driver retorna o driver de hardware usado pelo monitor
+func (m *Monitor) Driver() Driver {
+	return m.driver
+}
+
+// SetEventBus define o barramento onde o monitor publica seus eventos e
+// assina os eventos do socket (ligacao_*) para a máquina de chamada. Deve
+// ser chamado antes de Start.
+func (m *Monitor) SetEventBus(bus *events.Bus) {
+	m.bus = bus
+	bus.Handle(events.Options{Name: "monitor", Kinds: []events.Kind{events.KindSocket}}, m.handleSocketEvent)
+}
+
+// SetCallEndedDelay define por quanto tempo uma chamada encerrada fica em
+// ended antes de voltar a idle. Deve ser chamado antes de Start.
+func (m *Monitor) SetCallEndedDelay(delay time.Duration) {
+	m.callEndedDelay = delay
+}
+
+// Events retorna o barramento de eventos do monitor (nil se não definido)
+func (m *Monitor) Events() *events.Bus {
+	return m.bus
+}
+
+// publish publica um evento no barramento, se houver
+func (m *Monitor) publish(event events.Event) {
+	if m.bus != nil {
+		m.bus.Publish(event)
+	}
+}
+
+// handleSocketEvent aplica os eventos de chamada do servidor ACC ao
+// dispositivo principal
+func (m *Monitor) handleSocketEvent(event events.Event) {
+	socket, ok := event.(events.SocketEvent)
+	if !ok {
+		return
+	}
+	source := "socket:" + socket.Name
+	switch socket.Name {
+	case "ligacao_atendida":
+		if socket.Own {
+			m.HandleCallEvent(CallEventAnswer, source)
+		} else {
+			m.HandleCallEvent(CallEventAnsweredElsewhere, source)
+		}
+	case "ligacao_interna":
+		// Ligação solicitada pelo próprio ramal não toca aqui
+		if !socket.Own {
+			m.HandleCallEvent(CallEventIncoming, source)
+		}
+	}
+}
+
+func (m *Monitor) uptimeUpdater(ctx context.Context) error {
 	ticker := time.NewTicker(30 * time.Second)
-	for range ticker.C {
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
 		m.mu.Lock()
-		if m.currentState.State.Connection == "online" {
-			duration := time.Since(m.currentState.Events.LastPowerOn)
+		for _, dev := range m.devices {
+			if !dev.online {
+				continue
+			}
+			duration := time.Since(dev.payload.Events.LastPowerOn)
 			hours := int(duration.Hours())
 			minutes := int(duration.Minutes()) % 60
-			m.currentState.State.SessionUptime = fmt.Sprintf("%02dh %02dm", hours, minutes)
+			dev.payload.State.SessionUptime = fmt.Sprintf("%02dh %02dm", hours, minutes)
 		}
 		m.mu.Unlock()
 	}
 }
 
-func (m *Monitor) setConnectionStatus(status string, deviceName string, serial string) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	
-	if m.currentState.State.Connection != status {
-		m.currentState.State.Connection = status
-		if status == "online" {
-			m.currentState.Device = deviceName
-			m.currentState.Serial = serial
-			m.currentState.Events.LastPowerOn = time.Now()
-			
-			// Carrega identidade das configurações
-			m.currentState.State.CustomID = m.store.GetSetting("operator_name", "Operador 01")
-			m.currentState.State.CustomColor = m.store.GetSetting("custom_color", "#2196F3")
-			
-			beeep.Notify("ACC Jabra", "Headset Conectado: "+deviceName, "")
-		} else {
-			m.currentState.State.SessionUptime = "00h 00m"
-			m.currentState.State.CustomID = "Desconectado"
-			m.currentState.State.CustomColor = "#9e9e9e"
-			beeep.Alert("ACC Jabra: ALERTA", "Dongle Removido!", "")
+// setConnectionStatus atualiza a conexão de um dispositivo (deve ser chamado com lock)
+func (m *Monitor) setConnectionStatus(dev *deviceState, status string) {
+	if dev.payload.State.Connection == status {
+		return
+	}
+
+	dev.payload.State.Connection = status
+	if status == "online" {
+		dev.payload.Events.LastPowerOn = time.Now()
+
+		// Carrega identidade das configurações
+		dev.payload.State.CustomID = m.store.GetSetting("operator_name", "Operador 01")
+		dev.payload.State.CustomColor = m.store.GetSetting("custom_color", "#2196F3")
+
+		beeep.Notify("ACC Jabra", "Headset Conectado: "+dev.payload.Device, "")
+		m.publish(events.DeviceAttached{
+			DeviceID:   dev.payload.DeviceID,
+			Serial:     dev.payload.Serial,
+			Name:       dev.payload.Device,
+			Model:      dev.payload.Product.Model,
+			FormFactor: dev.payload.Product.FormFactor,
+			ParentID:   dev.payload.ParentID,
+		})
+	} else {
+		dev.payload.State.SessionUptime = "00h 00m"
+		dev.payload.State.CustomID = "Desconectado"
+		dev.payload.State.CustomColor = "#9e9e9e"
+		if alert := m.disconnectAlert(dev); alert != "" {
+			beeep.Alert("ACC Jabra: ALERTA", alert, "")
 		}
-		m.store.LogEvent("connection_change", "Status: "+status)
+		m.publish(events.DeviceDetached{
+			DeviceID: dev.payload.DeviceID,
+			Serial:   dev.payload.Serial,
+			Name:     dev.payload.Device,
+		})
 	}
 }
 
-func (m *Monitor) startHIDScanner() {
-	for {
-		devices := hid.Enumerate(JabraVID, 0)
-		if len(devices) > 0 {
-			info := devices[0]
-			serial := info.Serial
-			if serial == "" {
-				serial = "USB-HID-DEVICE"
-			}
+// disconnectAlert retorna o alerta de desconexão do dispositivo. Headsets
+// pareados não alertam: a saída de alcance é alertada pelo rádio do dongle
+// e a remoção do dongle, pelo próprio dongle.
+func (m *Monitor) disconnectAlert(dev *deviceState) string {
+	switch {
+	case dev.payload.Product.FormFactor == string(FormFactorDongle):
+		return "Dongle Removido!"
+	case dev.payload.ParentID != nil:
+		return ""
+	}
+	return "Headset Desconectado!"
+}
 
-			device, err := info.Open()
-			if err != nil {
-				log.Printf("[Jabra-HID] Erro: %v", err)
-				m.runSimulation()
-			} else {
-				m.setConnectionStatus("online", info.Product, serial)
-				m.readHIDEvents(device)
-				device.Close()
-				m.setConnectionStatus("offline", "", "")
-			}
-		} else {
-			// Se não houver hardware, rodamos em modo simulação para desenvolvimento
-			m.runSimulation()
+// setLink atualiza o rádio do dispositivo com o headset (deve ser chamado com lock)
+func (m *Monitor) setLink(dev *deviceState, link LinkState) {
+	if dev.payload.State.Link == string(link) {
+		return
+	}
+	dev.payload.State.Link = string(link)
+	if dev.online && link == LinkOutOfRange {
+		beeep.Alert("ACC Jabra: ALERTA", "Headset fora de alcance!", "")
+	}
+	m.store.LogEvent("link_change", fmt.Sprintf("%s (ID %d) Rádio: %s", dev.payload.Device, dev.payload.DeviceID, link))
+}
+
+// parent retorna o dongle/base conhecido do dispositivo (deve ser chamado com lock)
+func (m *Monitor) parent(dev *deviceState) *deviceState {
+	if dev.payload.ParentID == nil {
+		return nil
+	}
+	return m.devices[*dev.payload.ParentID]
+}
+
+// hasOnlineChild retorna true se algum headset pareado ao dispositivo está
+// online (deve ser chamado com lock)
+func (m *Monitor) hasOnlineChild(dev *deviceState) bool {
+	for _, id := range dev.payload.Children {
+		if child, ok := m.devices[id]; ok && child.online {
+			return true
 		}
-		time.Sleep(2 * time.Second)
 	}
+	return false
 }
 
-func (m *Monitor) runSimulation() {
+// handleDeviceConnected trata a conexão de um dispositivo reportada pelo driver
+func (m *Monitor) handleDeviceConnected(event DeviceEvent) {
+	if event.Device == nil {
+		return
+	}
+
+	serial := event.Device.SerialNumber
+	if serial == "" {
+		serial = unknownSerial
+	}
+
 	m.mu.Lock()
-	if m.currentState.State.Connection == "online" {
+	dev, ok := m.devices[event.DeviceID]
+	if !ok {
+		dev = &deviceState{payload: m.placeholder, call: NewCallStateMachine()}
+		dev.payload.Events.LastButtonPressed = ""
+		dev.payload.State.Call = dev.call.Info()
+		m.devices[event.DeviceID] = dev
+	}
+	dev.payload.DeviceID = event.DeviceID
+	dev.payload.Device = event.Device.Name
+	if dev.payload.Device == "" {
+		dev.payload.Device = event.Device.Model
+	}
+	dev.payload.Product = models.ProductInfo{
+		ProductID:  event.Device.ProductID,
+		Model:      event.Device.Model,
+		Family:     event.Device.Family,
+		FormFactor: string(event.Device.FormFactor),
+		Wireless:   event.Device.Wireless,
+	}
+	dev.payload.Serial = serial
+	dev.payload.ParentID = event.Device.ParentID
+	dev.payload.State.Link = string(event.Device.LinkState)
+	dev.online = true
+	dev.connectedAt = time.Now()
+
+	// Headset pareado: registra no dongle, que passa a vê-lo ao alcance
+	if parent := m.parent(dev); parent != nil {
+		if !slices.Contains(parent.payload.Children, event.DeviceID) {
+			children := append(slices.Clone(parent.payload.Children), event.DeviceID)
+			slices.Sort(children)
+			parent.payload.Children = children
+		}
+		dev.payload.State.Link = string(LinkConnected)
+		m.setLink(parent, LinkConnected)
+	}
+	m.setConnectionStatus(dev, "online")
+	m.mu.Unlock()
+
+	m.refreshBatteryModel(serial)
+
+	// Leitura inicial da bateria, quando suportada
+	if m.driver == nil {
+		return
+	}
+	if status, err := m.driver.GetBatteryStatus(event.DeviceID); err == nil && status.Level >= 0 {
+		m.handleBatteryUpdate(event.DeviceID, *status)
+	}
+
+	// Volume padrão do operador, se configurado; senão o do dispositivo
+	if level, ok := m.defaultVolume(); ok {
+		if err := m.SetVolume(event.DeviceID, level, "default"); err != nil && !errors.Is(err, ErrNotSupported) {
+			log.Printf("[Jabra] Erro ao aplicar volume padrão (ID %d): %v", event.DeviceID, err)
+		}
+	} else if level, err := m.driver.GetVolume(event.DeviceID); err == nil {
+		m.mu.Lock()
+		if dev, ok := m.devices[event.DeviceID]; ok && dev.online {
+			dev.payload.State.Volume = level
+		}
 		m.mu.Unlock()
+	}
+
+	// Estado inicial do sensor de uso: registrado sem publicar evento, pois
+	// não houve transição
+	if worn, err := m.driver.GetWearState(event.DeviceID); err == nil {
+		m.mu.Lock()
+		if dev, ok := m.devices[event.DeviceID]; ok && dev.online {
+			dev.payload.State.Wear = wearState(worn)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// handleDeviceDisconnected trata a desconexão de um dispositivo
+func (m *Monitor) handleDeviceDisconnected(event DeviceEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev, ok := m.devices[event.DeviceID]
+	if !ok || !dev.online {
 		return
 	}
+	dev.online = false
+	m.lastDeviceID = event.DeviceID
+	m.hasLast = true
+
+	// Headset que some com o dongle presente saiu de alcance; sem o dongle
+	// (ou em dispositivos sem pai) não há rádio a reportar
+	if parent := m.parent(dev); parent != nil && parent.online {
+		dev.payload.State.Link = string(LinkOutOfRange)
+		m.setLink(parent, LinkOutOfRange)
+	} else {
+		dev.payload.State.Link = string(LinkNone)
+	}
+	dev.payload.State.Wear = ""
+
+	// Chamada em curso não sobrevive ao dispositivo: encerra para que
+	// IsInCall e o estado da chamada não fiquem presos
+	m.fireCall(dev, CallEventHangup, "disconnect")
+	m.setConnectionStatus(dev, "offline")
+}
+
+// OnButtonEvent registra um ouvinte para os eventos de botão dos
+// dispositivos online, chamado após a atualização da telemetria
+func (m *Monitor) OnButtonEvent(handler func(event ButtonEvent)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.buttonHandlers = append(m.buttonHandlers, handler)
+}
+
+// handleButtonEvent atualiza o estado a partir dos eventos de botão do driver
+// e repassa o evento aos ouvintes
+func (m *Monitor) handleButtonEvent(event ButtonEvent) {
+	m.mu.Lock()
+	// Publicado antes das transições de chamada que o botão causar
+	if dev, ok := m.devices[event.DeviceID]; ok && dev.online {
+		m.publish(events.Button{
+			DeviceID: event.DeviceID,
+			Serial:   dev.payload.Serial,
+			Button:   event.ButtonID.String(),
+			Pressed:  event.Pressed,
+		})
+	}
+	online := m.updateButtonState(event)
+	handlers := m.buttonHandlers
 	m.mu.Unlock()
 
-	m.setConnectionStatus("online", "Jabra Simulation Mode", "SIM-123456")
-	
-	// Simular variação de bateria
-	go func() {
-		level := 100
-		for {
-			m.mu.Lock()
-			if m.currentState.Serial != "SIM-123456" {
-				m.mu.Unlock()
-				return
-			}
-			m.currentState.State.Battery.Level = level
-			m.currentState.State.Battery.Status = "discharging"
-			m.currentState.State.Battery.EstimatedRemainingMinutes = m.CalculateRemainingMinutes(level, 0.1)
-			m.mu.Unlock()
-
-			level -= 1
-			if level < 0 {
-				level = 100
+	if !online {
+		return
+	}
+
+	// Dispositivos que ajustam o volume internamente: relê o valor
+	if event.Pressed && (event.ButtonID == ButtonVolumeUp || event.ButtonID == ButtonVolumeDown) {
+		m.refreshVolume(event.DeviceID, "button")
+	}
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// updateButtonState aplica o evento à telemetria (deve ser chamado com lock).
+// Retorna false se o dispositivo não está online.
+func (m *Monitor) updateButtonState(event ButtonEvent) bool {
+	dev, ok := m.devices[event.DeviceID]
+	if !ok || !dev.online {
+		return false
+	}
+
+	// Controles on/off absolutos reportam o estado diretamente
+	if event.Absolute {
+		switch event.ButtonID {
+		case ButtonMute:
+			dev.payload.Events.LastButtonPressed = "mute_toggle"
+			m.setMuted(dev, event.Pressed, "button")
+		case ButtonHookSwitch, ButtonOffHook:
+			dev.payload.Events.LastButtonPressed = "hook_switch"
+			if event.Pressed {
+				m.fireCall(dev, CallEventAnswer, "button")
+			} else {
+				m.fireCall(dev, CallEventHangup, "button")
 			}
-			time.Sleep(10 * time.Second)
 		}
-	}()
+		m.syncMute(dev, "button")
+		return true
+	}
+
+	if !event.Pressed {
+		return true
+	}
+
+	switch event.ButtonID {
+	case ButtonMute:
+		dev.payload.Events.LastButtonPressed = "mute_toggle"
+		m.setMuted(dev, !dev.payload.State.IsMuted, "button")
+
+	case ButtonHookSwitch, ButtonOffHook:
+		// Pulso de toggle: o sentido vem do estado da chamada, não de um
+		// booleano invertido a cada pacote
+		dev.payload.Events.LastButtonPressed = "hook_switch"
+		if dev.call.State().Active() {
+			m.fireCall(dev, CallEventHangup, "button")
+		} else {
+			m.fireCall(dev, CallEventAnswer, "button")
+		}
+
+	case ButtonRejectCall, ButtonDecline:
+		dev.payload.Events.LastButtonPressed = event.ButtonID.String()
+		m.fireCall(dev, CallEventReject, "button")
+
+	case ButtonEndCall:
+		dev.payload.Events.LastButtonPressed = event.ButtonID.String()
+		m.fireCall(dev, CallEventHangup, "button")
+
+	case ButtonOnline:
+		// Rádio do dongle com o headset, não uma tecla
+		m.setLink(dev, LinkConnected)
+
+	case ButtonOffline:
+		m.setLink(dev, LinkOutOfRange)
+
+	default:
+		dev.payload.Events.LastButtonPressed = event.ButtonID.String()
+	}
+	m.syncMute(dev, "button")
+	return true
 }
 
-func (m *Monitor) readHIDEvents(device *hid.Device) {
-	buf := make([]byte, 64)
-	for {
-		n, err := device.Read(buf)
-		if err != nil {
-			break
+// fireCall aplica um evento à máquina de chamada do dispositivo e atualiza a
+// telemetria (deve ser chamado com lock). Transições inválidas são apenas
+// registradas em log.
+func (m *Monitor) fireCall(dev *deviceState, event CallEvent, source string) {
+	from := dev.call.State()
+	changed, err := dev.call.Fire(event)
+	if err != nil {
+		log.Printf("[Jabra] Transição de chamada inválida em %s (ID %d, origem %s): %v", dev.payload.Device, dev.payload.DeviceID, source, err)
+		m.store.LogEvent("call_invalid", fmt.Sprintf("%s em %s (%s)", event, from, source))
+		return
+	}
+	if !changed {
+		return
+	}
+
+	to := dev.call.State()
+	dev.payload.State.Call = dev.call.Info()
+	dev.payload.State.IsInCall = to.Active()
+	m.updateBatteryEstimate(dev)
+	log.Printf("[Jabra] Chamada %s (ID %d): %s → %s (%s, %s)", dev.payload.Device, dev.payload.DeviceID, from, to, event, source)
+	m.publish(events.CallState{
+		DeviceID: dev.payload.DeviceID,
+		Serial:   dev.payload.Serial,
+		From:     string(from),
+		To:       string(to),
+		Event:    string(event),
+		Source:   source,
+	})
+	m.scheduleCallReset(dev)
+}
+
+// scheduleCallReset agenda a volta de ended para idle; qualquer outra
+// transição antes disso cancela o agendamento (deve ser chamado com lock)
+func (m *Monitor) scheduleCallReset(dev *deviceState) {
+	if dev.endedTimer != nil {
+		dev.endedTimer.Stop()
+		dev.endedTimer = nil
+	}
+	if dev.call.State() != CallEnded {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(m.callEndedDelay, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if dev.endedTimer != timer {
+			return
 		}
-		if n > 0 {
-			m.processHIDData(buf[:n])
+		dev.endedTimer = nil
+		m.fireCall(dev, CallEventReset, "timeout")
+	})
+	dev.endedTimer = timer
+}
+
+// setMuted atualiza o mute da telemetria e publica a mudança (deve ser
+// chamado com lock)
+func (m *Monitor) setMuted(dev *deviceState, muted bool, source string) {
+	if dev.payload.State.IsMuted == muted {
+		return
+	}
+	dev.payload.State.IsMuted = muted
+	m.lastUpdate = time.Now()
+	m.publish(events.Mute{
+		DeviceID: dev.payload.DeviceID,
+		Serial:   dev.payload.Serial,
+		Muted:    muted,
+		Source:   source,
+	})
+}
+
+// syncMute reflete o mute do dispositivo na máquina de chamada durante uma
+// chamada (deve ser chamado com lock). Fora de chamada o mute é só do microfone.
+func (m *Monitor) syncMute(dev *deviceState, source string) {
+	switch state := dev.call.State(); {
+	case state == CallInCall && dev.payload.State.IsMuted:
+		m.fireCall(dev, CallEventMute, source)
+	case state == CallMuted && !dev.payload.State.IsMuted:
+		m.fireCall(dev, CallEventUnmute, source)
+	}
+}
+
+// SetMute altera o mute no dispositivo e na telemetria; source identifica
+// a origem no evento publicado (ex.: "os" para o mute do sistema de áudio)
+func (m *Monitor) SetMute(deviceID uint16, mute bool, source string) error {
+	if m.driver != nil {
+		// Sem suporte no dispositivo a telemetria ainda acompanha o mute
+		if err := m.driver.SetMute(deviceID, mute); err != nil && !errors.Is(err, ErrNotSupported) {
+			return err
 		}
 	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev, ok := m.devices[deviceID]
+	if !ok || !dev.online {
+		return fmt.Errorf("device %d not online", deviceID)
+	}
+	m.setMuted(dev, mute, source)
+	m.syncMute(dev, source)
+	return nil
+}
+
+// HandleCallEvent aplica um evento de chamada externo (ex.: eventos
+// ligacao_* do socket) ao dispositivo principal
+func (m *Monitor) HandleCallEvent(event CallEvent, source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev := m.primary()
+	if dev == nil || !dev.online {
+		log.Printf("[Jabra] Evento de chamada %s (%s) ignorado: nenhum dispositivo online", event, source)
+		return
+	}
+	m.fireCall(dev, event, source)
+}
+
+// SetHookState altera o hook no dispositivo e alimenta a máquina de chamada
+func (m *Monitor) SetHookState(deviceID uint16, offHook bool) error {
+	if m.driver != nil {
+		// Sem suporte no dispositivo o agente ainda acompanha a chamada
+		if err := m.driver.SetHookState(deviceID, offHook); err != nil && !errors.Is(err, ErrNotSupported) {
+			return err
+		}
+	}
+
+	event := CallEventHangup
+	if offHook {
+		event = CallEventAnswer
+	}
+	return m.fireDeviceCall(deviceID, event, "SetHookState")
+}
+
+// SetHold coloca ou retira a chamada de espera no dispositivo e alimenta a
+// máquina de chamada
+func (m *Monitor) SetHold(deviceID uint16, hold bool) error {
+	if m.driver != nil {
+		// Sem suporte no dispositivo o agente ainda acompanha a chamada
+		if err := m.driver.SetHold(deviceID, hold); err != nil && !errors.Is(err, ErrNotSupported) {
+			return err
+		}
+	}
+
+	event := CallEventResume
+	if hold {
+		event = CallEventHold
+	}
+	return m.fireDeviceCall(deviceID, event, "SetHold")
+}
+
+// fireDeviceCall aplica um evento de chamada a um dispositivo pelo ID e
+// reaplica o mute (o retorno da espera volta a muted se o microfone ficou
+// mudo durante a espera)
+func (m *Monitor) fireDeviceCall(deviceID uint16, event CallEvent, source string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev, ok := m.devices[deviceID]
+	if !ok {
+		return fmt.Errorf("device %d not found", deviceID)
+	}
+	m.fireCall(dev, event, source)
+	m.syncMute(dev, source)
+	return nil
 }
 
-func (m *Monitor) processHIDData(data []byte) {
+// handleBatteryUpdate atualiza o estado da bateria reportado pelo driver
+func (m *Monitor) handleBatteryUpdate(deviceID uint16, status BatteryStatus) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	// Log para debug de pacotes novos
-	log.Printf("[Jabra-HID] Packet: %v", data)
+	dev, ok := m.devices[deviceID]
+	if !ok || !dev.online {
+		return
+	}
+
+	battery := &dev.payload.State.Battery
+	battery.Level = status.Level
+	switch {
+	case status.IsCharging && status.Level >= 100:
+		battery.Status = "fully charged"
+	case status.IsCharging:
+		battery.Status = "charging"
+	default:
+		battery.Status = "discharging"
+	}
+	m.updateBatteryEstimate(dev)
+	m.lastUpdate = time.Now()
+	m.publish(events.Battery{
+		DeviceID: deviceID,
+		Serial:   dev.payload.Serial,
+		Level:    battery.Level,
+		Status:   battery.Status,
+	})
+}
 
-	// Mute Toggle (Padrão observado em alguns headsets)
-	if data[0] == 0x01 && data[1] == 0x02 {
-		m.currentState.Events.LastButtonPressed = "mute_toggle"
-		m.currentState.State.IsMuted = !m.currentState.State.IsMuted
-		m.store.LogEvent("button", "Mute Toggled")
+// handleWearEvent registra o headset colocado/retirado e publica a transição
+// com o estado da chamada no momento, usado pelas regras do keymap
+func (m *Monitor) handleWearEvent(event WearEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev, ok := m.devices[event.DeviceID]
+	if !ok || !dev.online {
 		return
 	}
 
-	// Hook Switch (Call answer/end)
-	if data[0] == 0x01 && (data[1] == 0x04 || data[1] == 0x08) {
-		m.currentState.Events.LastButtonPressed = "hook_switch"
-		m.currentState.State.IsInCall = !m.currentState.State.IsInCall
-		m.store.LogEvent("button", "Hook Switch Toggled")
+	state := wearState(event.Worn)
+	if dev.payload.State.Wear == state {
 		return
 	}
+	dev.payload.State.Wear = state
+	m.lastUpdate = time.Now()
+	if event.Worn {
+		log.Printf("[Jabra] Headset colocado (ID %d)", event.DeviceID)
+	} else {
+		log.Printf("[Jabra] Headset retirado (ID %d)", event.DeviceID)
+	}
+	m.publish(events.Wear{
+		DeviceID:  event.DeviceID,
+		Serial:    dev.payload.Serial,
+		Worn:      event.Worn,
+		CallState: string(dev.call.State()),
+	})
 }
 
-func (m *Monitor) batteryLogger() {
-	ticker := time.NewTicker(5 * time.Minute)
-	for range ticker.C {
-		m.mu.RLock()
-		status := m.currentState.State.Connection
-		level := m.currentState.State.Battery.Level
-		m.mu.RUnlock()
+// defaultVolume retorna o volume padrão aplicado na conexão: a setting
+// default_volume:<operador> ou, sem ela, default_volume
+func (m *Monitor) defaultVolume() (int, bool) {
+	key := "default_volume:" + m.store.GetSetting("operator_name", "Operador 01")
+	raw := m.store.GetSetting(key, "")
+	if raw == "" {
+		key = "default_volume"
+		raw = m.store.GetSetting(key, "")
+	}
+	if raw == "" {
+		return 0, false
+	}
+	level, err := strconv.Atoi(raw)
+	if err != nil || level < 0 || level > 100 {
+		log.Printf("[Jabra] Aviso: %s inválido: %q", key, raw)
+		return 0, false
+	}
+	return level, true
+}
+
+// SetVolume define o volume (0-100) no dispositivo e atualiza a telemetria;
+// source identifica a origem no evento publicado
+func (m *Monitor) SetVolume(deviceID uint16, level int, source string) error {
+	if level < 0 || level > 100 {
+		return fmt.Errorf("volume %d out of range 0-100", level)
+	}
+	if m.driver == nil {
+		return notSupported(CapabilityVolume, deviceID)
+	}
+	if err := m.driver.SetVolume(deviceID, level); err != nil {
+		return err
+	}
+	m.setVolume(deviceID, level, source)
+	return nil
+}
+
+// AdjustVolume soma delta ao volume atual do dispositivo, limitado a 0-100,
+// e retorna o novo volume
+func (m *Monitor) AdjustVolume(deviceID uint16, delta int, source string) (int, error) {
+	current, err := m.GetVolume(deviceID)
+	if err != nil {
+		return 0, err
+	}
+	level := min(max(current+delta, 0), 100)
+	if err := m.SetVolume(deviceID, level, source); err != nil {
+		return 0, err
+	}
+	return level, nil
+}
+
+// StepVolume aplica um passo de volume do keymap ao dispositivo e retorna o
+// novo volume. O passo é ignorado (applied false) sem suporte a volume e em
+// dispositivos que ajustam o próprio volume pelos botões: o ajuste já foi
+// feito e relido em handleButtonEvent, somar o passo dobraria o ajuste.
+func (m *Monitor) StepVolume(deviceID uint16, delta int, source string) (level int, applied bool, err error) {
+	if m.driver == nil {
+		return 0, false, nil
+	}
+	caps, err := m.driver.Capabilities(deviceID)
+	if err != nil {
+		return 0, false, err
+	}
+	if !caps.Volume || caps.VolumeButtons {
+		return 0, false, nil
+	}
+	level, err = m.AdjustVolume(deviceID, delta, source)
+	return level, err == nil, err
+}
+
+// StepPrimaryVolume aplica um passo de volume do keymap ao dispositivo principal
+func (m *Monitor) StepPrimaryVolume(delta int, source string) (int, bool, error) {
+	deviceID, err := m.primaryOnline()
+	if err != nil {
+		return 0, false, err
+	}
+	return m.StepVolume(deviceID, delta, source)
+}
+
+// primaryOnline retorna o ID do dispositivo principal, se estiver online
+func (m *Monitor) primaryOnline() (uint16, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	dev := m.primary()
+	if dev == nil || !dev.online {
+		return 0, errors.New("no device online")
+	}
+	return dev.payload.DeviceID, nil
+}
+
+// SetBusylight liga ou desliga o LED de ocupado do dispositivo; source
+// identifica a origem no log
+func (m *Monitor) SetBusylight(deviceID uint16, on bool, source string) error {
+	if m.driver == nil {
+		return notSupported(CapabilityBusylight, deviceID)
+	}
+	if err := m.driver.SetBusylight(deviceID, on); err != nil {
+		return err
+	}
+	state := "desligado"
+	if on {
+		state = "ligado"
+	}
+	log.Printf("[Jabra] Busylight %s (ID %d, origem %s)", state, deviceID, source)
+	return nil
+}
+
+// SetPrimaryBusylight liga ou desliga o LED de ocupado do dispositivo principal
+func (m *Monitor) SetPrimaryBusylight(on bool, source string) error {
+	deviceID, err := m.primaryOnline()
+	if err != nil {
+		return err
+	}
+	return m.SetBusylight(deviceID, on, source)
+}
+
+// GetVolume lê o volume do dispositivo e atualiza a telemetria
+func (m *Monitor) GetVolume(deviceID uint16) (int, error) {
+	if m.driver == nil {
+		return 0, notSupported(CapabilityVolume, deviceID)
+	}
+	level, err := m.driver.GetVolume(deviceID)
+	if err != nil {
+		return 0, err
+	}
+	m.setVolume(deviceID, level, "device")
+	return level, nil
+}
+
+// refreshVolume relê o volume do dispositivo, quando suportado
+func (m *Monitor) refreshVolume(deviceID uint16, source string) {
+	if m.driver == nil {
+		return
+	}
+	if level, err := m.driver.GetVolume(deviceID); err == nil {
+		m.setVolume(deviceID, level, source)
+	}
+}
+
+// setVolume atualiza o volume da telemetria e publica a mudança
+func (m *Monitor) setVolume(deviceID uint16, level int, source string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dev, ok := m.devices[deviceID]
+	if !ok || !dev.online || dev.payload.State.Volume == level {
+		return
+	}
+	dev.payload.State.Volume = level
+	m.lastUpdate = time.Now()
+	m.publish(events.Volume{
+		DeviceID: deviceID,
+		Serial:   dev.payload.Serial,
+		Level:    level,
+		Source:   source,
+	})
+}
+
+// wearState converte o sensor de uso para o valor de DeviceState.Wear
+func wearState(worn bool) string {
+	if worn {
+		return "on"
+	}
+	return "off"
+}
+
+// updateBatteryEstimate recalcula a autonomia com as taxas aprendidas para o
+// serial do dispositivo (deve ser chamado com lock)
+func (m *Monitor) updateBatteryEstimate(dev *deviceState) {
+	info := &dev.payload.State.Battery
+	if info.Status == "unknown" {
+		return
+	}
+
+	model, ok := m.batteryModels[dev.payload.Serial]
+	if !ok {
+		model = battery.Learn(dev.payload.Serial, nil, battery.DefaultConfig())
+	}
+	charging := info.Status == "charging" || info.Status == "fully charged"
+	est := model.Estimate(info.Level, charging, dev.call.State().Active())
+
+	info.EstimatedRemainingMinutes = est.TimeToEmptyMinutes
+	info.TimeToEmptyMinutes = est.TimeToEmptyMinutes
+	info.TimeToFullMinutes = est.TimeToFullMinutes
+	info.TalkTimeMinutes = est.TalkTimeMinutes
+	info.IdleTimeMinutes = est.IdleTimeMinutes
+	info.Confidence = est.Confidence
+}
+
+// refreshBatteryModel reaprende as taxas do serial a partir do battery_history.
+// Dispositivos sem serial compartilham unknownSerial e ficam com as taxas
+// padrão: o histórico misturaria baterias diferentes.
+func (m *Monitor) refreshBatteryModel(serial string) {
+	var samples []db.BatterySample
+	if serial != unknownSerial {
+		var err error
+		samples, err = m.store.GetBatterySamples(serial, time.Now().Add(-batteryHistoryWindow))
+		if err != nil {
+			log.Printf("[Jabra] Erro ao ler histórico de bateria de %s: %v", serial, err)
+			return
+		}
+	}
+	model := battery.Learn(serial, samples, battery.DefaultConfig())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batteryModels[serial] = model
+	for _, dev := range m.devices {
+		if dev.online && dev.payload.Serial == serial {
+			m.updateBatteryEstimate(dev)
+		}
+	}
+}
+
+// BatteryModel retorna as taxas aprendidas para um serial
+func (m *Monitor) BatteryModel(serial string) (battery.Model, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	model, ok := m.batteryModels[serial]
+	return model, ok
+}
+
+// logBatterySamples grava a leitura atual de cada dispositivo online e
+// reaprende as taxas dos seriais gravados
+func (m *Monitor) logBatterySamples() {
+	var samples []db.BatterySample
+	m.mu.RLock()
+	for _, dev := range m.devices {
+		info := dev.payload.State.Battery
+		if !dev.online || info.Status == "unknown" {
+			continue
+		}
+		samples = append(samples, db.BatterySample{
+			Serial: dev.payload.Serial,
+			Level:  info.Level,
+			Status: info.Status,
+			InCall: dev.call.State().Active(),
+		})
+	}
+	m.mu.RUnlock()
 
-		if status == "online" {
-			m.store.LogBattery(level, "periodic_check")
+	for _, sample := range samples {
+		m.store.LogBatterySample(sample)
+		m.refreshBatteryModel(sample.Serial)
+	}
+}
+
+func (m *Monitor) batteryLogger(ctx context.Context) error {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			m.logBatterySamples()
 		}
 	}
 }
@@ -204,8 +1026,68 @@ func (m *Monitor) CalculateRemainingMinutes(currentLevel int, dischargeRate floa
 	return int(float64(currentLevel) / dischargeRate)
 }
 
+// primary retorna o dispositivo principal (deve ser chamado com lock).
+// É o dispositivo online conectado há mais tempo, exceto dongles com headset
+// pareado online (o headset é o principal); sem nenhum online, o último
+// desconectado.
+func (m *Monitor) primary() *deviceState {
+	var best *deviceState
+	for _, dev := range m.devices {
+		// Dongle com headset pareado online: o headset é o principal
+		if !dev.online || m.hasOnlineChild(dev) {
+			continue
+		}
+		if best == nil || dev.connectedAt.Before(best.connectedAt) ||
+			(dev.connectedAt.Equal(best.connectedAt) && dev.payload.DeviceID < best.payload.DeviceID) {
+			best = dev
+		}
+	}
+	if best == nil && m.hasLast {
+		best = m.devices[m.lastDeviceID]
+	}
+	return best
+}
+
+// GetTelemetry retorna a telemetria do dispositivo principal
 func (m *Monitor) GetTelemetry() models.TelemetryPayload {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	return m.currentState
+
+	if dev := m.primary(); dev != nil {
+		return dev.payload
+	}
+	return m.placeholder
+}
+
+// GetDevicesTelemetry retorna a telemetria de todos os dispositivos conhecidos, ordenada por ID
+func (m *Monitor) GetDevicesTelemetry() []models.TelemetryPayload {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	payloads := make([]models.TelemetryPayload, 0, len(m.devices))
+	for _, dev := range m.devices {
+		payloads = append(payloads, dev.payload)
+	}
+	sort.Slice(payloads, func(i, j int) bool {
+		return payloads[i].DeviceID < payloads[j].DeviceID
+	})
+	return payloads
+}
+
+// GetDeviceTelemetry retorna a telemetria de um dispositivo pelo ID ou número serial
+func (m *Monitor) GetDeviceTelemetry(key string) (models.TelemetryPayload, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if id, err := strconv.ParseUint(key, 10, 16); err == nil {
+		if dev, ok := m.devices[uint16(id)]; ok {
+			return dev.payload, true
+		}
+	}
+	for _, dev := range m.devices {
+		if dev.payload.Serial == key {
+			return dev.payload, true
+		}
+	}
+	return models.TelemetryPayload{}, false
 }
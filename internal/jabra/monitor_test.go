@@ -1,7 +1,12 @@
 package jabra
 
 import (
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/battery"
+	"github.com/aiknow/acc_jabra_agent/internal/db"
 )
 
 func TestCalculateRemainingMinutes(t *testing.T) {
@@ -29,3 +34,118 @@ func TestCalculateRemainingMinutes(t *testing.T) {
 		})
 	}
 }
+
+func TestMonitorMultipleDevices(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "monitor_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	m := NewMonitor(nil, "", store)
+
+	var forwarded []ButtonEvent
+	m.OnButtonEvent(func(event ButtonEvent) { forwarded = append(forwarded, event) })
+
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: &DeviceInfo{ID: 1, Name: "Jabra Link 380", SerialNumber: "DONGLE-1"}})
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 2, Connected: true, Device: &DeviceInfo{ID: 2, Name: "Jabra Engage 55", SerialNumber: "HEADSET-2"}})
+	m.handleBatteryUpdate(2, BatteryStatus{Level: 80})
+	m.handleButtonEvent(ButtonEvent{DeviceID: 2, ButtonID: ButtonMute, Pressed: true})
+
+	m.handleButtonEvent(ButtonEvent{DeviceID: 9, ButtonID: ButtonFlash, Pressed: true})
+	if len(forwarded) != 1 || forwarded[0].DeviceID != 2 {
+		t.Errorf("apenas eventos de dispositivos online devem ser repassados: %+v", forwarded)
+	}
+
+	if got := len(m.GetDevicesTelemetry()); got != 2 {
+		t.Fatalf("esperado 2 dispositivos, obtido %d", got)
+	}
+
+	headset, ok := m.GetDeviceTelemetry("HEADSET-2")
+	if !ok {
+		t.Fatal("dispositivo HEADSET-2 não encontrado pelo serial")
+	}
+	if headset.State.Battery.Level != 80 || !headset.State.IsMuted {
+		t.Errorf("estado do headset incorreto: %+v", headset.State)
+	}
+
+	dongle, _ := m.GetDeviceTelemetry("1")
+	if dongle.State.IsMuted || dongle.State.Battery.Level != 0 {
+		t.Errorf("estado do dongle não deveria ser afetado: %+v", dongle.State)
+	}
+
+	if primary := m.GetTelemetry(); primary.DeviceID != 1 {
+		t.Errorf("dispositivo principal esperado 1, obtido %d", primary.DeviceID)
+	}
+
+	m.handleDeviceDisconnected(DeviceEvent{DeviceID: 1})
+	if primary := m.GetTelemetry(); primary.DeviceID != 2 || primary.State.Connection != "online" {
+		t.Errorf("dispositivo principal após desconexão incorreto: %d (%s)", primary.DeviceID, primary.State.Connection)
+	}
+
+	m.handleDeviceDisconnected(DeviceEvent{DeviceID: 2})
+	if primary := m.GetTelemetry(); primary.State.Connection != "offline" {
+		t.Errorf("telemetria principal deveria estar offline, obtido %s", primary.State.Connection)
+	}
+}
+
+func TestMonitorBatteryEstimate(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "estimate_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+
+	// Histórico do serial: 1% a cada 5 minutos fora de chamada (0.2 %/min)
+	start := time.Now().Add(-2 * time.Hour)
+	for i := 0; i <= 12; i++ {
+		store.LogBatterySample(db.BatterySample{
+			Serial: "HEADSET-1",
+			Level:  100 - i,
+			Status: "discharging",
+			Time:   start.Add(time.Duration(i) * 5 * time.Minute),
+		})
+	}
+
+	m := NewMonitor(nil, "", store)
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: &DeviceInfo{ID: 1, Name: "Jabra Engage 55", SerialNumber: "HEADSET-1"}})
+	m.handleBatteryUpdate(1, BatteryStatus{Level: 60})
+
+	battery := m.GetTelemetry().State.Battery
+	if battery.TimeToEmptyMinutes != 300 || battery.EstimatedRemainingMinutes != 300 {
+		t.Errorf("autonomia esperada 300 min, obtido %+v", battery)
+	}
+	if battery.Confidence <= 0 {
+		t.Errorf("confiança deveria ser positiva com histórico: %v", battery.Confidence)
+	}
+
+	m.logBatterySamples()
+	if model, ok := m.BatteryModel("HEADSET-1"); !ok || model.Idle.ObservedMinutes != 60 {
+		t.Errorf("modelo não reaprendido após gravar leitura: %+v", model)
+	}
+}
+
+func TestMonitorBatteryUnknownSerial(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "unknown_serial_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+
+	// Histórico gravado por outro dispositivo sem serial
+	start := time.Now().Add(-2 * time.Hour)
+	for i := 0; i <= 12; i++ {
+		store.LogBatterySample(db.BatterySample{
+			Serial: unknownSerial,
+			Level:  100 - 5*i,
+			Status: "discharging",
+			Time:   start.Add(time.Duration(i) * 5 * time.Minute),
+		})
+	}
+
+	m := NewMonitor(nil, "", store)
+	m.handleDeviceConnected(DeviceEvent{DeviceID: 1, Connected: true, Device: &DeviceInfo{ID: 1, Name: "Jabra Evolve 20"}})
+	m.handleBatteryUpdate(1, BatteryStatus{Level: 60})
+	m.logBatterySamples()
+
+	model, ok := m.BatteryModel(unknownSerial)
+	if !ok || model.Idle.Source != battery.RateDefault || model.Idle.ObservedMinutes != 0 {
+		t.Errorf("dispositivo sem serial não deveria aprender do histórico compartilhado: %+v", model)
+	}
+}
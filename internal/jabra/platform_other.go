@@ -0,0 +1,13 @@
+//go:build !windows
+
+package jabra
+
+// NewPlatformDriver retorna o driver adequado para a plataforma atual.
+// Fora do Windows usa o driver HID genérico.
+func NewPlatformDriver(config DriverConfig) (Driver, error) {
+	driver, err := NewHIDDriver(config)
+	if err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
@@ -0,0 +1,13 @@
+//go:build windows && cgo
+
+package jabra
+
+// NewPlatformDriver retorna o driver adequado para a plataforma atual.
+// No Windows usa o Jabra SDK nativo (libjabra.dll).
+func NewPlatformDriver(config DriverConfig) (Driver, error) {
+	driver, err := NewSDKDriver(config)
+	if err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
@@ -0,0 +1,11 @@
+//go:build windows && !cgo
+
+package jabra
+
+import "errors"
+
+// NewPlatformDriver não está disponível em builds Windows sem CGO,
+// pois o Jabra SDK exige CGO (ver target build-windows-nocgo).
+func NewPlatformDriver(config DriverConfig) (Driver, error) {
+	return nil, errors.New("jabra SDK driver requires CGO")
+}
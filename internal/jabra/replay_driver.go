@@ -0,0 +1,416 @@
+package jabra
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/hidtransport"
+)
+
+// ReplayDriver implementa Driver reproduzindo uma captura HID gravada pelo
+// hidtransport.Recorder. Conexões, desconexões e relatórios de entrada são
+// decodificados como no HIDDriver, permitindo reproduzir localmente problemas
+// de mapeamento de botões observados em campo.
+type ReplayDriver struct {
+	mu      sync.RWMutex
+	records []hidtransport.Record
+	speed   float64
+	running bool
+	stopCh  chan struct{}
+	done    chan struct{}
+
+	// Dispositivos conectados no ponto atual da reprodução
+	devices map[uint16]*DeviceInfo
+
+	// Interfaces anexadas: path → ID do dispositivo físico
+	paths map[string]uint16
+
+	// IDs atribuídos por dispositivo físico (mesma regra do HIDDriver)
+	deviceIDs map[string]uint16
+	nextID    uint16
+
+	// Decodificadores por interface, a partir dos descritores capturados
+	decoders map[string]*TelephonyDecoder
+
+	// Callbacks
+	onDeviceConnected    func(event DeviceEvent)
+	onDeviceDisconnected func(event DeviceEvent)
+	onButtonEvent        func(event ButtonEvent)
+	onBatteryUpdate      func(deviceID uint16, status BatteryStatus)
+}
+
+// NewReplayDriver cria o driver para os registros informados. speed é o
+// fator de aceleração: 1 reproduz em tempo real, 10 dez vezes mais rápido e
+// 0 sem nenhuma espera entre registros.
+func NewReplayDriver(records []hidtransport.Record, speed float64) *ReplayDriver {
+	if speed < 0 {
+		speed = 0
+	}
+	return &ReplayDriver{
+		records:   records,
+		speed:     speed,
+		stopCh:    make(chan struct{}),
+		done:      make(chan struct{}),
+		devices:   make(map[uint16]*DeviceInfo),
+		paths:     make(map[string]uint16),
+		deviceIDs: make(map[string]uint16),
+		decoders:  make(map[string]*TelephonyDecoder),
+	}
+}
+
+// OpenReplayDriver carrega um arquivo de captura e cria o driver
+func OpenReplayDriver(path string, speed float64) (*ReplayDriver, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := hidtransport.ReadCapture(f)
+	if err != nil {
+		return nil, fmt.Errorf("read capture %s: %w", path, err)
+	}
+	return NewReplayDriver(records, speed), nil
+}
+
+// Start inicia a reprodução
+func (d *ReplayDriver) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.running {
+		return errors.New("driver already running")
+	}
+	select {
+	case <-d.done:
+		return errors.New("replay already finished")
+	default:
+	}
+
+	d.running = true
+	go d.playback()
+
+	log.Printf("[Replay Driver] Iniciado (%d registros, velocidade %gx)", len(d.records), d.speed)
+	return nil
+}
+
+// Stop interrompe a reprodução e aguarda o término
+func (d *ReplayDriver) Stop() error {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return nil
+	}
+	d.running = false
+	close(d.stopCh)
+	d.mu.Unlock()
+
+	<-d.done
+	log.Println("[Replay Driver] Parado")
+	return nil
+}
+
+// IsRunning retorna se o driver está ativo
+func (d *ReplayDriver) IsRunning() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.running
+}
+
+// Done é fechado quando a reprodução termina ou é interrompida
+func (d *ReplayDriver) Done() <-chan struct{} {
+	return d.done
+}
+
+// playback percorre os registros respeitando os intervalos gravados
+func (d *ReplayDriver) playback() {
+	defer close(d.done)
+
+	var last time.Time
+	for _, rec := range d.records {
+		if !last.IsZero() && d.speed > 0 {
+			if delay := time.Duration(float64(rec.Time.Sub(last)) / d.speed); delay > 0 {
+				timer := time.NewTimer(delay)
+				select {
+				case <-d.stopCh:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		}
+		last = rec.Time
+
+		select {
+		case <-d.stopCh:
+			return
+		default:
+		}
+		d.apply(rec)
+	}
+	log.Println("[Replay Driver] Reprodução concluída")
+}
+
+// apply aplica um registro da captura e dispara os callbacks correspondentes
+func (d *ReplayDriver) apply(rec hidtransport.Record) {
+	data, _ := rec.Bytes()
+
+	switch rec.Type {
+	case hidtransport.RecordMeta:
+		log.Printf("[Replay Driver] Captura: %v", rec.Meta)
+
+	case hidtransport.RecordAttach:
+		if rec.Device != nil {
+			d.attach(*rec.Device)
+		}
+
+	case hidtransport.RecordDetach:
+		d.detach(rec.Path)
+
+	case hidtransport.RecordDescriptor:
+		decoder, err := ParseTelephonyDecoder(data)
+		if err != nil {
+			log.Printf("[Replay Driver] Descritor inválido em %s: %v", rec.Path, err)
+			return
+		}
+		if decoder != nil {
+			d.mu.Lock()
+			d.decoders[rec.Path] = decoder
+			d.mu.Unlock()
+		}
+
+	case hidtransport.RecordInput:
+		d.input(rec.Path, data)
+	}
+}
+
+// attach registra uma interface; a primeira de um dispositivo o conecta
+func (d *ReplayDriver) attach(info hidtransport.DeviceInfo) {
+	d.mu.Lock()
+	key := hidDeviceKey(info)
+	deviceID, known := d.deviceIDs[key]
+	if !known {
+		d.nextID++
+		deviceID = d.nextID
+		d.deviceIDs[key] = deviceID
+	}
+	d.paths[info.Path] = deviceID
+
+	if _, exists := d.devices[deviceID]; exists {
+		d.mu.Unlock()
+		return
+	}
+	device := &DeviceInfo{
+		ID:           deviceID,
+		Name:         info.Product,
+		SerialNumber: info.Serial,
+		VendorID:     info.VendorID,
+		ProductID:    info.ProductID,
+		Connected:    true,
+		ConnectedAt:  time.Now(),
+	}
+	defaultCatalog.Enrich(device)
+	d.devices[deviceID] = device
+	handler := d.onDeviceConnected
+	d.mu.Unlock()
+
+	log.Printf("[Replay Driver] Dispositivo conectado: %s (ID: %d)", device.Name, deviceID)
+	if handler != nil {
+		info := *device
+		handler(DeviceEvent{DeviceID: deviceID, Connected: true, Device: &info})
+	}
+}
+
+// detach remove uma interface; a última de um dispositivo o desconecta
+func (d *ReplayDriver) detach(path string) {
+	d.mu.Lock()
+	deviceID, ok := d.paths[path]
+	if !ok {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.paths, path)
+	delete(d.decoders, path)
+
+	for _, id := range d.paths {
+		if id == deviceID {
+			d.mu.Unlock()
+			return
+		}
+	}
+
+	device, ok := d.devices[deviceID]
+	delete(d.devices, deviceID)
+	handler := d.onDeviceDisconnected
+	d.mu.Unlock()
+
+	if !ok {
+		return
+	}
+	log.Printf("[Replay Driver] Dispositivo desconectado: %s (ID: %d)", device.Name, deviceID)
+	if handler != nil {
+		device.Connected = false
+		handler(DeviceEvent{DeviceID: deviceID, Connected: false, Device: device})
+	}
+}
+
+// input decodifica um relatório de entrada capturado
+func (d *ReplayDriver) input(path string, data []byte) {
+	d.mu.RLock()
+	deviceID, ok := d.paths[path]
+	decoder := d.decoders[path]
+	handler := d.onButtonEvent
+	d.mu.RUnlock()
+
+	if !ok {
+		return
+	}
+
+	var events []ButtonEvent
+	if decoder != nil {
+		events = decoder.Decode(data)
+	} else if event, ok := legacyButtonEvent(data); ok {
+		events = []ButtonEvent{event}
+	}
+
+	if handler == nil {
+		return
+	}
+	for _, event := range events {
+		event.DeviceID = deviceID
+		handler(event)
+	}
+}
+
+// GetDevices retorna os dispositivos conectados no ponto atual da reprodução
+func (d *ReplayDriver) GetDevices() []DeviceInfo {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	devices := make([]DeviceInfo, 0, len(d.devices))
+	for _, dev := range d.devices {
+		devices = append(devices, *dev)
+	}
+	return devices
+}
+
+// GetDevice retorna informações de um dispositivo específico
+func (d *ReplayDriver) GetDevice(deviceID uint16) (*DeviceInfo, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	dev, ok := d.devices[deviceID]
+	if !ok {
+		return nil, fmt.Errorf("device %d not found", deviceID)
+	}
+	info := *dev
+	return &info, nil
+}
+
+// Capabilities - a reprodução não controla o dispositivo
+func (d *ReplayDriver) Capabilities(deviceID uint16) (Capabilities, error) {
+	if _, err := d.GetDevice(deviceID); err != nil {
+		return Capabilities{}, err
+	}
+	return Capabilities{}, nil
+}
+
+// GetBatteryStatus - capturas HID não contêm leitura de bateria
+func (d *ReplayDriver) GetBatteryStatus(deviceID uint16) (*BatteryStatus, error) {
+	return nil, notSupported(CapabilityBattery, deviceID)
+}
+
+// SetMute não é suportado durante a reprodução
+func (d *ReplayDriver) SetMute(deviceID uint16, mute bool) error {
+	return notSupported(CapabilityMute, deviceID)
+}
+
+// GetMute não é reproduzido
+func (d *ReplayDriver) GetMute(deviceID uint16) (bool, error) {
+	return false, notSupported(CapabilityMute, deviceID)
+}
+
+// SetRinger não é suportado durante a reprodução
+func (d *ReplayDriver) SetRinger(deviceID uint16, ring bool) error {
+	return notSupported(CapabilityRinger, deviceID)
+}
+
+// SetHookState não é suportado durante a reprodução
+func (d *ReplayDriver) SetHookState(deviceID uint16, offHook bool) error {
+	return notSupported(CapabilityHook, deviceID)
+}
+
+// SetBusylight não é suportado durante a reprodução
+func (d *ReplayDriver) SetBusylight(deviceID uint16, on bool) error {
+	return notSupported(CapabilityBusylight, deviceID)
+}
+
+// SetHold não é suportado durante a reprodução
+func (d *ReplayDriver) SetHold(deviceID uint16, hold bool) error {
+	return notSupported(CapabilityHold, deviceID)
+}
+
+// SetVolume não é suportado durante a reprodução
+func (d *ReplayDriver) SetVolume(deviceID uint16, volume int) error {
+	return notSupported(CapabilityVolume, deviceID)
+}
+
+// GetVolume não é reproduzido
+func (d *ReplayDriver) GetVolume(deviceID uint16) (int, error) {
+	return 0, notSupported(CapabilityVolume, deviceID)
+}
+
+// GetWearState - a captura HID não contém o sensor de uso
+func (d *ReplayDriver) GetWearState(deviceID uint16) (bool, error) {
+	return false, notSupported(CapabilityWear, deviceID)
+}
+
+// ListSettings - a captura HID não contém configurações
+func (d *ReplayDriver) ListSettings(deviceID uint16) ([]Setting, error) {
+	return nil, notSupported(CapabilitySettings, deviceID)
+}
+
+// GetSetting - a captura HID não contém configurações
+func (d *ReplayDriver) GetSetting(deviceID uint16, key string) (Setting, error) {
+	return Setting{}, notSupported(CapabilitySettings, deviceID)
+}
+
+// SetSetting - a captura HID não contém configurações
+func (d *ReplayDriver) SetSetting(deviceID uint16, key string, value any) error {
+	return notSupported(CapabilitySettings, deviceID)
+}
+
+// OnDeviceConnected registra callback
+func (d *ReplayDriver) OnDeviceConnected(handler func(event DeviceEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onDeviceConnected = handler
+}
+
+// OnDeviceDisconnected registra callback
+func (d *ReplayDriver) OnDeviceDisconnected(handler func(event DeviceEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onDeviceDisconnected = handler
+}
+
+// OnButtonEvent registra callback
+func (d *ReplayDriver) OnButtonEvent(handler func(event ButtonEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onButtonEvent = handler
+}
+
+// OnBatteryUpdate registra callback
+func (d *ReplayDriver) OnBatteryUpdate(handler func(deviceID uint16, status BatteryStatus)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onBatteryUpdate = handler
+}
+
+// OnWearEvent - a captura HID não contém o sensor de uso
+func (d *ReplayDriver) OnWearEvent(handler func(event WearEvent)) {}
@@ -0,0 +1,91 @@
+package jabra
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/hidtransport"
+)
+
+func TestReplayDriver(t *testing.T) {
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	info := hidtransport.DeviceInfo{Path: "1:2:3", VendorID: JabraVID, ProductID: 0x0123, Serial: "ABC", Product: "Jabra Engage 55"}
+
+	records := []hidtransport.Record{
+		{Time: at(0), Type: hidtransport.RecordMeta, Meta: map[string]string{"version": "1"}},
+		{Time: at(10), Type: hidtransport.RecordAttach, Path: info.Path, Device: &info},
+		{Time: at(20), Type: hidtransport.RecordDescriptor, Path: info.Path, Data: hex.EncodeToString(testHeadsetDescriptor)},
+		{Time: at(500), Type: hidtransport.RecordInput, Path: info.Path, Data: "0201"},
+		{Time: at(900), Type: hidtransport.RecordInput, Path: info.Path, Data: "0203"},
+		{Time: at(950), Type: hidtransport.RecordOutput, Path: info.Path, Data: "0202"},
+		{Time: at(1500), Type: hidtransport.RecordDetach, Path: info.Path},
+	}
+
+	// 100x: a captura de 1,5 s é reproduzida em ~15 ms
+	driver := NewReplayDriver(records, 100)
+	var devices []DeviceEvent
+	var buttons []ButtonEvent
+	driver.OnDeviceConnected(func(event DeviceEvent) { devices = append(devices, event) })
+	driver.OnDeviceDisconnected(func(event DeviceEvent) { devices = append(devices, event) })
+	driver.OnButtonEvent(func(event ButtonEvent) { buttons = append(buttons, event) })
+
+	begin := time.Now()
+	if err := driver.Start(); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	select {
+	case <-driver.Done():
+	case <-time.After(2 * time.Second):
+		t.Fatal("reprodução não terminou")
+	}
+	if elapsed := time.Since(begin); elapsed < 10*time.Millisecond {
+		t.Errorf("reprodução acelerada ignorou os intervalos (%v)", elapsed)
+	}
+	driver.Stop()
+
+	if len(devices) != 2 || !devices[0].Connected || devices[0].Device.SerialNumber != "ABC" || devices[1].Connected {
+		t.Fatalf("eventos de dispositivo inesperados: %+v", devices)
+	}
+	want := []ButtonEvent{
+		{DeviceID: devices[0].DeviceID, ButtonID: ButtonHookSwitch, Pressed: true, Absolute: true},
+		{DeviceID: devices[0].DeviceID, ButtonID: ButtonMute, Pressed: true},
+	}
+	if len(buttons) != len(want) {
+		t.Fatalf("eventos de botão = %+v, want %+v", buttons, want)
+	}
+	for i := range want {
+		if buttons[i] != want[i] {
+			t.Errorf("evento %d = %+v, want %+v", i, buttons[i], want[i])
+		}
+	}
+	if got := len(driver.GetDevices()); got != 0 {
+		t.Errorf("esperado 0 dispositivos ao fim da captura, obtido %d", got)
+	}
+}
+
+func TestReplayDriverStop(t *testing.T) {
+	start := time.Now()
+	records := []hidtransport.Record{
+		{Time: start, Type: hidtransport.RecordMeta},
+		{Time: start.Add(time.Hour), Type: hidtransport.RecordDetach, Path: "x"},
+	}
+
+	driver := NewReplayDriver(records, 1)
+	driver.Start()
+
+	stopped := make(chan struct{})
+	go func() {
+		driver.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Stop() não interrompeu a espera entre registros")
+	}
+	if err := driver.Start(); err == nil {
+		t.Error("Start() após o fim da reprodução deveria falhar")
+	}
+}
@@ -55,6 +55,7 @@ type SDKDriver struct {
 	onDeviceDisconnected func(event DeviceEvent)
 	onButtonEvent        func(event ButtonEvent)
 	onBatteryUpdate      func(deviceID uint16, status BatteryStatus)
+	onWearEvent          func(event WearEvent)
 }
 
 // NewSDKDriver cria uma nova instância do driver SDK para Windows
@@ -156,11 +157,30 @@ func (d *SDKDriver) enumerateDevices() {
 			info.SerialNumber = C.GoString(serial)
 			C.Jabra_FreeString(serial)
 		}
+		defaultCatalog.Enrich(info)
 
 		d.devices[info.ID] = info
 	}
 }
 
+// sdkUSBIDs obtém Vendor/Product ID USB do dispositivo pela lista de
+// Jabra_GetAttachedJabraDevices; retorna zeros se ele não estiver na lista
+func sdkUSBIDs(deviceID uint16) (vendorID, productID uint16) {
+	var count C.int
+	devices := C.Jabra_GetAttachedJabraDevices(&count)
+	if devices == nil || count == 0 {
+		return 0, 0
+	}
+
+	for _, dev := range unsafe.Slice(devices, int(count)) {
+		if uint16(dev.deviceID) == deviceID {
+			vendorID, productID = uint16(dev.vendorID), uint16(dev.productID)
+		}
+		C.Jabra_FreeDeviceInfo(dev)
+	}
+	return vendorID, productID
+}
+
 // GetDevices retorna lista de dispositivos conectados
 func (d *SDKDriver) GetDevices() []DeviceInfo {
 	d.mu.RLock()
@@ -196,8 +216,8 @@ func (d *SDKDriver) GetBatteryStatus(deviceID uint16) (*BatteryStatus, error) {
 
 	var status C.Jabra_BatteryStatus
 	result := C.Jabra_GetBatteryStatus(C.Jabra_DeviceID(deviceID), &status)
-	if result != C.JABRA_SUCCESS {
-		return nil, fmt.Errorf("GetBatteryStatus failed: %d", result)
+	if err := sdkError("GetBatteryStatus", CapabilityBattery, deviceID, result); err != nil {
+		return nil, err
 	}
 
 	return &BatteryStatus{
@@ -207,6 +227,45 @@ func (d *SDKDriver) GetBatteryStatus(deviceID uint16) (*BatteryStatus, error) {
 	}, nil
 }
 
+// sdkError converte o código de retorno do SDK; JABRA_ERROR_NOT_SUPPORTED
+// vira ErrNotSupported para a capacidade informada
+func sdkError(op string, capability Capability, deviceID uint16, result C.Jabra_ReturnCode) error {
+	switch result {
+	case C.JABRA_SUCCESS:
+		return nil
+	case C.JABRA_ERROR_NOT_SUPPORTED:
+		return notSupported(capability, deviceID)
+	}
+	return fmt.Errorf("%s failed: %d", op, result)
+}
+
+// Capabilities consulta o SDK; volume e bateria são sondados pelas leituras,
+// já que o SDK só sinaliza a falta de suporte pelo retorno. Configurações não
+// são suportadas pelo driver SDK
+func (d *SDKDriver) Capabilities(deviceID uint16) (Capabilities, error) {
+	if _, err := d.GetDevice(deviceID); err != nil {
+		return Capabilities{}, err
+	}
+
+	id := C.Jabra_DeviceID(deviceID)
+	caps := Capabilities{
+		Mute:      C.Jabra_IsMuteSupported(id) != 0,
+		Ringer:    C.Jabra_IsRingerSupported(id) != 0,
+		Hook:      C.Jabra_IsOffHookSupported(id) != 0,
+		Busylight: C.Jabra_IsBusylightSupported(id) != 0,
+		Hold:      C.Jabra_IsHoldSupported(id) != 0,
+	}
+
+	var volume C.int
+	caps.Volume = C.Jabra_GetVolume(id, &volume) != C.JABRA_ERROR_NOT_SUPPORTED
+	// O firmware aplica os botões de volume no próprio headset
+	caps.VolumeButtons = caps.Volume
+
+	var battery C.Jabra_BatteryStatus
+	caps.Battery = C.Jabra_GetBatteryStatus(id, &battery) != C.JABRA_ERROR_NOT_SUPPORTED
+	return caps, nil
+}
+
 // SetMute define estado do mute
 func (d *SDKDriver) SetMute(deviceID uint16, mute bool) error {
 	muteVal := C.int(0)
@@ -215,18 +274,15 @@ func (d *SDKDriver) SetMute(deviceID uint16, mute bool) error {
 	}
 
 	result := C.Jabra_SetMute(C.Jabra_DeviceID(deviceID), muteVal)
-	if result != C.JABRA_SUCCESS {
-		return fmt.Errorf("SetMute failed: %d", result)
-	}
-	return nil
+	return sdkError("SetMute", CapabilityMute, deviceID, result)
 }
 
 // GetMute obtém estado do mute
 func (d *SDKDriver) GetMute(deviceID uint16) (bool, error) {
 	var mute C.int
 	result := C.Jabra_GetMute(C.Jabra_DeviceID(deviceID), &mute)
-	if result != C.JABRA_SUCCESS {
-		return false, fmt.Errorf("GetMute failed: %d", result)
+	if err := sdkError("GetMute", CapabilityMute, deviceID, result); err != nil {
+		return false, err
 	}
 	return mute != 0, nil
 }
@@ -239,10 +295,7 @@ func (d *SDKDriver) SetRinger(deviceID uint16, ring bool) error {
 	}
 
 	result := C.Jabra_SetRinger(C.Jabra_DeviceID(deviceID), ringVal)
-	if result != C.JABRA_SUCCESS {
-		return fmt.Errorf("SetRinger failed: %d", result)
-	}
-	return nil
+	return sdkError("SetRinger", CapabilityRinger, deviceID, result)
 }
 
 // SetHookState define estado do hook
@@ -253,10 +306,7 @@ func (d *SDKDriver) SetHookState(deviceID uint16, offHook bool) error {
 	}
 
 	result := C.Jabra_SetHookState(C.Jabra_DeviceID(deviceID), hookVal)
-	if result != C.JABRA_SUCCESS {
-		return fmt.Errorf("SetHookState failed: %d", result)
-	}
-	return nil
+	return sdkError("SetHookState", CapabilityHook, deviceID, result)
 }
 
 // SetBusylight define estado do LED
@@ -267,10 +317,7 @@ func (d *SDKDriver) SetBusylight(deviceID uint16, on bool) error {
 	}
 
 	result := C.Jabra_SetBusylightState(C.Jabra_DeviceID(deviceID), onVal)
-	if result != C.JABRA_SUCCESS {
-		return fmt.Errorf("SetBusylightState failed: %d", result)
-	}
-	return nil
+	return sdkError("SetBusylightState", CapabilityBusylight, deviceID, result)
 }
 
 // SetHold define estado de hold
@@ -281,10 +328,7 @@ func (d *SDKDriver) SetHold(deviceID uint16, hold bool) error {
 	}
 
 	result := C.Jabra_SetHold(C.Jabra_DeviceID(deviceID), holdVal)
-	if result != C.JABRA_SUCCESS {
-		return fmt.Errorf("SetHold failed: %d", result)
-	}
-	return nil
+	return sdkError("SetHold", CapabilityHold, deviceID, result)
 }
 
 // SetVolume define volume do dispositivo
@@ -297,22 +341,51 @@ func (d *SDKDriver) SetVolume(deviceID uint16, volume int) error {
 	}
 
 	result := C.Jabra_SetVolume(C.Jabra_DeviceID(deviceID), C.int(volume))
-	if result != C.JABRA_SUCCESS {
-		return fmt.Errorf("SetVolume failed: %d", result)
-	}
-	return nil
+	return sdkError("SetVolume", CapabilityVolume, deviceID, result)
 }
 
 // GetVolume obtém volume do dispositivo
 func (d *SDKDriver) GetVolume(deviceID uint16) (int, error) {
 	var volume C.int
 	result := C.Jabra_GetVolume(C.Jabra_DeviceID(deviceID), &volume)
-	if result != C.JABRA_SUCCESS {
-		return 0, fmt.Errorf("GetVolume failed: %d", result)
+	if err := sdkError("GetVolume", CapabilityVolume, deviceID, result); err != nil {
+		return 0, err
 	}
 	return int(volume), nil
 }
 
+// GetWearState não é suportado: a libjabra.dll distribuída não exporta a
+// leitura nem o callback do sensor de uso
+func (d *SDKDriver) GetWearState(deviceID uint16) (bool, error) {
+	return false, notSupported(CapabilityWear, deviceID)
+}
+
+// ListSettings não é suportado: a libjabra.dll distribuída não exporta
+// Jabra_SetSetting e o layout de Jabra_GetSettings não está declarado em
+// JabraSDK.h, então as configurações ficam indisponíveis no driver SDK
+func (d *SDKDriver) ListSettings(deviceID uint16) ([]Setting, error) {
+	if _, err := d.GetDevice(deviceID); err != nil {
+		return nil, err
+	}
+	return nil, notSupported(CapabilitySettings, deviceID)
+}
+
+// GetSetting não é suportado (veja ListSettings)
+func (d *SDKDriver) GetSetting(deviceID uint16, key string) (Setting, error) {
+	if _, err := d.GetDevice(deviceID); err != nil {
+		return Setting{}, err
+	}
+	return Setting{}, notSupported(CapabilitySettings, deviceID)
+}
+
+// SetSetting não é suportado (veja ListSettings)
+func (d *SDKDriver) SetSetting(deviceID uint16, key string, value any) error {
+	if _, err := d.GetDevice(deviceID); err != nil {
+		return err
+	}
+	return notSupported(CapabilitySettings, deviceID)
+}
+
 // OnDeviceConnected registra callback
 func (d *SDKDriver) OnDeviceConnected(handler func(event DeviceEvent)) {
 	d.mu.Lock()
@@ -341,6 +414,13 @@ func (d *SDKDriver) OnBatteryUpdate(handler func(deviceID uint16, status Battery
 	d.onBatteryUpdate = handler
 }
 
+// OnWearEvent registra callback; o driver SDK não emite eventos de uso
+func (d *SDKDriver) OnWearEvent(handler func(event WearEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onWearEvent = handler
+}
+
 // =============================================================================
 // Callbacks exportados para C
 // =============================================================================
@@ -374,6 +454,8 @@ func goOnDeviceAttached(deviceID C.ushort) {
 		info.SerialNumber = C.GoString(serial)
 		C.Jabra_FreeString(serial)
 	}
+	info.VendorID, info.ProductID = sdkUSBIDs(info.ID)
+	defaultCatalog.Enrich(info)
 
 	driver.devices[info.ID] = info
 	handler := driver.onDeviceConnected
@@ -426,14 +508,24 @@ func goOnButtonEvent(deviceID C.ushort, buttonID C.int, value C.int) {
 		return
 	}
 
-	driver.mu.RLock()
+	// Online/Offline do dongle sinalizam o rádio com o headset pareado
+	button := ButtonID(buttonID)
+	driver.mu.Lock()
+	if dev, ok := driver.devices[uint16(deviceID)]; ok && value != 0 {
+		switch button {
+		case ButtonOnline:
+			dev.LinkState = LinkConnected
+		case ButtonOffline:
+			dev.LinkState = LinkOutOfRange
+		}
+	}
 	handler := driver.onButtonEvent
-	driver.mu.RUnlock()
+	driver.mu.Unlock()
 
 	if handler != nil {
 		handler(ButtonEvent{
 			DeviceID: uint16(deviceID),
-			ButtonID: ButtonID(buttonID),
+			ButtonID: button,
 			Pressed:  value != 0,
 		})
 	}
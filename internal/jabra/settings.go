@@ -0,0 +1,115 @@
+package jabra
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// SettingType é o tipo de valor de uma configuração do dispositivo
+type SettingType string
+
+const (
+	SettingBool SettingType = "bool" // Valor bool
+	SettingInt  SettingType = "int"  // Valor int entre Min e Max
+	SettingEnum SettingType = "enum" // Valor string entre Options
+)
+
+// Chaves das configurações comuns aos headsets Jabra
+const (
+	SettingSidetone     = "sidetone"
+	SettingRingtone     = "ringtone"
+	SettingAutoAnswer   = "auto_answer"
+	SettingMuteReminder = "mute_reminder"
+)
+
+// SettingDescriptor descreve uma configuração e os valores aceitos
+type SettingDescriptor struct {
+	Key     string      `json:"key"`
+	Name    string      `json:"name"`
+	Type    SettingType `json:"type"`
+	Min     int         `json:"min,omitempty"`
+	Max     int         `json:"max,omitempty"`
+	Options []string    `json:"options,omitempty"`
+}
+
+// Setting é uma configuração com o valor atual (bool, int ou string)
+type Setting struct {
+	SettingDescriptor
+	Value any `json:"value"`
+}
+
+var (
+	// ErrUnknownSetting indica uma chave que o dispositivo não possui
+	ErrUnknownSetting = errors.New("unknown setting")
+
+	// ErrInvalidSettingValue indica um valor fora do tipo ou dos limites
+	ErrInvalidSettingValue = errors.New("invalid setting value")
+)
+
+// Normalize valida o valor para o descritor e o converte para o tipo Go
+// da configuração. Números vindos de JSON (float64) são aceitos como int.
+func (d SettingDescriptor) Normalize(value any) (any, error) {
+	switch d.Type {
+	case SettingBool:
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case SettingInt:
+		var n int
+		switch v := value.(type) {
+		case int:
+			n = v
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("%w: %s expects an integer", ErrInvalidSettingValue, d.Key)
+			}
+			n = int(v)
+		default:
+			return nil, fmt.Errorf("%w: %s expects an integer", ErrInvalidSettingValue, d.Key)
+		}
+		if n < d.Min || n > d.Max {
+			return nil, fmt.Errorf("%w: %s must be between %d and %d", ErrInvalidSettingValue, d.Key, d.Min, d.Max)
+		}
+		return n, nil
+	case SettingEnum:
+		if s, ok := value.(string); ok {
+			for _, option := range d.Options {
+				if option == s {
+					return s, nil
+				}
+			}
+			return nil, fmt.Errorf("%w: %s must be one of %v", ErrInvalidSettingValue, d.Key, d.Options)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s expects %s", ErrInvalidSettingValue, d.Key, d.Type)
+}
+
+// SettingResult é o resultado da aplicação de uma configuração de um perfil
+type SettingResult struct {
+	Key     string `json:"key"`
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ApplySettings aplica um perfil (chave → valor) ao dispositivo, em ordem
+// de chave. Falhas não interrompem as demais configurações.
+func ApplySettings(driver Driver, deviceID uint16, profile map[string]any) []SettingResult {
+	keys := make([]string, 0, len(profile))
+	for key := range profile {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	results := make([]SettingResult, 0, len(keys))
+	for _, key := range keys {
+		result := SettingResult{Key: key, Applied: true}
+		if err := driver.SetSetting(deviceID, key, profile[key]); err != nil {
+			result.Applied = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
@@ -0,0 +1,75 @@
+package jabra
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSettingNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		desc    SettingDescriptor
+		value   any
+		want    any
+		wantErr bool
+	}{
+		{"bool", SettingDescriptor{Key: "a", Type: SettingBool}, true, true, false},
+		{"bool inválido", SettingDescriptor{Key: "a", Type: SettingBool}, "true", nil, true},
+		{"int de JSON", SettingDescriptor{Key: "b", Type: SettingInt, Min: 1, Max: 5}, float64(3), 3, false},
+		{"int fracionário", SettingDescriptor{Key: "b", Type: SettingInt, Min: 1, Max: 5}, 2.5, nil, true},
+		{"int fora do limite", SettingDescriptor{Key: "b", Type: SettingInt, Min: 1, Max: 5}, 6, nil, true},
+		{"enum", SettingDescriptor{Key: "c", Type: SettingEnum, Options: []string{"off", "low"}}, "low", "low", false},
+		{"enum desconhecido", SettingDescriptor{Key: "c", Type: SettingEnum, Options: []string{"off", "low"}}, "max", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.desc.Normalize(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSettingValue) {
+					t.Errorf("Normalize(%v) = %v, want ErrInvalidSettingValue", tt.value, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Normalize(%v) = %v, %v; want %v", tt.value, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimulationSettings(t *testing.T) {
+	driver := NewSimulationDriver(nil)
+	if err := driver.Execute(SimCommand{Action: SimAttach, Device: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := driver.ListSettings(1)
+	if err != nil || len(settings) != 4 {
+		t.Fatalf("ListSettings() = %d configurações, %v", len(settings), err)
+	}
+
+	if err := driver.SetSetting(1, SettingSidetone, "low"); err != nil {
+		t.Fatalf("SetSetting() erro inesperado: %v", err)
+	}
+	if setting, _ := driver.GetSetting(1, SettingSidetone); setting.Value != "low" {
+		t.Errorf("sidetone = %v, want low", setting.Value)
+	}
+	if err := driver.SetSetting(1, SettingRingtone, 9); !errors.Is(err, ErrInvalidSettingValue) {
+		t.Errorf("SetSetting(ringtone 9) = %v, want ErrInvalidSettingValue", err)
+	}
+	if _, err := driver.GetSetting(1, "equalizer"); !errors.Is(err, ErrUnknownSetting) {
+		t.Errorf("GetSetting(equalizer) = %v, want ErrUnknownSetting", err)
+	}
+
+	results := ApplySettings(driver, 1, map[string]any{
+		SettingAutoAnswer:   true,
+		SettingMuteReminder: "yes",
+	})
+	if len(results) != 2 || !results[0].Applied || results[1].Applied || results[1].Error == "" {
+		t.Errorf("ApplySettings() = %+v", results)
+	}
+	if state := driver.State(); state[0].Settings[SettingAutoAnswer] != true {
+		t.Errorf("estado simulado deveria refletir o perfil: %+v", state[0].Settings)
+	}
+}
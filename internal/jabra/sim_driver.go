@@ -0,0 +1,832 @@
+package jabra
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Ações aceitas em cenários e comandos do SimulationDriver
+const (
+	SimAttach       = "attach"        // Conecta um dispositivo simulado
+	SimDetach       = "detach"        // Desconecta o dispositivo
+	SimPress        = "press"         // Pressiona um botão
+	SimRelease      = "release"       // Libera um botão
+	SimClick        = "click"         // Pressiona e libera um botão
+	SimBattery      = "battery"       // Define o nível da bateria
+	SimBatteryCurve = "battery_curve" // Varia a bateria de From até To ao longo de Duration
+	SimCharging     = "charging"      // Liga/desliga o carregamento
+	SimLink         = "link"          // Rádio do dongle com o headset (Linked)
+	SimWear         = "wear"          // Headset colocado/retirado (Worn)
+)
+
+// defaultCurveInterval é o intervalo entre leituras de uma battery_curve
+const defaultCurveInterval = 10 * time.Second
+
+// SimCommand é um passo de cenário ou um comando ao vivo (POST /api/sim)
+type SimCommand struct {
+	At        string  `json:"at,omitempty"` // Deslocamento desde o início do cenário ("1m30s")
+	Action    string  `json:"action"`
+	Device    uint16  `json:"device,omitempty"`
+	Name      string  `json:"name,omitempty"`
+	Serial    string  `json:"serial,omitempty"`
+	ProductID uint16  `json:"product_id,omitempty"`
+	Dongle    bool    `json:"dongle,omitempty"`
+	Parent    *uint16 `json:"parent,omitempty"` // Dongle ao qual o headset está pareado
+	Linked    bool    `json:"linked,omitempty"` // Headset ao alcance (action link)
+	Worn      bool    `json:"worn,omitempty"`   // Headset em uso (action wear)
+	Button    string  `json:"button,omitempty"`
+	Absolute  bool    `json:"absolute,omitempty"` // Botão como controle on/off absoluto
+	Level     int     `json:"level,omitempty"`
+	From      int     `json:"from,omitempty"`
+	To        int     `json:"to,omitempty"`
+	Duration  string  `json:"duration,omitempty"`
+	Interval  string  `json:"interval,omitempty"`
+	Charging  bool    `json:"charging,omitempty"`
+}
+
+// SimScenario é uma linha do tempo de comandos simulados
+type SimScenario struct {
+	Name  string       `json:"name"`
+	Loop  bool         `json:"loop"`
+	Steps []SimCommand `json:"steps"`
+}
+
+// LoadSimScenario lê um cenário JSON do disco
+func LoadSimScenario(path string) (*SimScenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario SimScenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("parse scenario %s: %w", path, err)
+	}
+	if _, err := scenario.timeline(); err != nil {
+		return nil, fmt.Errorf("scenario %s: %w", path, err)
+	}
+	return &scenario, nil
+}
+
+// errAlreadyAttached é ignorado ao repetir cenários em loop
+var errAlreadyAttached = errors.New("device already attached")
+
+// DefaultSimScenario reproduz o antigo modo simulação: um headset que
+// descarrega 1% a cada 30 segundos e recarrega ao esgotar.
+func DefaultSimScenario() *SimScenario {
+	return &SimScenario{
+		Name: "default",
+		Loop: true,
+		Steps: []SimCommand{
+			{At: "0s", Action: SimAttach, Name: "Jabra Simulado"},
+			{At: "0s", Action: SimBatteryCurve, From: 100, To: 0, Duration: "50m", Interval: "30s"},
+			{At: "50m", Action: SimCharging, Charging: true},
+			{At: "50m", Action: SimBatteryCurve, From: 0, To: 100, Duration: "10m", Interval: "30s"},
+			{At: "60m", Action: SimCharging, Charging: false},
+		},
+	}
+}
+
+// timedCommand é um comando com deslocamento já interpretado
+type timedCommand struct {
+	offset time.Duration
+	cmd    SimCommand
+}
+
+// timeline valida o cenário e o expande em comandos ordenados por tempo.
+// Curvas de bateria viram uma sequência de comandos battery.
+func (s *SimScenario) timeline() ([]timedCommand, error) {
+	var steps []timedCommand
+	for i, cmd := range s.Steps {
+		var offset time.Duration
+		if cmd.At != "" {
+			var err error
+			if offset, err = time.ParseDuration(cmd.At); err != nil || offset < 0 {
+				return nil, fmt.Errorf("step %d: invalid at %q", i+1, cmd.At)
+			}
+		}
+
+		expanded, err := cmd.expand()
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		for _, e := range expanded {
+			e.offset += offset
+			steps = append(steps, e)
+		}
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].offset < steps[j].offset
+	})
+	return steps, nil
+}
+
+// expand valida o comando e retorna os passos relativos ao seu início
+func (c SimCommand) expand() ([]timedCommand, error) {
+	switch c.Action {
+	case SimAttach, SimDetach, SimCharging, SimLink, SimWear:
+	case SimPress, SimRelease, SimClick:
+		if _, ok := ParseButtonID(c.Button); !ok {
+			return nil, fmt.Errorf("unknown button %q", c.Button)
+		}
+	case SimBattery:
+		if c.Level < 0 || c.Level > 100 {
+			return nil, fmt.Errorf("battery level %d out of range", c.Level)
+		}
+	case SimBatteryCurve:
+		return c.expandCurve()
+	default:
+		return nil, fmt.Errorf("unknown action %q", c.Action)
+	}
+	return []timedCommand{{cmd: c}}, nil
+}
+
+// expandCurve converte uma battery_curve em leituras periódicas
+func (c SimCommand) expandCurve() ([]timedCommand, error) {
+	if c.From < 0 || c.From > 100 || c.To < 0 || c.To > 100 {
+		return nil, fmt.Errorf("battery curve %d..%d out of range", c.From, c.To)
+	}
+	duration, err := time.ParseDuration(c.Duration)
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("invalid battery curve duration %q", c.Duration)
+	}
+	interval := defaultCurveInterval
+	if c.Interval != "" {
+		if interval, err = time.ParseDuration(c.Interval); err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid battery curve interval %q", c.Interval)
+		}
+	}
+
+	n := int(duration / interval)
+	if n < 1 {
+		n = 1
+	}
+	steps := make([]timedCommand, 0, n+1)
+	for i := 0; i <= n; i++ {
+		level := c.From + (c.To-c.From)*i/n
+		steps = append(steps, timedCommand{
+			offset: time.Duration(i) * duration / time.Duration(n),
+			cmd:    SimCommand{Action: SimBattery, Device: c.Device, Level: level},
+		})
+	}
+	return steps, nil
+}
+
+// simDevice é o estado de um dispositivo simulado
+type simDevice struct {
+	info      DeviceInfo
+	battery   BatteryStatus
+	muted     bool
+	ringing   bool
+	offHook   bool
+	busylight bool
+	hold      bool
+	volume    int
+	worn      bool
+	settings  []Setting
+}
+
+// SimDeviceState é o estado de um dispositivo simulado exposto em GET /api/sim
+type SimDeviceState struct {
+	ID        uint16         `json:"id"`
+	Name      string         `json:"name"`
+	Serial    string         `json:"serial"`
+	Battery   int            `json:"battery"`
+	Charging  bool           `json:"charging"`
+	Muted     bool           `json:"muted"`
+	Ringing   bool           `json:"ringing"`
+	OffHook   bool           `json:"off_hook"`
+	Busylight bool           `json:"busylight"`
+	Hold      bool           `json:"hold"`
+	Volume    int            `json:"volume"`
+	Worn      bool           `json:"worn"`
+	Settings  map[string]any `json:"settings"`
+}
+
+// SimulationDriver implementa Driver sem hardware. Dispositivos, botões e
+// bateria são controlados por cenários JSON e por comandos ao vivo.
+type SimulationDriver struct {
+	mu       sync.RWMutex
+	scenario *SimScenario
+	running  bool
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+
+	// Dispositivos simulados conectados
+	devices map[uint16]*simDevice
+
+	// Callbacks
+	onDeviceConnected    func(event DeviceEvent)
+	onDeviceDisconnected func(event DeviceEvent)
+	onButtonEvent        func(event ButtonEvent)
+	onBatteryUpdate      func(deviceID uint16, status BatteryStatus)
+	onWearEvent          func(event WearEvent)
+}
+
+// NewSimulationDriver cria o driver simulado. O cenário (opcional) é
+// executado a partir de Start; sem cenário o driver aguarda comandos.
+func NewSimulationDriver(scenario *SimScenario) *SimulationDriver {
+	return &SimulationDriver{
+		scenario: scenario,
+		stopCh:   make(chan struct{}),
+		devices:  make(map[uint16]*simDevice),
+	}
+}
+
+// Start inicia o driver e o cenário configurado
+func (d *SimulationDriver) Start() error {
+	d.mu.Lock()
+	if d.running {
+		d.mu.Unlock()
+		return errors.New("driver already running")
+	}
+	d.running = true
+	d.stopCh = make(chan struct{})
+	scenario := d.scenario
+	d.mu.Unlock()
+
+	log.Println("[Sim Driver] Iniciado")
+	if scenario != nil {
+		return d.RunScenario(scenario)
+	}
+	return nil
+}
+
+// Stop interrompe cenários em andamento
+func (d *SimulationDriver) Stop() error {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return nil
+	}
+	d.running = false
+	close(d.stopCh)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	log.Println("[Sim Driver] Parado")
+	return nil
+}
+
+// IsRunning retorna se o driver está ativo
+func (d *SimulationDriver) IsRunning() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.running
+}
+
+// RunScenario executa um cenário em paralelo aos já em andamento
+func (d *SimulationDriver) RunScenario(scenario *SimScenario) error {
+	steps, err := scenario.timeline()
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running {
+		return errors.New("simulation driver not running")
+	}
+
+	log.Printf("[Sim Driver] Executando cenário %q (%d passos)", scenario.Name, len(steps))
+	d.wg.Add(1)
+	go d.runTimeline(steps, scenario.Loop, d.stopCh)
+	return nil
+}
+
+// Execute aplica um comando ao vivo. Curvas de bateria rodam em segundo plano.
+func (d *SimulationDriver) Execute(cmd SimCommand) error {
+	steps, err := cmd.expand()
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 1 && steps[0].offset == 0 {
+		return d.apply(steps[0].cmd)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running {
+		return errors.New("simulation driver not running")
+	}
+	d.wg.Add(1)
+	go d.runTimeline(steps, false, d.stopCh)
+	return nil
+}
+
+// runTimeline aplica os passos nos deslocamentos programados
+func (d *SimulationDriver) runTimeline(steps []timedCommand, loop bool, stopCh chan struct{}) {
+	defer d.wg.Done()
+
+	// Um cenário em loop sem duração giraria sem pausa
+	if loop && (len(steps) == 0 || steps[len(steps)-1].offset == 0) {
+		log.Println("[Sim Driver] Cenário em loop sem duração, executando uma vez")
+		loop = false
+	}
+
+	for iteration := 0; ; iteration++ {
+		start := time.Now()
+		for _, step := range steps {
+			if wait := time.Until(start.Add(step.offset)); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-stopCh:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			err := d.apply(step.cmd)
+			if errors.Is(err, errAlreadyAttached) && iteration > 0 {
+				continue
+			}
+			if err != nil {
+				log.Printf("[Sim Driver] Passo %s ignorado: %v", step.cmd.Action, err)
+			}
+		}
+		if !loop {
+			return
+		}
+	}
+}
+
+// apply executa um comando simples e dispara os callbacks correspondentes
+func (d *SimulationDriver) apply(cmd SimCommand) error {
+	switch cmd.Action {
+	case SimAttach:
+		return d.attach(cmd)
+	case SimDetach:
+		return d.detach(cmd.Device)
+	case SimPress, SimRelease, SimClick:
+		return d.button(cmd)
+	case SimBattery, SimCharging:
+		return d.battery(cmd)
+	case SimLink:
+		return d.link(cmd)
+	case SimWear:
+		return d.wear(cmd)
+	}
+	return fmt.Errorf("unknown action %q", cmd.Action)
+}
+
+func (d *SimulationDriver) attach(cmd SimCommand) error {
+	d.mu.Lock()
+	if _, exists := d.devices[cmd.Device]; exists {
+		d.mu.Unlock()
+		return fmt.Errorf("device %d: %w", cmd.Device, errAlreadyAttached)
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = "Jabra Simulado"
+	}
+	serial := cmd.Serial
+	if serial == "" {
+		serial = fmt.Sprintf("SIM-%06d", 123456+int(cmd.Device))
+	}
+	dev := &simDevice{
+		info: DeviceInfo{
+			ID:           cmd.Device,
+			Name:         name,
+			SerialNumber: serial,
+			VendorID:     JabraVID,
+			ProductID:    cmd.ProductID,
+			IsDongle:     cmd.Dongle,
+			Connected:    true,
+			ConnectedAt:  time.Now(),
+		},
+		battery:  BatteryStatus{Level: 100},
+		volume:   50,
+		worn:     true,
+		settings: defaultSimSettings(),
+	}
+	defaultCatalog.Enrich(&dev.info)
+	if cmd.Parent != nil {
+		parentID := *cmd.Parent
+		parent, ok := d.devices[parentID]
+		if !ok {
+			d.mu.Unlock()
+			return fmt.Errorf("parent device %d not attached", parentID)
+		}
+		dev.info.ParentID = &parentID
+		dev.info.LinkState = LinkConnected
+		parent.info.LinkState = LinkConnected
+	}
+	d.devices[cmd.Device] = dev
+	info := dev.info
+	handler := d.onDeviceConnected
+	d.mu.Unlock()
+
+	log.Printf("[Sim Driver] Dispositivo conectado: %s (ID: %d)", info.Name, info.ID)
+	if handler != nil {
+		handler(DeviceEvent{DeviceID: info.ID, Connected: true, Device: &info})
+	}
+	return nil
+}
+
+func (d *SimulationDriver) detach(deviceID uint16) error {
+	d.mu.Lock()
+	dev, ok := d.devices[deviceID]
+	if !ok {
+		d.mu.Unlock()
+		return fmt.Errorf("device %d not attached", deviceID)
+	}
+	delete(d.devices, deviceID)
+	info := dev.info
+	info.Connected = false
+	handler := d.onDeviceDisconnected
+	d.mu.Unlock()
+
+	log.Printf("[Sim Driver] Dispositivo desconectado: %s (ID: %d)", info.Name, info.ID)
+	if handler != nil {
+		handler(DeviceEvent{DeviceID: deviceID, Connected: false, Device: &info})
+	}
+
+	// Sem o dongle, os headsets pareados também somem
+	for _, child := range d.children(deviceID) {
+		d.detach(child)
+	}
+	return nil
+}
+
+// children retorna os headsets pareados a um dongle, ordenados por ID
+func (d *SimulationDriver) children(parentID uint16) []uint16 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var ids []uint16
+	for id, dev := range d.devices {
+		if dev.info.ParentID != nil && *dev.info.ParentID == parentID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// link altera o rádio do dongle com o headset. Fora de alcance, os headsets
+// pareados são desconectados antes do Offline, como no SDK.
+func (d *SimulationDriver) link(cmd SimCommand) error {
+	d.mu.Lock()
+	dev, ok := d.devices[cmd.Device]
+	if !ok {
+		d.mu.Unlock()
+		return fmt.Errorf("device %d not attached", cmd.Device)
+	}
+	button := ButtonOnline
+	dev.info.LinkState = LinkConnected
+	if !cmd.Linked {
+		button = ButtonOffline
+		dev.info.LinkState = LinkOutOfRange
+	}
+	handler := d.onButtonEvent
+	d.mu.Unlock()
+
+	if !cmd.Linked {
+		for _, child := range d.children(cmd.Device) {
+			d.detach(child)
+		}
+	}
+	if handler != nil {
+		handler(ButtonEvent{DeviceID: cmd.Device, ButtonID: button, Pressed: true})
+		handler(ButtonEvent{DeviceID: cmd.Device, ButtonID: button})
+	}
+	return nil
+}
+
+// wear coloca ou retira o headset; repetir o estado atual não gera evento
+func (d *SimulationDriver) wear(cmd SimCommand) error {
+	d.mu.Lock()
+	dev, ok := d.devices[cmd.Device]
+	if !ok {
+		d.mu.Unlock()
+		return fmt.Errorf("device %d not attached", cmd.Device)
+	}
+	changed := dev.worn != cmd.Worn
+	dev.worn = cmd.Worn
+	handler := d.onWearEvent
+	d.mu.Unlock()
+
+	if changed && handler != nil {
+		handler(WearEvent{DeviceID: cmd.Device, Worn: cmd.Worn})
+	}
+	return nil
+}
+
+func (d *SimulationDriver) button(cmd SimCommand) error {
+	button, _ := ParseButtonID(cmd.Button)
+
+	d.mu.Lock()
+	dev, ok := d.devices[cmd.Device]
+	if !ok {
+		d.mu.Unlock()
+		return fmt.Errorf("device %d not attached", cmd.Device)
+	}
+
+	// O botão de mute altera o estado do dispositivo, como no hardware
+	if button == ButtonMute {
+		switch {
+		case cmd.Absolute:
+			dev.muted = cmd.Action != SimRelease
+		case cmd.Action != SimRelease:
+			dev.muted = !dev.muted
+		}
+	}
+	handler := d.onButtonEvent
+	d.mu.Unlock()
+
+	if handler == nil {
+		return nil
+	}
+	event := ButtonEvent{DeviceID: cmd.Device, ButtonID: button, Absolute: cmd.Absolute}
+	switch cmd.Action {
+	case SimPress:
+		event.Pressed = true
+		handler(event)
+	case SimRelease:
+		handler(event)
+	case SimClick:
+		event.Pressed = true
+		handler(event)
+		event.Pressed = false
+		handler(event)
+	}
+	return nil
+}
+
+func (d *SimulationDriver) battery(cmd SimCommand) error {
+	d.mu.Lock()
+	dev, ok := d.devices[cmd.Device]
+	if !ok {
+		d.mu.Unlock()
+		return fmt.Errorf("device %d not attached", cmd.Device)
+	}
+
+	if cmd.Action == SimCharging {
+		dev.battery.IsCharging = cmd.Charging
+	} else {
+		dev.battery.Level = cmd.Level
+	}
+	dev.battery.IsLow = dev.battery.Level < 20
+	status := dev.battery
+	handler := d.onBatteryUpdate
+	d.mu.Unlock()
+
+	if handler != nil {
+		handler(cmd.Device, status)
+	}
+	return nil
+}
+
+// State retorna o estado dos dispositivos simulados, ordenado por ID
+func (d *SimulationDriver) State() []SimDeviceState {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	states := make([]SimDeviceState, 0, len(d.devices))
+	for _, dev := range d.devices {
+		settings := make(map[string]any, len(dev.settings))
+		for _, setting := range dev.settings {
+			settings[setting.Key] = setting.Value
+		}
+		states = append(states, SimDeviceState{
+			ID:        dev.info.ID,
+			Name:      dev.info.Name,
+			Serial:    dev.info.SerialNumber,
+			Battery:   dev.battery.Level,
+			Charging:  dev.battery.IsCharging,
+			Muted:     dev.muted,
+			Ringing:   dev.ringing,
+			OffHook:   dev.offHook,
+			Busylight: dev.busylight,
+			Hold:      dev.hold,
+			Volume:    dev.volume,
+			Worn:      dev.worn,
+			Settings:  settings,
+		})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].ID < states[j].ID })
+	return states
+}
+
+// GetDevices retorna os dispositivos simulados conectados
+func (d *SimulationDriver) GetDevices() []DeviceInfo {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	devices := make([]DeviceInfo, 0, len(d.devices))
+	for _, dev := range d.devices {
+		devices = append(devices, dev.info)
+	}
+	return devices
+}
+
+// GetDevice retorna informações de um dispositivo simulado
+func (d *SimulationDriver) GetDevice(deviceID uint16) (*DeviceInfo, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	dev, ok := d.devices[deviceID]
+	if !ok {
+		return nil, fmt.Errorf("device %d not found", deviceID)
+	}
+	info := dev.info
+	return &info, nil
+}
+
+// Capabilities - o dispositivo simulado modela todas as operações
+func (d *SimulationDriver) Capabilities(deviceID uint16) (Capabilities, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if _, ok := d.devices[deviceID]; !ok {
+		return Capabilities{}, fmt.Errorf("device %d not found", deviceID)
+	}
+	return AllCapabilities(), nil
+}
+
+// GetBatteryStatus retorna a bateria simulada
+func (d *SimulationDriver) GetBatteryStatus(deviceID uint16) (*BatteryStatus, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	dev, ok := d.devices[deviceID]
+	if !ok {
+		return nil, fmt.Errorf("device %d not found", deviceID)
+	}
+	status := dev.battery
+	return &status, nil
+}
+
+// update altera o estado de um dispositivo simulado sob lock
+func (d *SimulationDriver) update(deviceID uint16, fn func(dev *simDevice)) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	dev, ok := d.devices[deviceID]
+	if !ok {
+		return fmt.Errorf("device %d not found", deviceID)
+	}
+	fn(dev)
+	return nil
+}
+
+// SetMute define o mute simulado
+func (d *SimulationDriver) SetMute(deviceID uint16, mute bool) error {
+	return d.update(deviceID, func(dev *simDevice) { dev.muted = mute })
+}
+
+// GetMute retorna o mute simulado
+func (d *SimulationDriver) GetMute(deviceID uint16) (bool, error) {
+	var muted bool
+	err := d.update(deviceID, func(dev *simDevice) { muted = dev.muted })
+	return muted, err
+}
+
+// SetRinger define o toque simulado
+func (d *SimulationDriver) SetRinger(deviceID uint16, ring bool) error {
+	return d.update(deviceID, func(dev *simDevice) { dev.ringing = ring })
+}
+
+// SetHookState define o hook simulado
+func (d *SimulationDriver) SetHookState(deviceID uint16, offHook bool) error {
+	return d.update(deviceID, func(dev *simDevice) { dev.offHook = offHook })
+}
+
+// SetBusylight define o LED de ocupado simulado
+func (d *SimulationDriver) SetBusylight(deviceID uint16, on bool) error {
+	return d.update(deviceID, func(dev *simDevice) { dev.busylight = on })
+}
+
+// SetHold define o hold simulado
+func (d *SimulationDriver) SetHold(deviceID uint16, hold bool) error {
+	return d.update(deviceID, func(dev *simDevice) { dev.hold = hold })
+}
+
+// SetVolume define o volume simulado (0-100)
+func (d *SimulationDriver) SetVolume(deviceID uint16, volume int) error {
+	if volume < 0 {
+		volume = 0
+	}
+	if volume > 100 {
+		volume = 100
+	}
+	return d.update(deviceID, func(dev *simDevice) { dev.volume = volume })
+}
+
+// GetVolume retorna o volume simulado
+func (d *SimulationDriver) GetVolume(deviceID uint16) (int, error) {
+	volume := -1
+	err := d.update(deviceID, func(dev *simDevice) { volume = dev.volume })
+	return volume, err
+}
+
+// GetWearState retorna o sensor de uso simulado
+func (d *SimulationDriver) GetWearState(deviceID uint16) (bool, error) {
+	var worn bool
+	err := d.update(deviceID, func(dev *simDevice) { worn = dev.worn })
+	return worn, err
+}
+
+// defaultSimSettings são as configurações de um headset simulado
+func defaultSimSettings() []Setting {
+	return []Setting{
+		{SettingDescriptor{Key: SettingSidetone, Name: "Sidetone", Type: SettingEnum, Options: []string{"off", "low", "medium", "high"}}, "medium"},
+		{SettingDescriptor{Key: SettingRingtone, Name: "Ring tone", Type: SettingInt, Min: 1, Max: 5}, 1},
+		{SettingDescriptor{Key: SettingAutoAnswer, Name: "Auto answer", Type: SettingBool}, false},
+		{SettingDescriptor{Key: SettingMuteReminder, Name: "Mute reminder", Type: SettingBool}, true},
+	}
+}
+
+// ListSettings retorna as configurações simuladas
+func (d *SimulationDriver) ListSettings(deviceID uint16) ([]Setting, error) {
+	var settings []Setting
+	err := d.update(deviceID, func(dev *simDevice) {
+		settings = append(settings, dev.settings...)
+	})
+	return settings, err
+}
+
+// GetSetting retorna uma configuração simulada
+func (d *SimulationDriver) GetSetting(deviceID uint16, key string) (Setting, error) {
+	settings, err := d.ListSettings(deviceID)
+	if err != nil {
+		return Setting{}, err
+	}
+	for _, setting := range settings {
+		if setting.Key == key {
+			return setting, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+}
+
+// SetSetting valida e altera uma configuração simulada
+func (d *SimulationDriver) SetSetting(deviceID uint16, key string, value any) error {
+	var err error
+	if updateErr := d.update(deviceID, func(dev *simDevice) {
+		for i := range dev.settings {
+			if dev.settings[i].Key != key {
+				continue
+			}
+			var normalized any
+			if normalized, err = dev.settings[i].Normalize(value); err == nil {
+				dev.settings[i].Value = normalized
+			}
+			return
+		}
+		err = fmt.Errorf("%w: %s", ErrUnknownSetting, key)
+	}); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+// OnDeviceConnected registra callback
+func (d *SimulationDriver) OnDeviceConnected(handler func(event DeviceEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onDeviceConnected = handler
+}
+
+// OnDeviceDisconnected registra callback
+func (d *SimulationDriver) OnDeviceDisconnected(handler func(event DeviceEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onDeviceDisconnected = handler
+}
+
+// OnButtonEvent registra callback
+func (d *SimulationDriver) OnButtonEvent(handler func(event ButtonEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onButtonEvent = handler
+}
+
+// OnBatteryUpdate registra callback
+func (d *SimulationDriver) OnBatteryUpdate(handler func(deviceID uint16, status BatteryStatus)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onBatteryUpdate = handler
+}
+
+// OnWearEvent registra callback
+func (d *SimulationDriver) OnWearEvent(handler func(event WearEvent)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onWearEvent = handler
+}
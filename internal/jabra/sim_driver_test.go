@@ -0,0 +1,200 @@
+package jabra
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSimScenarioTimeline(t *testing.T) {
+	scenario := &SimScenario{Steps: []SimCommand{
+		{At: "2s", Action: SimClick, Button: "Mute"},
+		{At: "0s", Action: SimAttach},
+		{At: "1s", Action: SimBatteryCurve, From: 100, To: 80, Duration: "2s", Interval: "1s"},
+	}}
+
+	steps, err := scenario.timeline()
+	if err != nil {
+		t.Fatalf("timeline() erro inesperado: %v", err)
+	}
+
+	want := []struct {
+		offset time.Duration
+		action string
+		level  int
+	}{
+		{0, SimAttach, 0},
+		{time.Second, SimBattery, 100},
+		{2 * time.Second, SimClick, 0},
+		{2 * time.Second, SimBattery, 90},
+		{3 * time.Second, SimBattery, 80},
+	}
+	if len(steps) != len(want) {
+		t.Fatalf("esperado %d passos, obtido %d: %+v", len(want), len(steps), steps)
+	}
+	for i, w := range want {
+		if steps[i].offset != w.offset || steps[i].cmd.Action != w.action || steps[i].cmd.Level != w.level {
+			t.Errorf("passo %d = %v %s %d, want %v %s %d", i, steps[i].offset, steps[i].cmd.Action, steps[i].cmd.Level, w.offset, w.action, w.level)
+		}
+	}
+}
+
+func TestSimScenarioValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  SimCommand
+	}{
+		{"ação desconhecida", SimCommand{Action: "explode"}},
+		{"botão desconhecido", SimCommand{Action: SimPress, Button: "Turbo"}},
+		{"bateria fora da faixa", SimCommand{Action: SimBattery, Level: 150}},
+		{"curva sem duração", SimCommand{Action: SimBatteryCurve, From: 100, To: 0}},
+		{"offset inválido", SimCommand{At: "ontem", Action: SimAttach}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario := &SimScenario{Steps: []SimCommand{tt.cmd}}
+			if _, err := scenario.timeline(); err == nil {
+				t.Error("timeline() deveria falhar")
+			}
+		})
+	}
+}
+
+func TestLoadSimScenarioExample(t *testing.T) {
+	files, _ := filepath.Glob(filepath.Join("..", "..", "config", "scenarios", "*.json"))
+	if len(files) == 0 {
+		t.Fatal("nenhum cenário de exemplo encontrado")
+	}
+	for _, file := range files {
+		scenario, err := LoadSimScenario(file)
+		if err != nil {
+			t.Fatalf("LoadSimScenario(%s) erro inesperado: %v", file, err)
+		}
+		if len(scenario.Steps) == 0 {
+			t.Errorf("cenário de exemplo %s sem passos", file)
+		}
+	}
+	if _, err := DefaultSimScenario().timeline(); err != nil {
+		t.Errorf("cenário padrão inválido: %v", err)
+	}
+}
+
+func TestSimulationDriverCommands(t *testing.T) {
+	driver := NewSimulationDriver(nil)
+
+	var mu sync.Mutex
+	var devices []DeviceEvent
+	var buttons []ButtonEvent
+	var battery []BatteryStatus
+	driver.OnDeviceConnected(func(event DeviceEvent) { mu.Lock(); devices = append(devices, event); mu.Unlock() })
+	driver.OnDeviceDisconnected(func(event DeviceEvent) { mu.Lock(); devices = append(devices, event); mu.Unlock() })
+	driver.OnButtonEvent(func(event ButtonEvent) { mu.Lock(); buttons = append(buttons, event); mu.Unlock() })
+	driver.OnBatteryUpdate(func(id uint16, status BatteryStatus) { mu.Lock(); battery = append(battery, status); mu.Unlock() })
+
+	if err := driver.Start(); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer driver.Stop()
+
+	if err := driver.Execute(SimCommand{Action: SimPress, Device: 3, Button: "Mute"}); err == nil {
+		t.Error("press em dispositivo não conectado deveria falhar")
+	}
+
+	commands := []SimCommand{
+		{Action: SimAttach, Device: 3, Serial: "SIM-3"},
+		{Action: SimClick, Device: 3, Button: "Mute"},
+		{Action: SimPress, Device: 3, Button: "HookSwitch", Absolute: true},
+		{Action: SimBattery, Device: 3, Level: 15},
+		{Action: SimCharging, Device: 3, Charging: true},
+	}
+	for _, cmd := range commands {
+		if err := driver.Execute(cmd); err != nil {
+			t.Fatalf("Execute(%+v) erro inesperado: %v", cmd, err)
+		}
+	}
+	if err := driver.Execute(SimCommand{Action: SimAttach, Device: 3}); err == nil {
+		t.Error("attach duplicado deveria falhar")
+	}
+
+	if muted, _ := driver.GetMute(3); !muted {
+		t.Error("click em Mute deveria alternar o mute simulado")
+	}
+	driver.SetRinger(3, true)
+	state := driver.State()
+	if len(state) != 1 || state[0].Serial != "SIM-3" || state[0].Battery != 15 || !state[0].Charging || !state[0].Ringing {
+		t.Errorf("estado simulado inesperado: %+v", state)
+	}
+
+	if err := driver.Execute(SimCommand{Action: SimDetach, Device: 3}); err != nil {
+		t.Fatalf("detach erro inesperado: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(devices) != 2 || !devices[0].Connected || devices[1].Connected {
+		t.Errorf("eventos de dispositivo inesperados: %+v", devices)
+	}
+	wantButtons := []ButtonEvent{
+		{DeviceID: 3, ButtonID: ButtonMute, Pressed: true},
+		{DeviceID: 3, ButtonID: ButtonMute, Pressed: false},
+		{DeviceID: 3, ButtonID: ButtonHookSwitch, Pressed: true, Absolute: true},
+	}
+	if len(buttons) != len(wantButtons) {
+		t.Fatalf("eventos de botão = %+v, want %+v", buttons, wantButtons)
+	}
+	for i := range wantButtons {
+		if buttons[i] != wantButtons[i] {
+			t.Errorf("evento %d = %+v, want %+v", i, buttons[i], wantButtons[i])
+		}
+	}
+	if len(battery) != 2 || !battery[0].IsLow || !battery[1].IsCharging {
+		t.Errorf("atualizações de bateria inesperadas: %+v", battery)
+	}
+}
+
+func TestSimulationDriverScenario(t *testing.T) {
+	scenario := &SimScenario{Name: "rápido", Steps: []SimCommand{
+		{At: "0s", Action: SimAttach},
+		{At: "10ms", Action: SimBatteryCurve, From: 50, To: 40, Duration: "20ms", Interval: "10ms"},
+		{At: "40ms", Action: SimDetach},
+	}}
+	driver := NewSimulationDriver(scenario)
+
+	levels := make(chan int, 8)
+	detached := make(chan DeviceEvent, 1)
+	driver.OnBatteryUpdate(func(id uint16, status BatteryStatus) { levels <- status.Level })
+	driver.OnDeviceDisconnected(func(event DeviceEvent) { detached <- event })
+
+	driver.Start()
+	defer driver.Stop()
+
+	receive(t, detached)
+	close(levels)
+	var got []int
+	for level := range levels {
+		got = append(got, level)
+	}
+	if len(got) != 3 || got[0] != 50 || got[2] != 40 {
+		t.Errorf("curva de bateria = %v, want [50 45 40]", got)
+	}
+}
+
+func TestSimulationDriverStopInterruptsScenario(t *testing.T) {
+	driver := NewSimulationDriver(&SimScenario{Loop: true, Steps: []SimCommand{
+		{At: "0s", Action: SimAttach},
+		{At: "1h", Action: SimDetach},
+	}})
+	driver.Start()
+
+	done := make(chan struct{})
+	go func() {
+		driver.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Stop() não interrompeu o cenário")
+	}
+}
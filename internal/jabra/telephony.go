@@ -0,0 +1,245 @@
+package jabra
+
+import (
+	"github.com/aiknow/acc_jabra_agent/internal/hidreport"
+)
+
+// Páginas de uso HID relevantes para headsets
+const (
+	UsagePageLED       uint16 = 0x08
+	UsagePageTelephony uint16 = 0x0B
+	UsagePageConsumer  uint16 = 0x0C
+)
+
+// Usos da página Telephony (HID Usage Tables, seção 14)
+const (
+	UsageHookSwitch    uint16 = 0x20
+	UsageFlash         uint16 = 0x21
+	UsageRedial        uint16 = 0x24
+	UsageTransfer      uint16 = 0x25
+	UsageDrop          uint16 = 0x26
+	UsagePhoneMute     uint16 = 0x2F
+	UsageSpeedDial     uint16 = 0x50
+	UsageVoiceMail     uint16 = 0x70
+	UsageLineBusy      uint16 = 0x97
+	UsageRinger        uint16 = 0x9E
+	UsagePhoneKey0     uint16 = 0xB0
+	UsagePhoneKeyStar  uint16 = 0xBA
+	UsagePhoneKeyPound uint16 = 0xBB
+)
+
+// Usos da página Consumer
+const (
+	UsageVolumeIncrement uint16 = 0xE9
+	UsageVolumeDecrement uint16 = 0xEA
+)
+
+// telephonyButtons mapeia usos HID para botões do driver
+var telephonyButtons = map[hidreport.Usage]ButtonID{
+	hidreport.NewUsage(UsagePageTelephony, UsageHookSwitch):     ButtonHookSwitch,
+	hidreport.NewUsage(UsagePageTelephony, UsageFlash):          ButtonFlash,
+	hidreport.NewUsage(UsagePageTelephony, UsageRedial):         ButtonRedial,
+	hidreport.NewUsage(UsagePageTelephony, UsageTransfer):       ButtonTransfer,
+	hidreport.NewUsage(UsagePageTelephony, UsageDrop):           ButtonEndCall,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneMute):      ButtonMute,
+	hidreport.NewUsage(UsagePageTelephony, UsageSpeedDial):      ButtonSpeedDial,
+	hidreport.NewUsage(UsagePageTelephony, UsageVoiceMail):      ButtonVoiceMail,
+	hidreport.NewUsage(UsagePageTelephony, UsageLineBusy):       ButtonLineBusy,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+0):    ButtonKey0,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+1):    ButtonKey1,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+2):    ButtonKey2,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+3):    ButtonKey3,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+4):    ButtonKey4,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+5):    ButtonKey5,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+6):    ButtonKey6,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+7):    ButtonKey7,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+8):    ButtonKey8,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKey0+9):    ButtonKey9,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKeyStar):   ButtonKeyStar,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneKeyPound):  ButtonKeyPound,
+	hidreport.NewUsage(UsagePageConsumer, UsageVolumeIncrement): ButtonVolumeUp,
+	hidreport.NewUsage(UsagePageConsumer, UsageVolumeDecrement): ButtonVolumeDown,
+}
+
+// onOffControls são os usos do tipo OOC (On/Off Control): quando declarados
+// como absolutos, o valor reportado é o estado e não um pulso.
+var onOffControls = map[hidreport.Usage]bool{
+	hidreport.NewUsage(UsagePageTelephony, UsageHookSwitch): true,
+	hidreport.NewUsage(UsagePageTelephony, UsagePhoneMute):  true,
+}
+
+// telephonyControl é um campo de entrada associado a um botão
+type telephonyControl struct {
+	field    hidreport.Field
+	button   ButtonID
+	relative bool
+	absolute bool // OOC absoluto: valor = estado
+	last     int32
+}
+
+// telephonyArray é um campo array cujos slots selecionam usos
+type telephonyArray struct {
+	field   hidreport.Field
+	pressed []ButtonID
+}
+
+// TelephonyDecoder traduz relatórios de entrada HID em eventos de botão
+// a partir do descritor de relatório do dispositivo.
+type TelephonyDecoder struct {
+	desc     *hidreport.Descriptor
+	controls map[uint8][]*telephonyControl
+	arrays   map[uint8][]*telephonyArray
+	buttons  map[ButtonID]*telephonyControl
+}
+
+// NewTelephonyDecoder cria um decodificador para o descritor informado.
+// Retorna nil se o descritor não contém nenhum controle conhecido.
+func NewTelephonyDecoder(desc *hidreport.Descriptor) *TelephonyDecoder {
+	t := &TelephonyDecoder{
+		desc:     desc,
+		controls: make(map[uint8][]*telephonyControl),
+		arrays:   make(map[uint8][]*telephonyArray),
+		buttons:  make(map[ButtonID]*telephonyControl),
+	}
+
+	found := false
+	for _, f := range desc.Fields {
+		if f.Kind != hidreport.Input {
+			continue
+		}
+
+		if !f.IsVariable() {
+			for _, u := range f.Usages {
+				if _, ok := telephonyButtons[u]; ok {
+					t.arrays[f.ReportID] = append(t.arrays[f.ReportID], &telephonyArray{field: f})
+					found = true
+					break
+				}
+			}
+			continue
+		}
+
+		button, ok := telephonyButtons[f.Usage()]
+		if !ok {
+			continue
+		}
+		c := &telephonyControl{
+			field:    f,
+			button:   button,
+			relative: f.IsRelative(),
+			absolute: !f.IsRelative() && onOffControls[f.Usage()],
+		}
+		t.controls[f.ReportID] = append(t.controls[f.ReportID], c)
+		if _, exists := t.buttons[button]; !exists {
+			t.buttons[button] = c
+		}
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+	return t
+}
+
+// ParseTelephonyDecoder interpreta o descritor bruto e cria o decodificador
+func ParseTelephonyDecoder(descriptor []byte) (*TelephonyDecoder, error) {
+	desc, err := hidreport.Parse(descriptor)
+	if err != nil {
+		return nil, err
+	}
+	return NewTelephonyDecoder(desc), nil
+}
+
+// Descriptor retorna o descritor interpretado
+func (t *TelephonyDecoder) Descriptor() *hidreport.Descriptor {
+	return t.desc
+}
+
+// Supports retorna true se o dispositivo reporta o botão
+func (t *TelephonyDecoder) Supports(button ButtonID) bool {
+	if _, ok := t.buttons[button]; ok {
+		return true
+	}
+	for _, arrays := range t.arrays {
+		for _, a := range arrays {
+			for _, u := range a.field.Usages {
+				if telephonyButtons[u] == button {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// IsRelative retorna true se o botão é reportado como controle relativo
+func (t *TelephonyDecoder) IsRelative(button ButtonID) bool {
+	c, ok := t.buttons[button]
+	return ok && c.relative
+}
+
+// Decode processa um relatório de entrada e retorna os eventos de botão
+// resultantes (sem DeviceID). Controles absolutos geram eventos apenas
+// quando o valor muda; relativos geram press a cada valor não nulo.
+func (t *TelephonyDecoder) Decode(report []byte) []ButtonEvent {
+	reportID, payload := t.desc.Split(report)
+
+	var events []ButtonEvent
+	for _, c := range t.controls[reportID] {
+		v, ok := c.field.Value(payload, 0)
+		if !ok {
+			continue
+		}
+		changed := v != c.last
+		c.last = v
+
+		if (c.relative && v != 0) || changed {
+			events = append(events, ButtonEvent{
+				ButtonID: c.button,
+				Pressed:  v != 0,
+				Absolute: c.absolute,
+			})
+		}
+	}
+
+	for _, a := range t.arrays[reportID] {
+		var current []ButtonID
+		for i := 0; i < a.field.Count; i++ {
+			v, ok := a.field.Value(payload, i)
+			if !ok {
+				break
+			}
+			usage, ok := a.field.ArrayUsage(v)
+			if !ok {
+				continue
+			}
+			if button, ok := telephonyButtons[usage]; ok && !containsButton(current, button) {
+				current = append(current, button)
+			}
+		}
+
+		for _, button := range a.pressed {
+			if !containsButton(current, button) {
+				events = append(events, ButtonEvent{ButtonID: button, Pressed: false})
+			}
+		}
+		for _, button := range current {
+			if !containsButton(a.pressed, button) {
+				events = append(events, ButtonEvent{ButtonID: button, Pressed: true})
+			}
+		}
+		a.pressed = current
+	}
+
+	return events
+}
+
+func containsButton(buttons []ButtonID, button ButtonID) bool {
+	for _, b := range buttons {
+		if b == button {
+			return true
+		}
+	}
+	return false
+}
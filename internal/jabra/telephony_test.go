@@ -0,0 +1,139 @@
+package jabra
+
+import (
+	"reflect"
+	"testing"
+)
+
+// testHeadsetDescriptor descreve um headset com Hook Switch (absoluto),
+// Phone Mute (relativo), Flash e Redial no report 2, teclado em array no
+// report 3 e LEDs Off-Hook/Mute/Ring/Hold no report de saída 2.
+var testHeadsetDescriptor = []byte{
+	0x05, 0x0B, 0x09, 0x05, 0xA1, 0x01,
+	0x85, 0x02, 0x15, 0x00, 0x25, 0x01, 0x75, 0x01, 0x95, 0x01,
+	0x09, 0x20, 0x81, 0x22, // Hook Switch
+	0x09, 0x2F, 0x81, 0x06, // Phone Mute (relativo)
+	0x09, 0x21, 0x09, 0x24, 0x95, 0x02, 0x81, 0x02, // Flash, Redial
+	0x95, 0x04, 0x81, 0x01,
+	0x85, 0x03, 0x19, 0xB0, 0x29, 0xBB, 0x15, 0x01, 0x25, 0x0C,
+	0x75, 0x04, 0x95, 0x01, 0x81, 0x00, 0x81, 0x01, // Teclado
+	0x05, 0x08, 0x85, 0x02, 0x15, 0x00, 0x25, 0x01, 0x75, 0x01, 0x95, 0x01,
+	0x09, 0x17, 0x91, 0x22, // LED Off-Hook
+	0x09, 0x09, 0x91, 0x22, // LED Mute
+	0x09, 0x18, 0x91, 0x22, // LED Ring
+	0x09, 0x20, 0x91, 0x22, // LED Hold
+	0x95, 0x04, 0x91, 0x01,
+	0xC0,
+}
+
+func TestTelephonyDecoder(t *testing.T) {
+	decoder, err := ParseTelephonyDecoder(testHeadsetDescriptor)
+	if err != nil || decoder == nil {
+		t.Fatalf("ParseTelephonyDecoder() = %v, %v", decoder, err)
+	}
+
+	if !decoder.IsRelative(ButtonMute) || decoder.IsRelative(ButtonHookSwitch) {
+		t.Error("Phone Mute deveria ser relativo e Hook Switch absoluto")
+	}
+	if !decoder.Supports(ButtonKeyPound) || decoder.Supports(ButtonVolumeUp) {
+		t.Error("Supports() incorreto para teclado/volume")
+	}
+
+	// Sequência de relatórios: o decodificador mantém estado entre eles
+	steps := []struct {
+		name   string
+		report []byte
+		want   []ButtonEvent
+	}{
+		{"atende (off-hook)", []byte{0x02, 0x01}, []ButtonEvent{{ButtonID: ButtonHookSwitch, Pressed: true, Absolute: true}}},
+		{"repetição sem mudança", []byte{0x02, 0x01}, nil},
+		{"mute pressionado", []byte{0x02, 0x03}, []ButtonEvent{{ButtonID: ButtonMute, Pressed: true}}},
+		{"mute liberado", []byte{0x02, 0x01}, []ButtonEvent{{ButtonID: ButtonMute, Pressed: false}}},
+		{"flash e redial", []byte{0x02, 0x0D}, []ButtonEvent{{ButtonID: ButtonFlash, Pressed: true}, {ButtonID: ButtonRedial, Pressed: true}}},
+		{"desliga (on-hook)", []byte{0x02, 0x00}, []ButtonEvent{
+			{ButtonID: ButtonHookSwitch, Pressed: false, Absolute: true},
+			{ButtonID: ButtonFlash, Pressed: false},
+			{ButtonID: ButtonRedial, Pressed: false},
+		}},
+		{"tecla 1", []byte{0x03, 0x02}, []ButtonEvent{{ButtonID: ButtonKey1, Pressed: true}}},
+		{"tecla 1 para #", []byte{0x03, 0x0C}, []ButtonEvent{{ButtonID: ButtonKey1, Pressed: false}, {ButtonID: ButtonKeyPound, Pressed: true}}},
+		{"teclado liberado", []byte{0x03, 0x00}, []ButtonEvent{{ButtonID: ButtonKeyPound, Pressed: false}}},
+		{"report desconhecido", []byte{0x07, 0xFF}, nil},
+		{"report curto", []byte{0x02}, nil},
+		{"relatório vazio", nil, nil},
+	}
+
+	for _, step := range steps {
+		got := decoder.Decode(step.report)
+		if !reflect.DeepEqual(got, step.want) {
+			t.Errorf("%s: Decode(%x) = %+v, want %+v", step.name, step.report, got, step.want)
+		}
+	}
+}
+
+func TestTelephonyDecoderWithoutControls(t *testing.T) {
+	// Mouse genérico: nenhum controle de telefonia
+	mouse := []byte{0x05, 0x01, 0x09, 0x02, 0xA1, 0x01, 0x05, 0x09, 0x19, 0x01, 0x29, 0x03,
+		0x15, 0x00, 0x25, 0x01, 0x75, 0x01, 0x95, 0x03, 0x81, 0x02, 0xC0}
+
+	decoder, err := ParseTelephonyDecoder(mouse)
+	if err != nil {
+		t.Fatalf("ParseTelephonyDecoder() erro inesperado: %v", err)
+	}
+	if decoder != nil {
+		t.Error("decodificador deveria ser nil sem controles de telefonia")
+	}
+}
+
+func FuzzTelephonyDecoder(f *testing.F) {
+	f.Add([]byte{0x02, 0x01})
+	f.Add([]byte{0x02, 0x0D})
+	f.Add([]byte{0x03, 0x0C})
+	f.Add([]byte{0x02})       // Report curto
+	f.Add([]byte{0x00, 0xFF}) // Report ID 0 em descritor com IDs
+	f.Add([]byte{0xFF, 0xFF, 0xFF})
+	f.Add([]byte{})
+	f.Add(make([]byte, 65))
+
+	f.Fuzz(func(t *testing.T, report []byte) {
+		decoder, err := ParseTelephonyDecoder(testHeadsetDescriptor)
+		if err != nil || decoder == nil {
+			t.Fatalf("ParseTelephonyDecoder() = %v, %v", decoder, err)
+		}
+
+		for _, ev := range decoder.Decode(report) {
+			if !decoder.Supports(ev.ButtonID) {
+				t.Errorf("Decode(%x) gerou botão não suportado: %+v", report, ev)
+			}
+		}
+
+		// Repetido, o relatório só gera de novo o press dos relativos
+		for _, ev := range decoder.Decode(report) {
+			if !ev.Pressed || !decoder.IsRelative(ev.ButtonID) {
+				t.Errorf("Decode(%x) repetido gerou %+v", report, ev)
+			}
+		}
+	})
+}
+
+func TestLegacyButtonEvent(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want ButtonEvent
+		ok   bool
+	}{
+		{[]byte{0x01, 0x01}, ButtonEvent{ButtonID: ButtonHookSwitch, Pressed: true, Absolute: true}, true},
+		{[]byte{0x01, 0x02}, ButtonEvent{ButtonID: ButtonMute, Pressed: true}, true},
+		{[]byte{0x01, 0x04}, ButtonEvent{ButtonID: ButtonHookSwitch, Pressed: false, Absolute: true}, true},
+		{[]byte{0x01, 0x08}, ButtonEvent{ButtonID: ButtonFlash, Pressed: true}, true},
+		{[]byte{0x01, 0x10}, ButtonEvent{}, false},
+		{[]byte{0x02, 0x02}, ButtonEvent{}, false},
+		{[]byte{0x01}, ButtonEvent{}, false},
+	}
+	for _, tt := range tests {
+		event, ok := legacyButtonEvent(tt.data)
+		if ok != tt.ok || event != tt.want {
+			t.Errorf("legacyButtonEvent(%x) = %+v, %v; want %+v, %v", tt.data, event, ok, tt.want, tt.ok)
+		}
+	}
+}
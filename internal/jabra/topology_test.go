@@ -0,0 +1,127 @@
+package jabra
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+)
+
+// parentID retorna o ponteiro usado em SimCommand.Parent
+func parentID(id uint16) *uint16 {
+	return &id
+}
+
+func TestMonitorTopology(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "topology_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	sim := NewSimulationDriver(nil)
+	m := NewMonitor(sim, "", store)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer m.Stop()
+
+	run := func(cmd SimCommand) {
+		t.Helper()
+		if err := sim.Execute(cmd); err != nil {
+			t.Fatalf("Execute(%+v) erro inesperado: %v", cmd, err)
+		}
+	}
+	telemetry := func(id string) (connection, link string) {
+		t.Helper()
+		payload, ok := m.GetDeviceTelemetry(id)
+		if !ok {
+			t.Fatalf("dispositivo %s não encontrado", id)
+		}
+		return payload.State.Connection, payload.State.Link
+	}
+
+	if err := sim.Execute(SimCommand{Action: SimAttach, Device: 2, Parent: parentID(1)}); err == nil {
+		t.Error("attach com pai inexistente deveria falhar")
+	}
+
+	run(SimCommand{Action: SimAttach, Device: 1, Name: "Jabra Link 380"})
+	run(SimCommand{Action: SimAttach, Device: 2, Name: "Jabra Engage 55", Parent: parentID(1)})
+
+	dongle, _ := m.GetDeviceTelemetry("1")
+	if dongle.Product.FormFactor != string(FormFactorDongle) || !slices.Equal(dongle.Children, []uint16{2}) || dongle.State.Link != string(LinkConnected) {
+		t.Errorf("topologia do dongle inesperada: %+v", dongle)
+	}
+	if headset, _ := m.GetDeviceTelemetry("2"); headset.ParentID == nil || *headset.ParentID != 1 {
+		t.Errorf("headset deveria apontar para o dongle: parent %v", headset.ParentID)
+	}
+	if primary := m.GetTelemetry(); primary.DeviceID != 2 {
+		t.Errorf("headset pareado deveria ser o principal, obtido %d", primary.DeviceID)
+	}
+
+	// Headset fora de alcance: o dongle continua presente
+	run(SimCommand{Action: SimLink, Device: 1, Linked: false})
+	if connection, link := telemetry("1"); connection != "online" || link != string(LinkOutOfRange) {
+		t.Errorf("dongle = %s/%s, want online/out_of_range", connection, link)
+	}
+	if connection, link := telemetry("2"); connection != "offline" || link != string(LinkOutOfRange) {
+		t.Errorf("headset = %s/%s, want offline/out_of_range", connection, link)
+	}
+	if primary := m.GetTelemetry(); primary.DeviceID != 1 {
+		t.Errorf("sem headset o dongle deveria ser o principal, obtido %d", primary.DeviceID)
+	}
+
+	run(SimCommand{Action: SimLink, Device: 1, Linked: true})
+	run(SimCommand{Action: SimAttach, Device: 2, Name: "Jabra Engage 55", Parent: parentID(1)})
+	if connection, link := telemetry("2"); connection != "online" || link != string(LinkConnected) {
+		t.Errorf("headset = %s/%s, want online/connected", connection, link)
+	}
+
+	// Dongle removido: headset some junto, sem estado de rádio
+	run(SimCommand{Action: SimDetach, Device: 1})
+	if connection, link := telemetry("1"); connection != "offline" || link != string(LinkNone) {
+		t.Errorf("dongle = %s/%s, want offline sem rádio", connection, link)
+	}
+	if connection, link := telemetry("2"); connection != "offline" || link != string(LinkNone) {
+		t.Errorf("headset = %s/%s, want offline sem rádio", connection, link)
+	}
+}
+
+// O ID 0 é um pai válido (o simulador e o cenário padrão usam o ID 0)
+func TestMonitorTopologyParentZero(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "topology_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	sim := NewSimulationDriver(nil)
+	m := NewMonitor(sim, "", store)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer m.Stop()
+
+	for _, cmd := range []SimCommand{
+		{Action: SimAttach, Device: 0, Name: "Jabra Link 380"},
+		{Action: SimAttach, Device: 1, Name: "Jabra Engage 55", Parent: parentID(0)},
+	} {
+		if err := sim.Execute(cmd); err != nil {
+			t.Fatalf("Execute(%+v) erro inesperado: %v", cmd, err)
+		}
+	}
+
+	dongle, _ := m.GetDeviceTelemetry("0")
+	if dongle.ParentID != nil || !slices.Equal(dongle.Children, []uint16{1}) {
+		t.Errorf("dongle = parent %v, children %v; want sem pai, children [1]", dongle.ParentID, dongle.Children)
+	}
+	if headset, _ := m.GetDeviceTelemetry("1"); headset.ParentID == nil || *headset.ParentID != 0 {
+		t.Errorf("headset deveria apontar para o dongle 0: parent %v", headset.ParentID)
+	}
+
+	// Sem o dongle 0, o headset pareado some junto
+	if err := sim.Execute(SimCommand{Action: SimDetach, Device: 0}); err != nil {
+		t.Fatalf("detach erro inesperado: %v", err)
+	}
+	if headset, _ := m.GetDeviceTelemetry("1"); headset.State.Connection != "offline" {
+		t.Errorf("headset = %s, want offline", headset.State.Connection)
+	}
+}
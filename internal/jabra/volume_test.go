@@ -0,0 +1,128 @@
+package jabra
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+)
+
+func TestMonitorVolume(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "volume_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	store.SetSetting("operator_name", "Maria")
+	store.SetSetting("default_volume", "40")
+	store.SetSetting("default_volume:Maria", "70")
+
+	bus := events.NewBus()
+	defer bus.Close()
+	sim := NewSimulationDriver(nil)
+	m := NewMonitor(sim, "", store)
+	m.SetEventBus(bus)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer m.Stop()
+	sub := bus.Subscribe(events.Options{Name: "test", Kinds: []events.Kind{events.KindVolume}})
+
+	next := func() events.Volume {
+		t.Helper()
+		select {
+		case event := <-sub.Events():
+			return event.(events.Volume)
+		case <-time.After(time.Second):
+			t.Fatal("timeout aguardando evento de volume")
+		}
+		return events.Volume{}
+	}
+	volume := func() int {
+		t.Helper()
+		payload, _ := m.GetDeviceTelemetry("1")
+		device, _ := sim.GetVolume(1)
+		if device != payload.State.Volume {
+			t.Errorf("volume do dispositivo %d difere da telemetria %d", device, payload.State.Volume)
+		}
+		return payload.State.Volume
+	}
+
+	// Volume padrão do operador aplicado na conexão
+	if err := sim.Execute(SimCommand{Action: SimAttach, Device: 1}); err != nil {
+		t.Fatalf("attach erro inesperado: %v", err)
+	}
+	if got := volume(); got != 70 {
+		t.Errorf("volume na conexão = %d, want 70 (default_volume:Maria)", got)
+	}
+	if event := next(); event.Level != 70 || event.Source != "default" {
+		t.Errorf("evento inesperado: %+v", event)
+	}
+
+	if level, applied, err := m.StepPrimaryVolume(40, "keymap"); err != nil || !applied || level != 100 {
+		t.Errorf("StepPrimaryVolume(+40) = %d, %v, %v; want 100 (limite)", level, applied, err)
+	}
+	if event := next(); event.Level != 100 || event.Source != "keymap" {
+		t.Errorf("evento inesperado: %+v", event)
+	}
+
+	if err := m.SetVolume(1, 101, "api"); err == nil {
+		t.Error("volume fora de 0-100 deveria falhar")
+	}
+	if err := m.SetVolume(1, 25, "api"); err != nil {
+		t.Fatalf("SetVolume() erro inesperado: %v", err)
+	}
+	if got := volume(); got != 25 {
+		t.Errorf("volume = %d, want 25", got)
+	}
+}
+
+// capsDriver é o simulador com as capacidades substituídas
+type capsDriver struct {
+	*SimulationDriver
+	caps Capabilities
+}
+
+func (d *capsDriver) Capabilities(deviceID uint16) (Capabilities, error) {
+	return d.caps, nil
+}
+
+func TestMonitorStepVolume(t *testing.T) {
+	tests := []struct {
+		name    string
+		caps    Capabilities
+		applied bool
+		want    int
+	}{
+		{"Volume pelo agente", Capabilities{Volume: true}, true, 55},
+		{"Dispositivo ajusta o próprio volume", Capabilities{Volume: true, VolumeButtons: true}, false, 50},
+		{"Sem suporte a volume", Capabilities{}, false, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := db.NewStore(filepath.Join(t.TempDir(), "step_test.db"))
+			if err != nil {
+				t.Fatalf("Erro ao criar store: %v", err)
+			}
+			sim := NewSimulationDriver(nil)
+			m := NewMonitor(&capsDriver{SimulationDriver: sim, caps: tt.caps}, "", store)
+			if err := m.Start(context.Background()); err != nil {
+				t.Fatalf("Start() erro inesperado: %v", err)
+			}
+			defer m.Stop()
+			if err := sim.Execute(SimCommand{Action: SimAttach, Device: 1}); err != nil {
+				t.Fatalf("attach erro inesperado: %v", err)
+			}
+
+			_, applied, err := m.StepVolume(1, 5, "keymap")
+			if err != nil || applied != tt.applied {
+				t.Errorf("StepVolume(+5) = %v, %v; want applied %v", applied, err, tt.applied)
+			}
+			if level, _ := sim.GetVolume(1); level != tt.want {
+				t.Errorf("volume = %d, want %d", level, tt.want)
+			}
+		})
+	}
+}
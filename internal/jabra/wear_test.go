@@ -0,0 +1,83 @@
+package jabra
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/db"
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+)
+
+func TestMonitorWearEvents(t *testing.T) {
+	store, err := db.NewStore(filepath.Join(t.TempDir(), "wear_test.db"))
+	if err != nil {
+		t.Fatalf("Erro ao criar store: %v", err)
+	}
+	bus := events.NewBus()
+	defer bus.Close()
+
+	sim := NewSimulationDriver(nil)
+	m := NewMonitor(sim, "", store)
+	m.SetEventBus(bus)
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() erro inesperado: %v", err)
+	}
+	defer m.Stop()
+	sub := bus.Subscribe(events.Options{Name: "test", Kinds: []events.Kind{events.KindWear}})
+
+	run := func(cmd SimCommand) {
+		t.Helper()
+		if err := sim.Execute(cmd); err != nil {
+			t.Fatalf("Execute(%+v) erro inesperado: %v", cmd, err)
+		}
+	}
+	wear := func() string {
+		t.Helper()
+		payload, ok := m.GetDeviceTelemetry("1")
+		if !ok {
+			t.Fatal("dispositivo 1 não encontrado")
+		}
+		return payload.State.Wear
+	}
+
+	// Estado inicial lido na conexão, sem evento
+	run(SimCommand{Action: SimAttach, Device: 1, Serial: "SIM-1"})
+	if got := wear(); got != "on" {
+		t.Errorf("wear inicial = %q, want on", got)
+	}
+
+	run(SimCommand{Action: SimWear, Device: 1, Worn: false})
+	run(SimCommand{Action: SimWear, Device: 1, Worn: false}) // Repetido: sem evento
+	run(SimCommand{Action: SimClick, Device: 1, Button: "OffHook"})
+	run(SimCommand{Action: SimWear, Device: 1, Worn: true})
+
+	want := []events.Wear{
+		{DeviceID: 1, Serial: "SIM-1", Worn: false, CallState: string(CallIdle)},
+		{DeviceID: 1, Serial: "SIM-1", Worn: true, CallState: string(CallInCall)},
+	}
+	for i, w := range want {
+		select {
+		case event := <-sub.Events():
+			if got, ok := event.(events.Wear); !ok || got != w {
+				t.Errorf("evento %d = %+v, want %+v", i, event, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout aguardando evento %d", i)
+		}
+	}
+	select {
+	case event := <-sub.Events():
+		t.Errorf("evento inesperado: %+v", event)
+	default:
+	}
+
+	if got := wear(); got != "on" {
+		t.Errorf("wear = %q, want on", got)
+	}
+	run(SimCommand{Action: SimDetach, Device: 1})
+	if got := wear(); got != "" {
+		t.Errorf("wear de dispositivo offline = %q, want vazio", got)
+	}
+}
@@ -3,9 +3,23 @@ package models
 import "time"
 
 type BatteryInfo struct {
-	Level                     int    `json:"level"`
-	Status                    string `json:"status"` // discharging, charging, fully charged
-	EstimatedRemainingMinutes int    `json:"estimated_remaining_minutes"`
+	Level                     int     `json:"level"`
+	Status                    string  `json:"status"` // discharging, charging, fully charged
+	EstimatedRemainingMinutes int     `json:"estimated_remaining_minutes"`
+	TimeToEmptyMinutes        int     `json:"time_to_empty_minutes"`
+	TimeToFullMinutes         int     `json:"time_to_full_minutes"`
+	TalkTimeMinutes           int     `json:"talk_time_minutes"` // Autonomia se o restante for em chamada
+	IdleTimeMinutes           int     `json:"idle_time_minutes"` // Autonomia se o restante for fora de chamada
+	Confidence                float64 `json:"confidence"`        // 0-1, aprendida do histórico do serial
+}
+
+// CallInfo é o estado da máquina de chamada de um dispositivo
+type CallInfo struct {
+	State     string     `json:"state"`                // idle, ringing, in_call, held, muted, ended
+	Since     time.Time  `json:"since"`                // Entrada no estado atual
+	StartedAt *time.Time `json:"started_at,omitempty"` // Início da chamada atual ou da última
+	EndedAt   *time.Time `json:"ended_at,omitempty"`   // Fim da última chamada
+	LastEvent string     `json:"last_event,omitempty"` // Último evento aceito (ex.: "answer")
 }
 
 type DeviceState struct {
@@ -17,6 +31,9 @@ type DeviceState struct {
 	SessionUptime string      `json:"session_uptime"` // Ex: "02h 15m"
 	CustomID      string      `json:"custom_id"`      // Nome do operador
 	CustomColor   string      `json:"custom_color"`   // Cor de identificação
+	Call          CallInfo    `json:"call"`
+	Link          string      `json:"link,omitempty"` // Rádio do headset: connected, out_of_range
+	Wear          string      `json:"wear,omitempty"` // Sensor de uso: on, off
 }
 
 type DeviceEvents struct {
@@ -24,10 +41,23 @@ type DeviceEvents struct {
 	LastButtonPressed string    `json:"last_button_pressed"`
 }
 
+// ProductInfo são os metadados do modelo resolvidos pelo catálogo de produtos
+type ProductInfo struct {
+	ProductID  uint16 `json:"product_id"`
+	Model      string `json:"model"`
+	Family     string `json:"family,omitempty"`
+	FormFactor string `json:"form_factor"` // dongle, headset, speakerphone, unknown
+	Wireless   bool   `json:"wireless"`
+}
+
 type TelemetryPayload struct {
-	Module string       `json:"module"`
-	Device string       `json:"device"`
-	Serial string       `json:"serial"`
-	State  DeviceState  `json:"state"`
-	Events DeviceEvents `json:"events"`
+	Module   string       `json:"module"`
+	DeviceID uint16       `json:"device_id"`
+	Device   string       `json:"device"`
+	Serial   string       `json:"serial"`
+	Product  ProductInfo  `json:"product"`
+	ParentID *uint16      `json:"parent_id,omitempty"` // Dongle/base do headset
+	Children []uint16     `json:"children,omitempty"`  // Headsets pareados ao dongle
+	State    DeviceState  `json:"state"`
+	Events   DeviceEvents `json:"events"`
 }
@@ -2,10 +2,15 @@ package security
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"sync"
 	"time"
+
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
 )
 
 // BlockMode define como dispositivos não autorizados são tratados
@@ -228,25 +233,68 @@ func (w *Whitelist) CheckDevice(serial string) (allowed bool, shouldBlock bool)
 	return
 }
 
-// SoftBlock aplica soft-block em um dispositivo não autorizado
+// SoftBlock aplica soft-block em um dispositivo não autorizado.
+// O mute é obrigatório: se falhar (ou o dispositivo não suportar mute), o
+// dispositivo não fica marcado como bloqueado e o erro é retornado. Ringer
+// sem suporte é apenas registrado.
 func (w *Whitelist) SoftBlock(controller DeviceController, deviceID uint16) error {
-	w.mu.Lock()
-	w.blockedDevices[deviceID] = true
-	w.mu.Unlock()
-
 	// Força mute e desliga ringer
 	if err := controller.SetMute(deviceID, true); err != nil {
-		log.Printf("[Whitelist] Erro ao aplicar mute no dispositivo %d: %v", deviceID, err)
+		if errors.Is(err, jabra.ErrNotSupported) {
+			log.Printf("[Whitelist] Dispositivo %d não suporta mute, soft-block não aplicável", deviceID)
+		}
+		return fmt.Errorf("soft-block device %d: %w", deviceID, err)
 	}
 
 	if err := controller.SetRinger(deviceID, false); err != nil {
-		log.Printf("[Whitelist] Erro ao desligar ringer no dispositivo %d: %v", deviceID, err)
+		if errors.Is(err, jabra.ErrNotSupported) {
+			log.Printf("[Whitelist] Dispositivo %d não suporta ringer, apenas mute aplicado", deviceID)
+		} else {
+			log.Printf("[Whitelist] Erro ao desligar ringer no dispositivo %d: %v", deviceID, err)
+		}
 	}
 
+	w.mu.Lock()
+	w.blockedDevices[deviceID] = true
+	w.mu.Unlock()
+
 	log.Printf("[Whitelist] Soft-block aplicado no dispositivo %d", deviceID)
 	return nil
 }
 
+// SubscribeEvents verifica cada dispositivo conectado publicado no
+// barramento, aplicando soft-block pelo controller quando não autorizado, e
+// remove o bloqueio quando ele é desconectado
+func (w *Whitelist) SubscribeEvents(bus *events.Bus, controller DeviceController) *events.Subscription {
+	return bus.Handle(events.Options{
+		Name:  "whitelist",
+		Kinds: []events.Kind{events.KindDeviceAttached, events.KindDeviceDetached},
+	}, func(event events.Event) {
+		switch e := event.(type) {
+		case events.DeviceAttached:
+			w.handleAttached(controller, e)
+		case events.DeviceDetached:
+			w.RemoveBlock(e.DeviceID)
+		}
+	})
+}
+
+// handleAttached aplica a política da whitelist a um dispositivo conectado
+func (w *Whitelist) handleAttached(controller DeviceController, e events.DeviceAttached) {
+	allowed, shouldBlock := w.CheckDevice(e.Serial)
+	if allowed {
+		return
+	}
+
+	log.Printf("[Whitelist] Dispositivo não autorizado: %s (serial %s, ID %d)", e.Name, e.Serial, e.DeviceID)
+	if !shouldBlock {
+		return
+	}
+	if err := w.SoftBlock(controller, e.DeviceID); err != nil {
+		log.Printf("[Whitelist] Erro ao bloquear dispositivo %d: %v", e.DeviceID, err)
+	}
+}
+
 // RemoveBlock remove o bloqueio de um dispositivo
 func (w *Whitelist) RemoveBlock(deviceID uint16) {
 	w.mu.Lock()
@@ -294,7 +342,9 @@ func (w *Whitelist) enforceBlocks(controller DeviceController) {
 	w.mu.RUnlock()
 
 	for _, deviceID := range devices {
-		controller.SetMute(deviceID, true)
+		if err := controller.SetMute(deviceID, true); err != nil {
+			log.Printf("[Whitelist] Erro ao reforçar mute no dispositivo %d: %v", deviceID, err)
+		}
 	}
 }
 
@@ -0,0 +1,89 @@
+package security
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aiknow/acc_jabra_agent/internal/events"
+	"github.com/aiknow/acc_jabra_agent/internal/jabra"
+)
+
+// fakeController registra os comandos e retorna os erros configurados
+type fakeController struct {
+	muteErr   error
+	ringerErr error
+	mutes     int
+}
+
+func (c *fakeController) SetMute(deviceID uint16, mute bool) error {
+	c.mutes++
+	return c.muteErr
+}
+
+func (c *fakeController) SetRinger(deviceID uint16, ring bool) error {
+	return c.ringerErr
+}
+
+func TestSoftBlock(t *testing.T) {
+	notSupported := &jabra.NotSupportedError{Capability: jabra.CapabilityRinger, DeviceID: 1}
+
+	t.Run("mute aplicado, ringer sem suporte", func(t *testing.T) {
+		w, _ := NewWhitelist("")
+		controller := &fakeController{ringerErr: notSupported}
+		if err := w.SoftBlock(controller, 1); err != nil {
+			t.Fatalf("SoftBlock() erro inesperado: %v", err)
+		}
+		if !w.IsBlocked(1) {
+			t.Error("dispositivo deveria estar bloqueado")
+		}
+	})
+
+	t.Run("mute sem suporte", func(t *testing.T) {
+		w, _ := NewWhitelist("")
+		controller := &fakeController{muteErr: &jabra.NotSupportedError{Capability: jabra.CapabilityMute, DeviceID: 2}}
+		err := w.SoftBlock(controller, 2)
+		if !errors.Is(err, jabra.ErrNotSupported) {
+			t.Errorf("SoftBlock() = %v, want ErrNotSupported", err)
+		}
+		if w.IsBlocked(2) {
+			t.Error("dispositivo sem mute não deveria ficar marcado como bloqueado")
+		}
+	})
+
+	t.Run("falha no mute", func(t *testing.T) {
+		w, _ := NewWhitelist("")
+		controller := &fakeController{muteErr: errors.New("write failed")}
+		if err := w.SoftBlock(controller, 3); err == nil || w.IsBlocked(3) {
+			t.Errorf("SoftBlock() = %v, bloqueado = %v; want erro sem bloqueio", err, w.IsBlocked(3))
+		}
+	})
+}
+
+func TestWhitelistSubscribeEvents(t *testing.T) {
+	w, _ := NewWhitelist("")
+	w.SetEnabled(true)
+	w.SetBlockMode(BlockModeSoft)
+	w.AddSerial("ALLOWED")
+	controller := &fakeController{}
+
+	bus := events.NewBus()
+	w.SubscribeEvents(bus, controller)
+	bus.Publish(events.DeviceAttached{DeviceID: 1, Serial: "ALLOWED"})
+	bus.Publish(events.DeviceAttached{DeviceID: 2, Serial: "INTRUSO"})
+	bus.Publish(events.DeviceAttached{DeviceID: 3, Serial: "OUTRO"})
+	bus.Publish(events.DeviceDetached{DeviceID: 3, Serial: "OUTRO"})
+	bus.Close() // Aguarda o assinante processar a fila
+
+	if w.IsBlocked(1) {
+		t.Error("dispositivo autorizado não deveria ser bloqueado")
+	}
+	if !w.IsBlocked(2) {
+		t.Error("dispositivo não autorizado deveria ser bloqueado")
+	}
+	if w.IsBlocked(3) {
+		t.Error("bloqueio deveria ser removido ao desconectar")
+	}
+	if controller.mutes != 2 {
+		t.Errorf("mutes = %d, want 2", controller.mutes)
+	}
+}
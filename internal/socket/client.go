@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/aiknow/acc_jabra_agent/internal/events"
 	"github.com/gorilla/websocket"
 )
 
@@ -51,6 +52,9 @@ type Client struct {
 	onLigacaoAtendida  func(ramalQueAtendeu string)
 	onLigacaoInterna   func(ramalQueSolicitou string, temCarro bool)
 	onConnectionChange func(connected bool)
+
+	// Barramento onde eventos recebidos e mudanças de conexão são publicados
+	bus *events.Bus
 }
 
 // SocketMessage representa uma mensagem Socket.IO
@@ -132,6 +136,7 @@ func (c *Client) connectInternal() error {
 	if c.onConnectionChange != nil {
 		go c.onConnectionChange(true)
 	}
+	c.publish(events.SocketEvent{Name: events.SocketConnected})
 
 	log.Printf("[Socket.IO] Conectado a %s:%d", c.config.Host, c.config.Port)
 	return nil
@@ -159,6 +164,7 @@ func (c *Client) Disconnect() error {
 	if c.onConnectionChange != nil {
 		go c.onConnectionChange(false)
 	}
+	c.publish(events.SocketEvent{Name: events.SocketDisconnected})
 
 	log.Printf("[Socket.IO] Desconectado")
 	return nil
@@ -252,27 +258,39 @@ func (c *Client) processEvent(event string, data json.RawMessage) {
 
 	switch event {
 	case "notificar_carro":
-		if c.onNotificarCarro != nil {
-			var payload NotificarCarroPayload
-			if err := json.Unmarshal(data, &payload); err == nil {
+		var payload NotificarCarroPayload
+		if err := json.Unmarshal(data, &payload); err == nil {
+			if c.onNotificarCarro != nil {
 				go c.onNotificarCarro(payload.TemCarro)
 			}
+			c.publish(events.SocketEvent{Name: event, TemCarro: payload.TemCarro})
 		}
 
 	case "ligacao_atendida":
-		if c.onLigacaoAtendida != nil {
-			var payload LigacaoAtendidaPayload
-			if err := json.Unmarshal(data, &payload); err == nil {
+		var payload LigacaoAtendidaPayload
+		if err := json.Unmarshal(data, &payload); err == nil {
+			if c.onLigacaoAtendida != nil {
 				go c.onLigacaoAtendida(payload.RamalQueAtendeu)
 			}
+			c.publish(events.SocketEvent{
+				Name:  event,
+				Ramal: payload.RamalQueAtendeu,
+				Own:   payload.RamalQueAtendeu == c.config.Ramal,
+			})
 		}
 
 	case "ligacao_interna":
-		if c.onLigacaoInterna != nil {
-			var payload LigacaoInternaPayload
-			if err := json.Unmarshal(data, &payload); err == nil {
+		var payload LigacaoInternaPayload
+		if err := json.Unmarshal(data, &payload); err == nil {
+			if c.onLigacaoInterna != nil {
 				go c.onLigacaoInterna(payload.RamalQueSolicitou, payload.TemCarro)
 			}
+			c.publish(events.SocketEvent{
+				Name:     event,
+				Ramal:    payload.RamalQueSolicitou,
+				Own:      payload.RamalQueSolicitou == c.config.Ramal,
+				TemCarro: payload.TemCarro,
+			})
 		}
 
 	default:
@@ -293,6 +311,7 @@ func (c *Client) handleDisconnect() {
 	if c.onConnectionChange != nil {
 		go c.onConnectionChange(false)
 	}
+	c.publish(events.SocketEvent{Name: events.SocketDisconnected})
 
 	// Tenta reconectar
 	go c.reconnect()
@@ -375,6 +394,22 @@ func (c *Client) EmitClick(button string) error {
 	return c.Emit("click", payload)
 }
 
+// SetEventBus define o barramento onde os eventos do servidor
+// (notificar_carro, ligacao_*) e as mudanças de conexão são publicados.
+// Deve ser chamado antes de Connect.
+func (c *Client) SetEventBus(bus *events.Bus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.bus = bus
+}
+
+// publish publica um evento no barramento, se houver
+func (c *Client) publish(event events.SocketEvent) {
+	if c.bus != nil {
+		c.bus.Publish(event)
+	}
+}
+
 // OnNotificarCarro registra callback para evento notificar_carro
 func (c *Client) OnNotificarCarro(handler func(temCarro bool)) {
 	c.mu.Lock()
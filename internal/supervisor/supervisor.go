@@ -0,0 +1,83 @@
+// Package supervisor executa workers de longa duração em segundo plano,
+// reiniciando os que falham (erro ou panic) até o contexto ser cancelado.
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Worker é uma tarefa de longa duração. Deve retornar quando ctx for
+// cancelado; retorno nil encerra o worker sem reinício.
+type Worker func(ctx context.Context) error
+
+// Supervisor acompanha um grupo de workers
+type Supervisor struct {
+	name string
+	wg   sync.WaitGroup
+
+	// RestartDelay é a espera antes do primeiro reinício; dobra a cada falha
+	// seguida até MaxRestartDelay
+	RestartDelay    time.Duration
+	MaxRestartDelay time.Duration
+}
+
+// New cria um supervisor; name identifica o grupo nos logs
+func New(name string) *Supervisor {
+	return &Supervisor{
+		name:            name,
+		RestartDelay:    time.Second,
+		MaxRestartDelay: time.Minute,
+	}
+}
+
+// Go inicia o worker e o reinicia após falhas enquanto ctx estiver ativo
+func (s *Supervisor) Go(ctx context.Context, name string, worker Worker) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		delay := s.RestartDelay
+		for {
+			started := time.Now()
+			err := run(ctx, worker)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+
+			// Um worker que rodou mais que o atraso máximo volta ao atraso inicial
+			if time.Since(started) > s.MaxRestartDelay {
+				delay = s.RestartDelay
+			}
+			log.Printf("[%s] Worker %s falhou, reiniciando em %v: %v", s.name, name, delay, err)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			delay = min(delay*2, s.MaxRestartDelay)
+		}
+	}()
+}
+
+// Wait aguarda o término de todos os workers
+func (s *Supervisor) Wait() {
+	s.wg.Wait()
+}
+
+// run executa o worker convertendo panics em erro
+func run(ctx context.Context, worker Worker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return worker(ctx)
+}
@@ -0,0 +1,83 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestSupervisor() *Supervisor {
+	s := New("test")
+	s.RestartDelay = time.Millisecond
+	s.MaxRestartDelay = 5 * time.Millisecond
+	return s
+}
+
+func TestSupervisorRestartsFailedWorkers(t *testing.T) {
+	s := newTestSupervisor()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs atomic.Int32
+	done := make(chan struct{})
+	s.Go(ctx, "flaky", func(ctx context.Context) error {
+		switch runs.Add(1) {
+		case 1:
+			return errors.New("falha")
+		case 2:
+			panic("boom")
+		}
+		close(done)
+		<-ctx.Done()
+		return nil
+	})
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("worker não foi reiniciado (execuções: %d)", runs.Load())
+	}
+	cancel()
+	s.Wait()
+	if got := runs.Load(); got != 3 {
+		t.Errorf("execuções = %d, want 3", got)
+	}
+}
+
+func TestSupervisorWorkerDone(t *testing.T) {
+	s := newTestSupervisor()
+	var runs atomic.Int32
+	s.Go(context.Background(), "once", func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	s.Wait()
+	if got := runs.Load(); got != 1 {
+		t.Errorf("worker concluído não deveria ser reiniciado: %d execuções", got)
+	}
+}
+
+func TestSupervisorStopsOnCancel(t *testing.T) {
+	s := newTestSupervisor()
+	s.RestartDelay = time.Hour
+	s.MaxRestartDelay = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+
+	failed := make(chan struct{})
+	s.Go(ctx, "failing", func(ctx context.Context) error {
+		close(failed)
+		return errors.New("falha")
+	})
+	<-failed
+	cancel()
+
+	waited := make(chan struct{})
+	go func() { s.Wait(); close(waited) }()
+	select {
+	case <-waited:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Wait() não retornou após o cancelamento durante o atraso de reinício")
+	}
+}
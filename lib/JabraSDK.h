@@ -226,6 +226,18 @@ void Jabra_FreeDeviceList(Jabra_DeviceInfo* devices);
  */
 const char* Jabra_GetDeviceName(Jabra_DeviceID deviceID);
 
+/**
+ * Obtém os dispositivos Jabra conectados com Vendor/Product ID USB.
+ * @param count Ponteiro para receber quantidade de dispositivos
+ * @return Array de Jabra_DeviceInfo (cada item liberado com Jabra_FreeDeviceInfo)
+ */
+Jabra_DeviceInfo* Jabra_GetAttachedJabraDevices(int* count);
+
+/**
+ * Libera um item retornado por Jabra_GetAttachedJabraDevices.
+ */
+void Jabra_FreeDeviceInfo(Jabra_DeviceInfo info);
+
 /**
  * Obtém número serial do dispositivo.
  * @param deviceID ID do dispositivo
@@ -245,6 +257,45 @@ void Jabra_FreeString(char* str);
  */
 int Jabra_IsDongle(Jabra_DeviceID deviceID);
 
+// ============================================================================
+// Funções de Capacidade
+// ============================================================================
+
+/**
+ * Verifica se o dispositivo suporta mute.
+ * @param deviceID ID do dispositivo
+ * @return 1 se suportado, 0 caso contrário
+ */
+int Jabra_IsMuteSupported(Jabra_DeviceID deviceID);
+
+/**
+ * Verifica se o dispositivo suporta ringer (toque).
+ * @param deviceID ID do dispositivo
+ * @return 1 se suportado, 0 caso contrário
+ */
+int Jabra_IsRingerSupported(Jabra_DeviceID deviceID);
+
+/**
+ * Verifica se o dispositivo suporta controle de hook (off-hook).
+ * @param deviceID ID do dispositivo
+ * @return 1 se suportado, 0 caso contrário
+ */
+int Jabra_IsOffHookSupported(Jabra_DeviceID deviceID);
+
+/**
+ * Verifica se o dispositivo tem busylight.
+ * @param deviceID ID do dispositivo
+ * @return 1 se suportado, 0 caso contrário
+ */
+int Jabra_IsBusylightSupported(Jabra_DeviceID deviceID);
+
+/**
+ * Verifica se o dispositivo suporta hold (chamada em espera).
+ * @param deviceID ID do dispositivo
+ * @return 1 se suportado, 0 caso contrário
+ */
+int Jabra_IsHoldSupported(Jabra_DeviceID deviceID);
+
 // ============================================================================
 // Funções de Bateria
 // ============================================================================